@@ -1,23 +1,41 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/audit"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/credentials"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/ratelimit"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/agents"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/auth"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/dependencies"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/diagnostics"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/dynamic"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/env"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/integrations"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/jobs"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/labels"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/local"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/logs"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/mcpservers"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/modelapis"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/runtime"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/sandboxes"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/scaling"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/serviceaccounts"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools/users"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/truncate"
 	"github.com/joho/godotenv"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -33,6 +51,15 @@ func main() {
 	readOnlyFlag := flag.Bool("read-only", false, "Enable read-only mode")
 	toolsetsFlag := flag.String("toolsets", "all", "Comma-separated list of toolsets to enable")
 	transportFlag := flag.String("transport", "stdio", "Transport mode: stdio (default) or http")
+	configFlag := flag.String("config", "", "Path to a YAML or JSON configuration file")
+	enableToolsFlag := flag.String("enable-tools", "", "Comma-separated glob patterns of tools to expose (e.g. 'list_*,get_*')")
+	disableToolsFlag := flag.String("disable-tools", "", "Comma-separated glob patterns of tools to hide (e.g. 'delete_*,run_sandbox')")
+	auditLogFlag := flag.String("audit-log", "", "Path of the JSONL audit log of write tool calls ('off' to disable)")
+	maxOutputCharsFlag := flag.Int("max-output-chars", 0, "Shorten tool results longer than this many characters (0 for no limit)")
+	outputFormatFlag := flag.String("output-format", "", "Default format of list and get results: text, json, yaml or markdown")
+	dryRunFlag := flag.Bool("dry-run", false, "Preview create/update/delete calls without changing the workspace")
+	allowRevealSecretsFlag := flag.Bool("allow-reveal-secrets", false, "Let tool calls return secrets in clear with revealSecrets (ignored in read-only mode)")
+	dynamicToolsetsFlag := flag.Bool("dynamic-toolsets", false, "Expose only toolset discovery tools and enable toolsets on demand")
 	flag.Parse()
 
 	// Handle version flag (before logger init since it doesn't need logging)
@@ -64,19 +91,125 @@ func main() {
 		logger.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Keep OAuth / device-login tokens fresh for the lifetime of the server
+	credentials.Install(cfg)
+
+	// Apply the configuration file, then let flags override it
+	if *configFlag != "" {
+		if err := cfg.ApplyFile(*configFlag); err != nil {
+			logger.Fatalf("Failed to load configuration file: %v", err)
+		}
+	}
+
 	// Override read-only mode from flag if provided
 	if *readOnlyFlag {
 		cfg.ReadOnly = true
 	}
+	if *enableToolsFlag != "" {
+		cfg.EnableTools = config.ParseList(*enableToolsFlag)
+	}
+	if *disableToolsFlag != "" {
+		cfg.DisableTools = config.ParseList(*disableToolsFlag)
+	}
+	if *dynamicToolsetsFlag {
+		cfg.DynamicToolsets = true
+	}
+	if *auditLogFlag != "" {
+		cfg.AuditLog = *auditLogFlag
+	}
+	if *dryRunFlag {
+		cfg.DryRun = true
+	}
+	if *allowRevealSecretsFlag {
+		cfg.AllowRevealSecrets = true
+	}
+	if *outputFormatFlag != "" {
+		cfg.OutputFormat = *outputFormatFlag
+	}
+	if *maxOutputCharsFlag > 0 {
+		cfg.MaxOutputChars = *maxOutputCharsFlag
+	}
+	outputFormat, err := formatter.ParseFormat(cfg.OutputFormat)
+	if err != nil {
+		logger.Fatalf("Invalid output format: %v", err)
+	}
+
+	// Classify every tool as read or write; in read-only mode write tools are
+	// neither listed nor callable, whatever their toolset does. Every result is
+	// then passed through secret redaction.
+	policy := tools.NewPolicy(cfg.ReadOnly, cfg.AllowRevealSecrets)
+
+	serverOptions := []server.ServerOption{
+		server.WithToolCapabilities(true),
+	}
+
+	// Record every write tool call, including the ones the policy rejects
+	auditLog, err := audit.Init(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to open audit log: %v", err)
+	}
+	if auditLog != nil {
+		defer auditLog.Close()
+		logger.Printf("Recording write tool calls to %s", auditLog.Path())
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(auditLog.Middleware(
+			func(name string) bool {
+				return policy.Access(name) == tools.AccessWrite
+			},
+			func(request mcp.CallToolRequest) bool {
+				return cfg.DryRun || request.GetBool(tools.DryRunArg, false)
+			},
+		)))
+	}
+
+	serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(policy.Middleware))
+
+	// Refuse delete, update and run calls on protected resources, dry runs included
+	if !cfg.Protected.Empty() {
+		guard, err := newGuard(cfg)
+		if err != nil {
+			logger.Fatalf("Failed to configure protected resources: %v", err)
+		}
+		logger.Printf("Protecting resources matching %v and labels %v", cfg.Protected.Names, cfg.Protected.Labels)
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(guard.Middleware))
+	}
+
+	// Rate limit and cap concurrent calls; buckets are dropped when a session ends
+	if !cfg.Limits.Empty() {
+		limiter, err := ratelimit.New(cfg.Limits)
+		if err != nil {
+			logger.Fatalf("Failed to configure limits: %v", err)
+		}
+		hooks := &server.Hooks{}
+		hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+			limiter.Forget(session.SessionID())
+		})
+		serverOptions = append(serverOptions,
+			server.WithHooks(hooks),
+			server.WithToolHandlerMiddleware(limiter.Middleware),
+		)
+	}
+
+	// Innermost: truncation, dry runs, output format, field selection, label
+	// selectors, then redaction, so that results are redacted and projected
+	// while still JSON, converted afterwards and only then shortened
+	serverOptions = append(serverOptions,
+		server.WithToolHandlerMiddleware(tools.TruncationMiddleware(cfg.MaxOutputChars, truncate.Default())),
+		server.WithToolHandlerMiddleware(policy.DryRunMiddleware(cfg.DryRun)),
+		server.WithToolHandlerMiddleware(tools.FormatMiddleware(outputFormat)),
+		server.WithToolHandlerMiddleware(tools.QueryMiddleware),
+		server.WithToolHandlerMiddleware(tools.SelectorMiddleware),
+		server.WithToolHandlerMiddleware(policy.RedactionMiddleware),
+	)
 
 	// Create MCP server
 	mcp := server.NewMCPServer(
 		"blaxel-mcp-server",
 		version,
+		serverOptions...,
 	)
 
 	// Register tools based on enabled toolsets
-	if err := registerTools(mcp, cfg, *toolsetsFlag); err != nil {
+	if err := registerTools(mcp, cfg, policy, *toolsetsFlag); err != nil {
 		logger.Fatalf("Failed to register tools: %v", err)
 	}
 
@@ -94,51 +227,91 @@ func main() {
 	}
 }
 
-func registerTools(mcp *server.MCPServer, cfg *config.Config, toolsets string) error {
-	// Parse toolsets
-	enabledToolsets := config.ParseToolsets(toolsets)
-
-	// Register tools based on enabled toolsets
-	if enabledToolsets["all"] || enabledToolsets["agents"] {
-		agents.RegisterTools(mcp, cfg)
+// newGuard creates the protected resource guard. Labels are read through the
+// shared cache, bypassing it so that a freshly added label is honored.
+func newGuard(cfg *config.Config) (*tools.Guard, error) {
+	sdkClient, err := client.NewSDKClient(cfg)
+	if err != nil {
+		logger.Warnf("Protected labels cannot be checked: %v", err)
 	}
+	cache := client.SharedCache(cfg)
 
-	if enabledToolsets["all"] || enabledToolsets["modelapis"] {
-		modelapis.RegisterTools(mcp, cfg)
-	}
+	return tools.NewGuard(cfg.Protected, func(ctx context.Context, resource, name string) (map[string]string, bool, error) {
+		if sdkClient == nil {
+			return nil, false, fmt.Errorf("SDK client not initialized")
+		}
+		return client.Labels(client.WithRefresh(ctx, true), sdkClient, cache, resource, name)
+	})
+}
 
-	if enabledToolsets["all"] || enabledToolsets["mcpservers"] {
-		mcpservers.RegisterTools(mcp, cfg)
-	}
+// toolset describes a group of tools that can be enabled with --toolsets
+type toolset struct {
+	name        string
+	description string
+	register    func(tools.ToolRegistrar, *config.Config)
+}
 
-	if enabledToolsets["all"] || enabledToolsets["sandboxes"] {
-		sandboxes.RegisterTools(mcp, cfg)
-	}
+// toolsets lists every toolset in registration order
+var toolsets = []toolset{
+	{"auth", "Authentication status of the server", auth.RegisterTools},
+	{"agents", "List, inspect, create, update, roll back and delete agents", agents.RegisterTools},
+	{"modelapis", "Manage model APIs and their provider integrations", modelapis.RegisterTools},
+	{"mcpservers", "Manage MCP servers (functions) and their integrations", mcpservers.RegisterTools},
+	{"sandboxes", "Manage sandboxes", sandboxes.RegisterTools},
+	{"jobs", "Manage batch jobs", jobs.RegisterTools},
+	{"labels", "Add, change and remove labels on agents, models, MCP servers, sandboxes, jobs and integrations", labels.RegisterTools},
+	{"env", "List, set and unset environment variables and secrets of agents, MCP servers, jobs and sandboxes", env.RegisterTools},
+	{"scaling", "Scale agents, MCP servers and jobs: memory, generation, concurrency, replicas and timeout", scaling.RegisterTools},
+	{"dependencies", "Dependency graph of the workspace and the dependents of a resource", dependencies.RegisterTools},
+	{"integrations", "Manage integration connections and browse the MCP Hub", integrations.RegisterTools},
+	{"users", "Manage workspace users", users.RegisterTools},
+	{"serviceaccounts", "Manage service accounts", serviceaccounts.RegisterTools},
+	{"local", "Create, deploy and run Blaxel projects locally", local.RegisterTools},
+	{"logs", "Read and follow the logs of agents, MCP servers, jobs and sandboxes", logs.RegisterTools},
+	{"diagnostics", "Server diagnostics: cache statistics, the audit log and shortened outputs", diagnostics.RegisterTools},
+	{"runtime", "Run, evaluate and benchmark agents, models and MCP servers; run jobs and sandboxes", runtime.RegisterTools},
+}
 
-	if enabledToolsets["all"] || enabledToolsets["jobs"] {
-		jobs.RegisterTools(mcp, cfg)
-	}
+func registerTools(s *server.MCPServer, cfg *config.Config, policy *tools.Policy, toolsetsList string) error {
+	// Parse toolsets
+	enabledToolsets := config.ParseToolsets(toolsetsList)
 
-	if enabledToolsets["all"] || enabledToolsets["integrations"] {
-		integrations.RegisterTools(mcp, cfg)
+	// Every toolset registers through the registry, which applies the
+	// --enable-tools / --disable-tools patterns in one place
+	mcp, err := tools.NewRegistry(s, policy, cfg.EnableTools, cfg.DisableTools, cfg.DynamicToolsets)
+	if err != nil {
+		return err
 	}
 
-	if enabledToolsets["all"] || enabledToolsets["users"] {
-		users.RegisterTools(mcp, cfg)
+	if cfg.DynamicToolsets {
+		// Only the discovery tools are exposed until a toolset is enabled
+		dynamic.RegisterTools(mcp)
 	}
 
-	if enabledToolsets["all"] || enabledToolsets["serviceaccounts"] {
-		serviceaccounts.RegisterTools(mcp, cfg)
-	}
+	for _, ts := range toolsets {
+		selected := enabledToolsets[ts.name] || enabledToolsets["all"]
+		if cfg.DynamicToolsets {
+			// Every toolset can be enabled later; the ones named explicitly start enabled
+			selected = enabledToolsets[ts.name]
+		} else if !selected {
+			continue
+		}
 
-	if enabledToolsets["all"] || enabledToolsets["local"] {
-		local.RegisterTools(mcp, cfg)
+		register := ts.register
+		mcp.AddToolset(ts.name, ts.description, selected, func(r tools.ToolRegistrar) {
+			register(r, cfg)
+		})
 	}
 
-	// Register runtime execution tools (unless in read-only mode)
-	if !cfg.ReadOnly && (enabledToolsets["all"] || enabledToolsets["runtime"]) {
-		runtime.RegisterTools(mcp, cfg)
+	for _, pattern := range mcp.UnmatchedPatterns() {
+		logger.Warnf("Tool pattern '%s' did not match any tool", pattern)
+	}
+	if hidden := mcp.Hidden(); len(hidden) > 0 {
+		logger.Debugf("Hidden tools: %s", strings.Join(hidden, ", "))
 	}
 
+	exposed := mcp.Exposed()
+	logger.Printf("Exposing %d tools: %s", len(exposed), strings.Join(exposed, ", "))
+
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +28,13 @@ type MCPTestClient struct {
 // NewMCPTestClient creates a new test client using the official mcp-go library
 func NewMCPTestClient(t *testing.T, env map[string]string) *MCPTestClient {
 	t.Helper()
+	return NewMCPTestClientWithArgs(t, env)
+}
+
+// NewMCPTestClientWithArgs creates a new test client for a server started with
+// the given command-line arguments
+func NewMCPTestClientWithArgs(t *testing.T, env map[string]string, args ...string) *MCPTestClient {
+	t.Helper()
 
 	// Find the server binary - handle different test locations
 	var serverPath string
@@ -55,7 +63,7 @@ func NewMCPTestClient(t *testing.T, env map[string]string) *MCPTestClient {
 	}
 
 	// Create the MCP client using stdio transport
-	stdioClient, err := client.NewStdioMCPClient(serverPath, envVars)
+	stdioClient, err := client.NewStdioMCPClient(serverPath, envVars, args...)
 	if err != nil {
 		t.Fatalf("Failed to create MCP client: %v", err)
 	}
@@ -116,6 +124,13 @@ func (c *MCPTestClient) Ping() error {
 	return c.client.Ping(c.ctx)
 }
 
+// OnNotification registers a handler for notifications sent by the server.
+// The stdio client is started without Client.Start, so the handler is set on
+// the transport directly.
+func (c *MCPTestClient) OnNotification(handler func(notification mcp.JSONRPCNotification)) {
+	c.client.GetTransport().SetNotificationHandler(handler)
+}
+
 // GetServerCapabilities returns the server capabilities
 func (c *MCPTestClient) GetServerCapabilities() mcp.ServerCapabilities {
 	return c.client.GetServerCapabilities()
@@ -159,6 +174,80 @@ func CheckToolError(result *mcp.CallToolResult) (bool, string) {
 	return false, ""
 }
 
+// ListedTools returns the tools listed by the server, by name
+func ListedTools(t *testing.T, c *MCPTestClient) map[string]mcp.Tool {
+	t.Helper()
+
+	result, err := c.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	tools := make(map[string]mcp.Tool, len(result.Tools))
+	for _, tool := range result.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+// AssertToolArgs checks that a tool is listed and accepts the given arguments
+func AssertToolArgs(t *testing.T, tools map[string]mcp.Tool, name string, args ...string) {
+	t.Helper()
+
+	tool, ok := tools[name]
+	if !ok {
+		t.Errorf("Expected %s to be registered", name)
+		return
+	}
+	for _, arg := range args {
+		if _, ok := tool.InputSchema.Properties[arg]; !ok {
+			t.Errorf("%s should accept %s", name, arg)
+		}
+	}
+}
+
+// ToolErrorCase is a tool call expected to fail with an error containing Expected
+type ToolErrorCase struct {
+	Tool     string
+	Args     map[string]interface{}
+	Expected string
+}
+
+// AssertToolErrors calls each tool and checks that it fails with the expected error
+func AssertToolErrors(t *testing.T, c *MCPTestClient, cases []ToolErrorCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		callResult, err := c.CallTool(tc.Tool, tc.Args)
+		if err != nil {
+			t.Fatalf("Failed to call %s: %v", tc.Tool, err)
+		}
+		if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, tc.Expected) {
+			t.Errorf("Expected an error containing %q for %s %v, got: %s", tc.Expected, tc.Tool, tc.Args, ExtractTextResult(callResult))
+		}
+	}
+}
+
+// AssertReadOnlyTools checks that in read-only mode the read tools are listed
+// and the write tools are not
+func AssertReadOnlyTools(t *testing.T, readTools, writeTools []string) {
+	t.Helper()
+
+	readOnly := NewMCPTestClientWithArgs(t, TestEnv(), "--read-only")
+	defer readOnly.Close()
+
+	tools := ListedTools(t, readOnly)
+	for _, name := range readTools {
+		if _, ok := tools[name]; !ok {
+			t.Errorf("Tool %s should be exposed in read-only mode", name)
+		}
+	}
+	for _, name := range writeTools {
+		if _, ok := tools[name]; ok {
+			t.Errorf("Tool %s should not be exposed in read-only mode", name)
+		}
+	}
+}
+
 // ExtractJSONResult extracts JSON result from tool response
 func ExtractJSONResult(result *mcp.CallToolResult) (map[string]interface{}, error) {
 	if result == nil || len(result.Content) == 0 {
@@ -195,6 +284,21 @@ func ExtractJSONResult(result *mcp.CallToolResult) (map[string]interface{}, erro
 	return nil, fmt.Errorf("no valid JSON content found")
 }
 
+// ExtractTextResult concatenates the text content of a tool response
+func ExtractTextResult(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+
+	var texts []string
+	for _, content := range result.Content {
+		if textContent, ok := mcp.AsTextContent(content); ok {
+			texts = append(texts, textContent.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
 // GenerateRandomTestName generates a random test name with the given prefix
 func GenerateRandomTestName(prefix string) string {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -1,7 +1,14 @@
 package e2e
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func TestServerInitialization(t *testing.T) {
@@ -118,3 +125,1040 @@ func TestReadOnlyMode(t *testing.T) {
 		t.Error("Read tool list_agents should be available in read-only mode")
 	}
 }
+
+func TestToolFiltering(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configFile := "enableTools:\n  - \"list_*\"\ndisableTools:\n  - list_jobs\n"
+	if err := os.WriteFile(configPath, []byte(configFile), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	env := TestEnv()
+	env["BL_MCP_CONFIG"] = configPath
+
+	client := NewMCPTestClient(t, env)
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	hasListAgents := false
+	for _, tool := range result.Tools {
+		if !strings.HasPrefix(tool.Name, "list_") {
+			t.Errorf("Tool %s should be filtered out by enableTools", tool.Name)
+		}
+		if tool.Name == "list_jobs" {
+			t.Error("Tool list_jobs should be filtered out by disableTools")
+		}
+		if tool.Name == "list_agents" {
+			hasListAgents = true
+		}
+	}
+	if !hasListAgents {
+		t.Error("Tool list_agents should be exposed")
+	}
+}
+
+func TestDynamicToolsets(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("dynamicToolsets: true\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	env := TestEnv()
+	env["BL_MCP_CONFIG"] = configPath
+
+	client := NewMCPTestClient(t, env)
+	defer client.Close()
+
+	listChanged := make(chan struct{}, 1)
+	client.OnNotification(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == mcp.MethodNotificationToolsListChanged {
+			select {
+			case listChanged <- struct{}{}:
+			default:
+			}
+		}
+	})
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	expected := map[string]bool{
+		"list_available_toolsets": true,
+		"get_toolset_tools":       true,
+		"enable_toolset":          true,
+	}
+	for _, tool := range result.Tools {
+		if !expected[tool.Name] {
+			t.Errorf("Tool %s should not be exposed before its toolset is enabled", tool.Name)
+		}
+	}
+	if len(result.Tools) != len(expected) {
+		t.Errorf("Expected %d discovery tools, got %d", len(expected), len(result.Tools))
+	}
+
+	callResult, err := client.CallTool("enable_toolset", map[string]interface{}{"toolset": "agents"})
+	if err != nil {
+		t.Fatalf("Failed to call enable_toolset: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Unexpected error from enable_toolset: %s", errorMsg)
+	}
+
+	select {
+	case <-listChanged:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected a tools/list_changed notification after enabling a toolset")
+	}
+
+	result, err = client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	hasListAgents := false
+	for _, tool := range result.Tools {
+		if tool.Name == "list_agents" {
+			hasListAgents = true
+		}
+	}
+	if !hasListAgents {
+		t.Error("Tool list_agents should be exposed after enabling the agents toolset")
+	}
+}
+
+func TestReadOnlyPolicy(t *testing.T) {
+	client := NewMCPTestClientWithArgs(t, TestEnv(), "--read-only")
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	if len(result.Tools) == 0 {
+		t.Fatal("Expected read tools to be listed in read-only mode")
+	}
+
+	// Every listed tool must be classified as read
+	for _, tool := range result.Tools {
+		if tool.Annotations.ReadOnlyHint == nil || !*tool.Annotations.ReadOnlyHint {
+			t.Errorf("Write tool %s should not be listed in read-only mode", tool.Name)
+		}
+	}
+
+	// Write tools must not be callable, even by name: they are either not
+	// registered or refused by the policy
+	for _, name := range []string{"delete_agent", "create_sandbox", "run_job", "local_deploy_directory"} {
+		callResult, err := client.CallTool(name, map[string]interface{}{"name": "e2e-read-only"})
+		if err != nil {
+			if !strings.Contains(err.Error(), "tool not found") {
+				t.Errorf("Write tool %s should be unknown in read-only mode, got: %v", name, err)
+			}
+			continue
+		}
+		if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "not available in read-only mode") {
+			t.Errorf("Write tool %s should not be callable in read-only mode, got: %s", name, ExtractTextResult(callResult))
+		}
+	}
+}
+
+func TestRevealSecretsGate(t *testing.T) {
+	// Without the operator setting, revealSecrets is neither listed nor accepted
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		if _, ok := tool.InputSchema.Properties["revealSecrets"]; ok {
+			t.Errorf("Tool %s should not accept revealSecrets by default", tool.Name)
+		}
+	}
+
+	callResult, err := client.CallTool("login_status", map[string]interface{}{"revealSecrets": true})
+	if err != nil {
+		t.Fatalf("Failed to call login_status: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "--allow-reveal-secrets") {
+		t.Errorf("Expected revealSecrets to be refused, got: %s", ExtractTextResult(callResult))
+	}
+
+	// With it, every tool accepts the argument
+	allowed := NewMCPTestClientWithArgs(t, TestEnv(), "--allow-reveal-secrets")
+	defer allowed.Close()
+
+	result, err = allowed.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		if _, ok := tool.InputSchema.Properties["revealSecrets"]; !ok {
+			t.Errorf("Tool %s should accept revealSecrets with --allow-reveal-secrets", tool.Name)
+		}
+	}
+
+	callResult, err = allowed.CallTool("login_status", map[string]interface{}{"revealSecrets": true})
+	if err != nil {
+		t.Fatalf("Failed to call login_status: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError && strings.Contains(errorMsg, "revealSecrets") {
+		t.Errorf("Expected revealSecrets to be accepted, got: %s", errorMsg)
+	}
+
+	// Read-only mode wins over the setting
+	readOnly := NewMCPTestClientWithArgs(t, TestEnv(), "--allow-reveal-secrets", "--read-only")
+	defer readOnly.Close()
+
+	callResult, err = readOnly.CallTool("login_status", map[string]interface{}{"revealSecrets": true})
+	if err != nil {
+		t.Fatalf("Failed to call login_status: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "read-only") {
+		t.Errorf("Expected revealSecrets to be refused in read-only mode, got: %s", ExtractTextResult(callResult))
+	}
+}
+
+func TestDryRunMode(t *testing.T) {
+	client := NewMCPTestClientWithArgs(t, TestEnv(), "--dry-run")
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+
+	// Mutations that can be previewed advertise the dryRun argument
+	for _, tool := range result.Tools {
+		_, hasDryRun := tool.InputSchema.Properties["dryRun"]
+		wantDryRun := strings.HasPrefix(tool.Name, "create_") || strings.HasPrefix(tool.Name, "delete_")
+		if wantDryRun && !hasDryRun {
+			t.Errorf("Tool %s should accept the dryRun argument", tool.Name)
+		}
+		if strings.HasPrefix(tool.Name, "list_") && hasDryRun {
+			t.Errorf("Read tool %s should not accept the dryRun argument", tool.Name)
+		}
+	}
+
+	// Write tools without a preview are refused rather than executed
+	callResult, err := client.CallTool("run_job", map[string]interface{}{"name": "e2e-dry-run"})
+	if err != nil {
+		t.Fatalf("Failed to call run_job: %v", err)
+	}
+	isError, errorMsg := CheckToolError(callResult)
+	if !isError || !strings.Contains(errorMsg, "dry-run") {
+		t.Errorf("Expected run_job to be refused in dry-run mode, got: %s", ExtractTextResult(callResult))
+	}
+
+	// A create returns the request it would send, and nothing is created
+	sandboxName := GenerateRandomTestName("e2e-dry-run")
+	callResult, err = client.CallTool("create_sandbox", map[string]interface{}{
+		"name":   sandboxName,
+		"memory": float64(2048),
+	})
+	if err != nil {
+		t.Fatalf("Failed to call create_sandbox: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Skipf("Skipping dry-run preview checks: %s", errorMsg)
+	}
+
+	preview, err := ExtractJSONResult(callResult)
+	if err != nil {
+		t.Fatalf("Failed to parse dry-run result: %v", err)
+	}
+	if preview["dryRun"] != true {
+		t.Errorf("Expected dryRun to be true, got: %v", preview["dryRun"])
+	}
+	requests, _ := preview["requests"].([]interface{})
+	if len(requests) != 1 {
+		t.Fatalf("Expected one planned request, got: %v", preview["requests"])
+	}
+	request, _ := requests[0].(map[string]interface{})
+	if request["method"] != "POST" || request["path"] != "/sandboxes" {
+		t.Errorf("Unexpected planned request: %v", request)
+	}
+
+	callResult, err = client.CallTool("get_sandbox", map[string]interface{}{"name": sandboxName})
+	if err != nil {
+		t.Fatalf("Failed to call get_sandbox: %v", err)
+	}
+	if isError, _ := CheckToolError(callResult); !isError {
+		t.Errorf("Sandbox %s should not have been created in dry-run mode", sandboxName)
+	}
+}
+
+func TestDryRunArgument(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	// Dependencies are resolved: deleting a missing resource fails the preview
+	callResult, err := client.CallTool("delete_sandbox", map[string]interface{}{
+		"name":   GenerateRandomTestName("e2e-missing"),
+		"dryRun": true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to call delete_sandbox: %v", err)
+	}
+	if isError, _ := CheckToolError(callResult); !isError {
+		t.Errorf("Expected a dry-run delete of a missing sandbox to fail, got: %s", ExtractTextResult(callResult))
+	}
+}
+
+func TestProtectedResources(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := "protected:\n  names:\n    - \"e2e-protected-*\"\n"
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	client := NewMCPTestClientWithArgs(t, TestEnv(), "--config", configPath)
+	defer client.Close()
+
+	calls := []struct {
+		tool      string
+		arguments map[string]interface{}
+	}{
+		{"delete_agent", map[string]interface{}{"name": "e2e-protected-agent"}},
+		{"delete_sandbox", map[string]interface{}{"name": "e2e-protected-sandbox", "dryRun": true}},
+		{"delete_job", map[string]interface{}{"id": "e2e-protected-job"}},
+		{"run_agent", map[string]interface{}{"name": "e2e-protected-agent", "message": "hello"}},
+		{"local_run_deployed_resource", map[string]interface{}{"resourceType": "agent", "resourceName": "e2e-protected-agent"}},
+	}
+
+	for _, call := range calls {
+		t.Run(call.tool, func(t *testing.T) {
+			callResult, err := client.CallTool(call.tool, call.arguments)
+			if err != nil {
+				t.Fatalf("Failed to call %s: %v", call.tool, err)
+			}
+
+			isError, errorMsg := CheckToolError(callResult)
+			if !isError || !strings.Contains(errorMsg, "policy error") || !strings.Contains(errorMsg, "e2e-protected-*") {
+				t.Errorf("Expected a policy error from %s, got: %s", call.tool, ExtractTextResult(callResult))
+			}
+		})
+	}
+
+	// Resources outside the patterns are not affected by the guard
+	callResult, err := client.CallTool("delete_agent", map[string]interface{}{"name": "e2e-unprotected-agent"})
+	if err != nil {
+		t.Fatalf("Failed to call delete_agent: %v", err)
+	}
+	if _, errorMsg := CheckToolError(callResult); strings.Contains(errorMsg, "policy error") {
+		t.Errorf("Unprotected agent should not be refused by the policy: %s", errorMsg)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := `limits:
+  tools:
+    - tool: local_quick_start_guide
+      perMinute: 1
+      burst: 2
+`
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	client := NewMCPTestClientWithArgs(t, TestEnv(), "--config", configPath)
+	defer client.Close()
+
+	// The burst allows two calls, the third one has to wait for a refill
+	for i := 0; i < 2; i++ {
+		callResult, err := client.CallTool("local_quick_start_guide", map[string]interface{}{"resourceType": "agent"})
+		if err != nil {
+			t.Fatalf("Failed to call local_quick_start_guide: %v", err)
+		}
+		if isError, errorMsg := CheckToolError(callResult); isError {
+			t.Fatalf("Call %d should be within the burst: %s", i+1, errorMsg)
+		}
+	}
+
+	callResult, err := client.CallTool("local_quick_start_guide", map[string]interface{}{"resourceType": "agent"})
+	if err != nil {
+		t.Fatalf("Failed to call local_quick_start_guide: %v", err)
+	}
+	isError, errorMsg := CheckToolError(callResult)
+	if !isError || !strings.Contains(errorMsg, "rate limit exceeded") || !strings.Contains(errorMsg, "retry after") {
+		t.Errorf("Expected a rate limit error with a retry delay, got: %s", ExtractTextResult(callResult))
+	}
+
+	// Other tools are not limited
+	callResult, err = client.CallTool("login_status", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Failed to call login_status: %v", err)
+	}
+	if _, errorMsg := CheckToolError(callResult); strings.Contains(errorMsg, "rate limit") {
+		t.Errorf("login_status should not be rate limited: %s", errorMsg)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		_, hasFormat := tool.InputSchema.Properties["format"]
+		isListOrGet := strings.HasPrefix(tool.Name, "list_") || strings.HasPrefix(tool.Name, "get_")
+		if isListOrGet != hasFormat {
+			t.Errorf("Tool %s: format argument present=%v, want %v", tool.Name, hasFormat, isListOrGet)
+		}
+	}
+
+	callResult, err := client.CallTool("get_cache_stats", map[string]interface{}{"format": "yaml"})
+	if err != nil {
+		t.Fatalf("Failed to call get_cache_stats: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Unexpected error: %s", errorMsg)
+	}
+	text := ExtractTextResult(callResult)
+	if strings.HasPrefix(strings.TrimSpace(text), "{") || !strings.Contains(text, "ttl:") {
+		t.Errorf("Expected YAML output, got: %s", text)
+	}
+
+	callResult, err = client.CallTool("get_cache_stats", map[string]interface{}{"format": "markdown"})
+	if err != nil {
+		t.Fatalf("Failed to call get_cache_stats: %v", err)
+	}
+	if text := ExtractTextResult(callResult); !strings.Contains(text, "| Field | Value |") {
+		t.Errorf("Expected a markdown table, got: %s", text)
+	}
+
+	callResult, err = client.CallTool("get_cache_stats", map[string]interface{}{"format": "xml"})
+	if err != nil {
+		t.Fatalf("Failed to call get_cache_stats: %v", err)
+	}
+	if isError, _ := CheckToolError(callResult); !isError {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestFieldSelection(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		_, hasFields := tool.InputSchema.Properties["fields"]
+		_, hasSortBy := tool.InputSchema.Properties["sortBy"]
+		isList := strings.HasPrefix(tool.Name, "list_")
+		isListOrGet := isList || strings.HasPrefix(tool.Name, "get_")
+		if isListOrGet != hasFields {
+			t.Errorf("Tool %s: fields argument present=%v, want %v", tool.Name, hasFields, isListOrGet)
+		}
+		if isList != hasSortBy {
+			t.Errorf("Tool %s: sortBy argument present=%v, want %v", tool.Name, hasSortBy, isList)
+		}
+	}
+
+	callResult, err := client.CallTool("get_cache_stats", map[string]interface{}{"fields": "ttl"})
+	if err != nil {
+		t.Fatalf("Failed to call get_cache_stats: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Unexpected error: %s", errorMsg)
+	}
+	var stats map[string]interface{}
+	if err := json.Unmarshal([]byte(ExtractTextResult(callResult)), &stats); err != nil {
+		t.Fatalf("Expected JSON output: %v", err)
+	}
+	if _, ok := stats["ttl"]; !ok || len(stats) != 1 {
+		t.Errorf("Expected only the ttl field, got: %v", stats)
+	}
+
+	callResult, err = client.CallTool("list_agents", map[string]interface{}{"sortBy": "metadata.name", "order": "sideways"})
+	if err != nil {
+		t.Fatalf("Failed to call list_agents: %v", err)
+	}
+	if isError, _ := CheckToolError(callResult); !isError {
+		t.Error("Expected an error for an unknown order")
+	}
+}
+
+func TestOutputTruncation(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	callResult, err := client.CallTool("get_cache_stats", map[string]interface{}{"maxOutputChars": 60})
+	if err != nil {
+		t.Fatalf("Failed to call get_cache_stats: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Unexpected error: %s", errorMsg)
+	}
+	if len(callResult.Content) != 2 {
+		t.Fatalf("Expected the shortened result and a note, got %d contents", len(callResult.Content))
+	}
+	shortened, _ := mcp.AsTextContent(callResult.Content[0])
+	note, _ := mcp.AsTextContent(callResult.Content[1])
+	if len([]rune(shortened.Text)) > 60 {
+		t.Errorf("Expected at most 60 characters, got %d", len([]rune(shortened.Text)))
+	}
+	if !strings.Contains(note.Text, "read_output") {
+		t.Fatalf("Expected the note to point at read_output, got: %s", note.Text)
+	}
+
+	id := strings.Split(strings.SplitN(note.Text, "'", 2)[1], "'")[0]
+	callResult, err = client.CallTool("read_output", map[string]interface{}{"id": id, "length": 100000})
+	if err != nil {
+		t.Fatalf("Failed to call read_output: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Unexpected error: %s", errorMsg)
+	}
+	var chunk struct {
+		Total int    `json:"total"`
+		Text  string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(ExtractTextResult(callResult)), &chunk); err != nil {
+		t.Fatalf("Expected JSON output: %v", err)
+	}
+	if chunk.Total <= 60 || !strings.Contains(chunk.Text, "\"ttl\"") {
+		t.Errorf("Expected the full output, got: %+v", chunk)
+	}
+}
+
+func TestLabelSelectors(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	selectable := map[string]bool{
+		"list_agents":       true,
+		"list_model_apis":   true,
+		"list_mcp_servers":  true,
+		"list_sandboxes":    true,
+		"list_jobs":         true,
+		"list_integrations": false,
+	}
+	for _, tool := range result.Tools {
+		hasStatus, isSelectable := selectable[tool.Name]
+		_, hasSelector := tool.InputSchema.Properties["labelSelector"]
+		if hasSelector != isSelectable {
+			t.Errorf("Tool %s: labelSelector argument present=%v, want %v", tool.Name, hasSelector, isSelectable)
+		}
+		if _, ok := tool.InputSchema.Properties["status"]; isSelectable && ok != hasStatus {
+			t.Errorf("Tool %s: status argument present=%v, want %v", tool.Name, ok, hasStatus)
+		}
+	}
+
+	callResult, err := client.CallTool("list_agents", map[string]interface{}{"labelSelector": "team in (ml,search"})
+	if err != nil {
+		t.Fatalf("Failed to call list_agents: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "invalid label selector") {
+		t.Errorf("Expected an invalid label selector error, got: %s", ExtractTextResult(callResult))
+	}
+}
+
+func TestLabelTools(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	tools := ListedTools(t, client)
+	AssertToolArgs(t, tools, "set_labels", "resourceType", "name", "labels", "dryRun")
+	AssertToolArgs(t, tools, "remove_labels", "resourceType", "name", "keys", "dryRun")
+
+	AssertToolErrors(t, client, []ToolErrorCase{
+		{"set_labels", map[string]interface{}{"resourceType": "volume", "name": "test", "labels": map[string]interface{}{"env": "prod"}}, "unknown resource type"},
+		{"remove_labels", map[string]interface{}{"resourceType": "agent", "name": "test"}, "key"},
+	})
+
+	AssertReadOnlyTools(t, nil, []string{"set_labels", "remove_labels"})
+}
+
+func TestAgentWriteTools(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	found := map[string]bool{}
+	for _, tool := range result.Tools {
+		if tool.Name != "create_agent" && tool.Name != "update_agent" {
+			continue
+		}
+		found[tool.Name] = true
+		for _, arg := range []string{"image", "memory", "generation", "envs", "integrationConnections", "labels", "waitForCompletion", "dryRun"} {
+			if _, ok := tool.InputSchema.Properties[arg]; !ok {
+				t.Errorf("Tool %s should accept %s", tool.Name, arg)
+			}
+		}
+	}
+	if !found["create_agent"] || !found["update_agent"] {
+		t.Fatalf("Expected create_agent and update_agent, got %v", found)
+	}
+
+	callResult, err := client.CallTool("create_agent", map[string]interface{}{
+		"name": "test-agent",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call create_agent: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "image") {
+		t.Errorf("Expected an error for a missing image, got: %s", ExtractTextResult(callResult))
+	}
+
+	callResult, err = client.CallTool("update_agent", map[string]interface{}{
+		"name":       "test-agent",
+		"generation": "mk9",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call update_agent: %v", err)
+	}
+	if isError, _ := CheckToolError(callResult); !isError {
+		t.Error("Expected an error for an unknown generation")
+	}
+
+	// Creating and updating agents is not possible in read-only mode
+	env := TestEnv()
+	env["BL_READ_ONLY"] = "true"
+	readOnlyClient := NewMCPTestClient(t, env)
+	defer readOnlyClient.Close()
+
+	result, err = readOnlyClient.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		if tool.Name == "create_agent" || tool.Name == "update_agent" {
+			t.Errorf("Tool %s should not be exposed in read-only mode", tool.Name)
+		}
+	}
+}
+
+func TestAgentRevisionTools(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	found := map[string]bool{}
+	for _, tool := range result.Tools {
+		switch tool.Name {
+		case "list_agent_revisions":
+			found[tool.Name] = true
+		case "rollback_agent":
+			found[tool.Name] = true
+			for _, arg := range []string{"revision", "waitForCompletion", "dryRun"} {
+				if _, ok := tool.InputSchema.Properties[arg]; !ok {
+					t.Errorf("Tool %s should accept %s", tool.Name, arg)
+				}
+			}
+		}
+	}
+	if !found["list_agent_revisions"] || !found["rollback_agent"] {
+		t.Fatalf("Expected list_agent_revisions and rollback_agent, got %v", found)
+	}
+
+	for _, tool := range []string{"list_agent_revisions", "rollback_agent"} {
+		callResult, err := client.CallTool(tool, map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("Failed to call %s: %v", tool, err)
+		}
+		if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "name is required") {
+			t.Errorf("Expected %s to require a name, got: %s", tool, ExtractTextResult(callResult))
+		}
+	}
+
+	// Rolling back is a write and is not exposed in read-only mode
+	env := TestEnv()
+	env["BL_READ_ONLY"] = "true"
+	readOnlyClient := NewMCPTestClient(t, env)
+	defer readOnlyClient.Close()
+
+	result, err = readOnlyClient.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	found = map[string]bool{}
+	for _, tool := range result.Tools {
+		found[tool.Name] = true
+	}
+	if !found["list_agent_revisions"] {
+		t.Error("list_agent_revisions should be exposed in read-only mode")
+	}
+	if found["rollback_agent"] {
+		t.Error("rollback_agent should not be exposed in read-only mode")
+	}
+}
+
+func TestResourceLogs(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	var found bool
+	for _, tool := range result.Tools {
+		if tool.Name != "get_resource_logs" {
+			continue
+		}
+		found = true
+		for _, arg := range []string{"since", "until", "severity", "search", "limit", "follow", "followSeconds"} {
+			if _, ok := tool.InputSchema.Properties[arg]; !ok {
+				t.Errorf("get_resource_logs should accept %s", arg)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected get_resource_logs to be registered")
+	}
+
+	// Invalid arguments are rejected before the API is called
+	cases := []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"resourceType": "model", "name": "test"}, "unknown resource type"},
+		{map[string]interface{}{"resourceType": "agent", "name": "test", "since": "yesterday"}, "invalid since"},
+		{map[string]interface{}{"resourceType": "agent", "name": "test", "severity": "LOUD"}, "unknown severity"},
+		{map[string]interface{}{"resourceType": "agent", "name": "test", "limit": 5000}, "limit must be"},
+		{map[string]interface{}{"resourceType": "agent", "name": "test", "follow": true, "followSeconds": 3600}, "followSeconds must be"},
+	}
+	for _, tc := range cases {
+		callResult, err := client.CallTool("get_resource_logs", tc.args)
+		if err != nil {
+			t.Fatalf("Failed to call get_resource_logs: %v", err)
+		}
+		if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, tc.expected) {
+			t.Errorf("Expected an error containing %q for %v, got: %s", tc.expected, tc.args, ExtractTextResult(callResult))
+		}
+	}
+}
+
+func TestAgentConversations(t *testing.T) {
+	logDir := t.TempDir()
+	env := TestEnv()
+	env["LOG_DIR"] = logDir
+	env["BL_WORKSPACE"] = "e2e-conversations"
+
+	// A transcript recorded by earlier run_agent calls
+	threadDir := filepath.Join(logDir, "conversations", "e2e-conversations")
+	if err := os.MkdirAll(threadDir, 0700); err != nil {
+		t.Fatalf("Failed to create transcript directory: %v", err)
+	}
+	transcript := `{"time":"2024-01-02T15:04:05Z","agent":"helper","role":"user","content":"hello"}
+{"time":"2024-01-02T15:04:06Z","agent":"helper","role":"agent","content":"hi there"}
+{"time":"2024-01-02T15:05:05Z","agent":"helper","role":"user","content":"what did I say?"}
+{"time":"2024-01-02T15:05:06Z","agent":"helper","role":"agent","content":"you said hello"}
+`
+	if err := os.WriteFile(filepath.Join(threadDir, "thread-1.jsonl"), []byte(transcript), 0600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	client := NewMCPTestClient(t, env)
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		if tool.Name == "run_agent" {
+			if _, ok := tool.InputSchema.Properties["threadId"]; !ok {
+				t.Error("run_agent should accept threadId")
+			}
+		}
+	}
+
+	callResult, err := client.CallTool("get_agent_conversation", map[string]interface{}{
+		"name": "helper",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call get_agent_conversation: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Failed to list threads: %s", errorMsg)
+	}
+	if text := ExtractTextResult(callResult); !strings.Contains(text, "thread-1") {
+		t.Errorf("Expected thread-1 in the threads of helper, got: %s", text)
+	}
+
+	callResult, err = client.CallTool("get_agent_conversation", map[string]interface{}{
+		"name":     "helper",
+		"threadId": "thread-1",
+		"limit":    2,
+		"format":   "json",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call get_agent_conversation: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Failed to replay thread: %s", errorMsg)
+	}
+	var thread struct {
+		TotalTurns int `json:"totalTurns"`
+		Turns      []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"turns"`
+	}
+	if err := json.Unmarshal([]byte(ExtractTextResult(callResult)), &thread); err != nil {
+		t.Fatalf("Failed to parse conversation: %v", err)
+	}
+	if thread.TotalTurns != 4 || len(thread.Turns) != 2 || thread.Turns[1].Content != "you said hello" {
+		t.Errorf("Expected the last 2 of 4 turns, got %+v", thread)
+	}
+
+	// Threads belong to the agent they were started with
+	callResult, err = client.CallTool("get_agent_conversation", map[string]interface{}{
+		"name":     "other",
+		"threadId": "thread-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call get_agent_conversation: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "belongs to agent 'helper'") {
+		t.Errorf("Expected an error for another agent's thread, got: %s", ExtractTextResult(callResult))
+	}
+
+	callResult, err = client.CallTool("run_agent", map[string]interface{}{
+		"name":     "helper",
+		"message":  "hello",
+		"threadId": "../escape",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call run_agent: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, "invalid threadId") {
+		t.Errorf("Expected an error for an invalid threadId, got: %s", ExtractTextResult(callResult))
+	}
+}
+
+func TestAgentEvaluations(t *testing.T) {
+	logDir := t.TempDir()
+	env := TestEnv()
+	env["LOG_DIR"] = logDir
+	env["BL_WORKSPACE"] = "e2e-evaluations"
+
+	// Two runs saved by earlier evaluate_agent calls
+	runDir := filepath.Join(logDir, "evaluations", "e2e-evaluations")
+	if err := os.MkdirAll(runDir, 0700); err != nil {
+		t.Fatalf("Failed to create evaluation directory: %v", err)
+	}
+	runs := map[string]string{
+		"eval-20240102-150405-0000000a": `{"runId":"eval-20240102-150405-0000000a","agent":"helper","cases":3,"passed":2,"score":0.667,"avgLatencyMs":100,
+"results":[{"id":"a","passed":true,"latencyMs":100},{"id":"b","passed":true,"latencyMs":100},{"id":"c","passed":false,"latencyMs":100}]}`,
+		"eval-20240103-150405-0000000b": `{"runId":"eval-20240103-150405-0000000b","agent":"helper","cases":3,"passed":2,"score":0.667,"avgLatencyMs":200,
+"results":[{"id":"a","passed":false,"latencyMs":400},{"id":"c","passed":true,"latencyMs":100},{"id":"d","passed":true,"latencyMs":100}]}`,
+	}
+	for id, run := range runs {
+		if err := os.WriteFile(filepath.Join(runDir, id+".json"), []byte(run), 0600); err != nil {
+			t.Fatalf("Failed to write evaluation: %v", err)
+		}
+	}
+
+	client := NewMCPTestClient(t, env)
+	defer client.Close()
+
+	callResult, err := client.CallTool("compare_evaluations", map[string]interface{}{
+		"baseline":  "eval-20240102-150405-0000000a",
+		"candidate": "eval-20240103-150405-0000000b",
+		"format":    "json",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call compare_evaluations: %v", err)
+	}
+	if isError, errorMsg := CheckToolError(callResult); isError {
+		t.Fatalf("Failed to compare evaluations: %s", errorMsg)
+	}
+	var comparison struct {
+		Regressions []string `json:"regressions"`
+		Fixes       []string `json:"fixes"`
+		Added       []string `json:"added"`
+		Removed     []string `json:"removed"`
+		Latency     []struct {
+			ID      string `json:"id"`
+			DeltaMs int64  `json:"deltaMs"`
+		} `json:"latency"`
+	}
+	if err := json.Unmarshal([]byte(ExtractTextResult(callResult)), &comparison); err != nil {
+		t.Fatalf("Failed to parse comparison: %v", err)
+	}
+	got := strings.Join([]string{
+		strings.Join(comparison.Regressions, ","),
+		strings.Join(comparison.Fixes, ","),
+		strings.Join(comparison.Added, ","),
+		strings.Join(comparison.Removed, ","),
+	}, " ")
+	if got != "a c d b" {
+		t.Errorf("Unexpected comparison: %+v", comparison)
+	}
+	if len(comparison.Latency) == 0 || comparison.Latency[0].ID != "a" || comparison.Latency[0].DeltaMs != 300 {
+		t.Errorf("Expected the slowdown of a first, got %+v", comparison.Latency)
+	}
+
+	// Datasets are validated before the agent is called
+	cases := []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"name": "helper"}, "a dataset is required"},
+		{map[string]interface{}{"name": "helper", "cases": `[{"input":"hi"}]`}, "at least one check"},
+		{map[string]interface{}{"name": "helper", "cases": `{"input":"hi","checks":[{"type":"fuzzy"}]}`}, "unknown check type"},
+		{map[string]interface{}{"name": "helper", "cases": `{"input":"hi","checks":[{"type":"regex","pattern":"("}]}`}, "invalid regex"},
+		{map[string]interface{}{"name": "helper", "datasetPath": filepath.Join(logDir, "missing.jsonl")}, "failed to read dataset"},
+		{map[string]interface{}{"name": "helper", "cases": `{"input":"hi","expected":"hello"}`, "concurrency": 100}, "concurrency must be"},
+	}
+	for _, tc := range cases {
+		callResult, err := client.CallTool("evaluate_agent", tc.args)
+		if err != nil {
+			t.Fatalf("Failed to call evaluate_agent: %v", err)
+		}
+		if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, tc.expected) {
+			t.Errorf("Expected an error containing %q for %v, got: %s", tc.expected, tc.args, ExtractTextResult(callResult))
+		}
+	}
+}
+
+func TestBenchmarkResource(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	var found bool
+	for _, tool := range result.Tools {
+		if tool.Name != "benchmark_resource" {
+			continue
+		}
+		found = true
+		for _, arg := range []string{"resourceType", "requests", "concurrency", "durationSeconds", "timeoutSeconds", "body", "path"} {
+			if _, ok := tool.InputSchema.Properties[arg]; !ok {
+				t.Errorf("benchmark_resource should accept %s", arg)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected benchmark_resource to be registered")
+	}
+
+	// Loads over the safety limits are rejected before any request is sent
+	cases := []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"resourceType": "sandbox", "name": "test"}, "unknown resource type"},
+		{map[string]interface{}{"resourceType": "model", "name": "test"}, "body is required"},
+		{map[string]interface{}{"resourceType": "agent", "name": "test", "requests": 100000}, "requests must be between"},
+		{map[string]interface{}{"resourceType": "agent", "name": "test", "concurrency": 500}, "concurrency must be between"},
+		{map[string]interface{}{"resourceType": "function", "name": "test", "durationSeconds": 3600}, "duration must be at most"},
+	}
+	for _, tc := range cases {
+		callResult, err := client.CallTool("benchmark_resource", tc.args)
+		if err != nil {
+			t.Fatalf("Failed to call benchmark_resource: %v", err)
+		}
+		if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, tc.expected) {
+			t.Errorf("Expected an error containing %q for %v, got: %s", tc.expected, tc.args, ExtractTextResult(callResult))
+		}
+	}
+}
+
+func TestEnvTools(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	tools := ListedTools(t, client)
+	AssertToolArgs(t, tools, "list_env", "resourceType", "name")
+	AssertToolArgs(t, tools, "set_env", "resourceType", "name", "envs", "secrets", "plaintext", "waitForCompletion", "dryRun")
+	AssertToolArgs(t, tools, "unset_env", "resourceType", "name", "names", "waitForCompletion", "dryRun")
+
+	// Invalid arguments are rejected before the API is called
+	AssertToolErrors(t, client, []ToolErrorCase{
+		{"set_env", map[string]interface{}{"resourceType": "agent", "name": "test", "envs": map[string]interface{}{}}, "at least one variable"},
+		{"set_env", map[string]interface{}{"resourceType": "agent", "name": "test", "envs": map[string]interface{}{"BAD NAME": "x"}}, "invalid variable name"},
+		{"set_env", map[string]interface{}{"resourceType": "agent", "name": "test", "envs": map[string]interface{}{"A": "x"}, "secrets": []string{"B"}}, "not one of the variables set"},
+		{"set_env", map[string]interface{}{"resourceType": "agent", "name": "test", "envs": map[string]interface{}{"A": "x"}, "secrets": []string{"A"}, "plaintext": []string{"A"}}, "both a secret and plaintext"},
+		{"unset_env", map[string]interface{}{"resourceType": "agent", "name": "test", "names": []string{}}, "at least one variable name"},
+	})
+
+	AssertReadOnlyTools(t, []string{"list_env"}, []string{"set_env", "unset_env"})
+}
+
+func TestScaleResource(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	tools := ListedTools(t, client)
+	AssertToolArgs(t, tools, "scale_resource", "memory", "generation", "maxConcurrentTasks", "minReplicas", "maxReplicas", "timeout", "waitForCompletion", "dryRun")
+
+	// Settings outside the allowed tiers are rejected before the API is called
+	AssertToolErrors(t, client, []ToolErrorCase{
+		{"scale_resource", map[string]interface{}{"resourceType": "sandbox", "name": "test", "memory": 2048}, "unknown resource type"},
+		{"scale_resource", map[string]interface{}{"resourceType": "agent", "name": "test"}, "at least one setting"},
+		{"scale_resource", map[string]interface{}{"resourceType": "agent", "name": "test", "memory": 3000}, "memory must be one of"},
+		{"scale_resource", map[string]interface{}{"resourceType": "agent", "name": "test", "generation": "mk1"}, "generation must be one of"},
+		{"scale_resource", map[string]interface{}{"resourceType": "function", "name": "test", "minReplicas": 5, "maxReplicas": 2}, "must not exceed maxReplicas"},
+		{"scale_resource", map[string]interface{}{"resourceType": "job", "name": "test", "maxReplicas": 2}, "jobs have no replicas"},
+		{"scale_resource", map[string]interface{}{"resourceType": "job", "name": "test", "timeout": 0}, "timeout must be between"},
+	})
+
+	AssertReadOnlyTools(t, nil, []string{"scale_resource"})
+}
+
+func TestDependencyTools(t *testing.T) {
+	client := NewMCPTestClient(t, TestEnv())
+	defer client.Close()
+
+	result, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	found := map[string]bool{}
+	for _, tool := range result.Tools {
+		found[tool.Name] = true
+	}
+	for _, name := range []string{"get_dependency_graph", "get_dependents"} {
+		if !found[name] {
+			t.Errorf("Expected %s to be registered", name)
+		}
+	}
+
+	// The resource is checked before the workspace is listed
+	cases := []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"resourceType": "volume", "name": "test"}, "unknown resource type"},
+		{map[string]interface{}{"resourceType": "integration"}, "resource name is required"},
+	}
+	for _, tc := range cases {
+		callResult, err := client.CallTool("get_dependents", tc.args)
+		if err != nil {
+			t.Fatalf("Failed to call get_dependents: %v", err)
+		}
+		if isError, errorMsg := CheckToolError(callResult); !isError || !strings.Contains(errorMsg, tc.expected) {
+			t.Errorf("Expected an error containing %q for %v, got: %s", tc.expected, tc.args, ExtractTextResult(callResult))
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/e2e"
+)
+
+func TestAuthTools(t *testing.T) {
+	client := e2e.NewMCPTestClient(t, e2e.TestEnv())
+	defer client.Close()
+
+	t.Run("login_status", func(t *testing.T) {
+		result, err := client.CallTool("login_status", map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("Failed to call login_status: %v", err)
+		}
+
+		isError, errorMsg := e2e.CheckToolError(result)
+		if isError {
+			t.Fatalf("Unexpected error from login_status: %s", errorMsg)
+		}
+
+		resp, err := e2e.ExtractJSONResult(result)
+		if err != nil {
+			t.Fatalf("Failed to parse login_status result: %v", err)
+		}
+
+		method, _ := resp["method"].(string)
+		if method != "api_key" && method != "device_login" && method != "client_credentials" {
+			t.Errorf("Unexpected authentication method: %v", resp["method"])
+		}
+
+		if workspace, _ := resp["workspace"].(string); strings.TrimSpace(workspace) == "" {
+			t.Error("Expected login_status to report the workspace")
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/e2e"
+)
+
+func TestDiagnosticsTools(t *testing.T) {
+	client := e2e.NewMCPTestClient(t, e2e.TestEnv())
+	defer client.Close()
+
+	t.Run("get_cache_stats counts hits and refreshes", func(t *testing.T) {
+		for _, args := range []map[string]interface{}{
+			{},
+			{},
+			{"refresh": true},
+		} {
+			result, err := client.CallTool("list_agents", args)
+			if err != nil {
+				t.Fatalf("Failed to call list_agents: %v", err)
+			}
+			if isError, errorMsg := e2e.CheckToolError(result); isError {
+				t.Skipf("list_agents unavailable: %s", errorMsg)
+			}
+		}
+
+		result, err := client.CallTool("get_cache_stats", map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("Failed to call get_cache_stats: %v", err)
+		}
+
+		isError, errorMsg := e2e.CheckToolError(result)
+		if isError {
+			t.Fatalf("Unexpected error from get_cache_stats: %s", errorMsg)
+		}
+
+		resp, err := e2e.ExtractJSONResult(result)
+		if err != nil {
+			t.Fatalf("Failed to parse get_cache_stats result: %v", err)
+		}
+
+		if enabled, _ := resp["enabled"].(bool); !enabled {
+			t.Skip("Response cache is disabled")
+		}
+
+		operations, ok := resp["operations"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected operations in cache stats, got %v", resp)
+		}
+
+		stats, ok := operations["agents.list"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected agents.list stats, got %v", operations)
+		}
+
+		if hits, _ := stats["hits"].(float64); hits < 1 {
+			t.Errorf("Expected at least one cache hit for list_agents, got %v", stats["hits"])
+		}
+		if bypassed, _ := stats["bypassed"].(float64); bypassed < 1 {
+			t.Errorf("Expected refresh to bypass the cache, got %v", stats["bypassed"])
+		}
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	env := e2e.TestEnv()
+	env["BL_AUDIT_LOG"] = filepath.Join(t.TempDir(), "audit.jsonl")
+
+	client := e2e.NewMCPTestClient(t, env)
+	defer client.Close()
+
+	agentName := e2e.GenerateRandomTestName("audit-missing-agent")
+
+	// A write call is recorded whatever its outcome
+	if _, err := client.CallTool("delete_agent", map[string]interface{}{"name": agentName}); err != nil {
+		t.Fatalf("Failed to call delete_agent: %v", err)
+	}
+
+	// Read calls are not recorded
+	if _, err := client.CallTool("list_agents", map[string]interface{}{}); err != nil {
+		t.Fatalf("Failed to call list_agents: %v", err)
+	}
+
+	result, err := client.CallTool("list_audit_events", map[string]interface{}{
+		"since": "1h",
+	})
+	if err != nil {
+		t.Fatalf("Failed to call list_audit_events: %v", err)
+	}
+
+	if isError, errorMsg := e2e.CheckToolError(result); isError {
+		t.Fatalf("Unexpected error from list_audit_events: %s", errorMsg)
+	}
+
+	var events []map[string]interface{}
+	if err := json.Unmarshal([]byte(e2e.ExtractTextResult(result)), &events); err != nil {
+		t.Fatalf("Failed to parse list_audit_events result: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("Expected exactly one audit event, got %d: %v", len(events), events)
+	}
+
+	event := events[0]
+	if event["tool"] != "delete_agent" {
+		t.Errorf("Expected delete_agent event, got %v", event["tool"])
+	}
+	if args, _ := event["arguments"].(map[string]interface{}); args["name"] != agentName {
+		t.Errorf("Expected arguments to be recorded, got %v", event["arguments"])
+	}
+	if clientInfo, _ := event["client"].(map[string]interface{}); clientInfo["name"] != "Integration Test Client" {
+		t.Errorf("Expected client info from initialize, got %v", event["client"])
+	}
+	if outcome := event["outcome"]; outcome != "success" && outcome != "error" {
+		t.Errorf("Unexpected outcome %v", outcome)
+	}
+}
@@ -74,6 +74,10 @@ func TestIntegrationsTools(t *testing.T) {
 				t.Fatalf("Unexpected error from list_integrations: %s", errorMsg)
 			}
 
+			if openaiAPIKey != "" && strings.Contains(e2e.ExtractTextResult(result), openaiAPIKey) {
+				t.Error("list_integrations should not expose secret values")
+			}
+
 			t.Logf("Successfully listed integrations")
 		})
 
@@ -100,6 +104,14 @@ func TestIntegrationsTools(t *testing.T) {
 				t.Fatalf("Unexpected error from get_integration: %s", errorMsg)
 			}
 
+			text := e2e.ExtractTextResult(result)
+			if openaiAPIKey != "" && strings.Contains(text, openaiAPIKey) {
+				t.Error("get_integration should not expose secret values")
+			}
+			if openaiAPIKey != "" && !strings.Contains(text, "apiKey") {
+				t.Error("get_integration should keep secret key names")
+			}
+
 			t.Logf("Successfully retrieved integration: %s", testIntegrationName)
 		})
 
@@ -0,0 +1,301 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Outcomes recorded for a tool call
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// DefaultFileName is the audit file created in the log directory when no
+// path is configured
+const DefaultFileName = "mcp-audit.jsonl"
+
+// maxErrorLength bounds the error message stored for failed calls
+const maxErrorLength = 500
+
+// Client identifies the MCP client that made a call, as sent in initialize
+type Client struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Event is one line of the audit file
+type Event struct {
+	Time       time.Time              `json:"time"`
+	Tool       string                 `json:"tool"`
+	Arguments  map[string]interface{} `json:"arguments,omitempty"`
+	Workspace  string                 `json:"workspace"`
+	SessionID  string                 `json:"sessionId,omitempty"`
+	Client     *Client                `json:"client,omitempty"`
+	DryRun     bool                   `json:"dryRun,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMs int64                  `json:"durationMs"`
+}
+
+// Filter selects events read back from the audit file
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	Tool    string // glob pattern, e.g. delete_*
+	Outcome string
+	Limit   int // most recent events to keep; 0 keeps all
+}
+
+// Log appends audit events to a JSONL file. The file is only ever appended to.
+type Log struct {
+	mu        sync.Mutex
+	path      string
+	workspace string
+	file      *os.File
+}
+
+var (
+	// Default audit log instance
+	defaultLog *Log
+)
+
+// Init opens the audit file configured in cfg and makes it the default log.
+// It returns nil without error when auditing is turned off.
+func Init(cfg *config.Config) (*Log, error) {
+	if cfg.AuditLog == "off" {
+		defaultLog = nil
+		return nil, nil
+	}
+
+	auditPath := cfg.AuditLog
+	if auditPath == "" {
+		logDir, err := logger.Dir()
+		if err != nil {
+			return nil, err
+		}
+		auditPath = filepath.Join(logDir, DefaultFileName)
+	}
+
+	log, err := Open(auditPath, cfg.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultLog = log
+	return log, nil
+}
+
+// Default returns the log opened by Init, or nil if auditing is off
+func Default() *Log {
+	return defaultLog
+}
+
+// Open opens (or creates) an audit file for appending
+func Open(auditPath, workspace string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(auditPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(auditPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	return &Log{
+		path:      auditPath,
+		workspace: workspace,
+		file:      file,
+	}, nil
+}
+
+// Path returns the audit file location
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Close closes the audit file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Record appends an event as one JSON line
+func (l *Log) Record(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+// Middleware records every call to a tool for which isWrite returns true,
+// including calls rejected by inner middlewares. Dry runs are recorded too,
+// flagged with isDryRun.
+func (l *Log) Middleware(isWrite func(name string) bool, isDryRun func(request mcp.CallToolRequest) bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !isWrite(request.Params.Name) {
+				return next(ctx, request)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			event := Event{
+				Time:       start.UTC(),
+				Tool:       request.Params.Name,
+				Arguments:  redactArguments(request.GetArguments()),
+				Workspace:  l.workspace,
+				DryRun:     isDryRun(request),
+				Outcome:    OutcomeSuccess,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				event.SessionID = session.SessionID()
+				if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+					info := withInfo.GetClientInfo()
+					if info.Name != "" {
+						event.Client = &Client{Name: info.Name, Version: info.Version}
+					}
+				}
+			}
+
+			switch {
+			case err != nil:
+				event.Outcome = OutcomeError
+				event.Error = truncate(redact.Text(err.Error()))
+			case result != nil && result.IsError:
+				event.Outcome = OutcomeError
+				event.Error = truncate(redact.Text(resultText(result)))
+			}
+
+			if recordErr := l.Record(event); recordErr != nil {
+				logger.Errorf("Failed to record audit event for %s: %v", request.Params.Name, recordErr)
+			}
+
+			return result, err
+		}
+	}
+}
+
+// Read returns the events matching filter, oldest first
+func (l *Log) Read(filter Filter) ([]Event, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			logger.Warnf("Skipping malformed audit line: %v", err)
+			continue
+		}
+
+		if !filter.matches(event) {
+			continue
+		}
+
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) > filter.Limit {
+			events = events[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return events, nil
+}
+
+func (f Filter) matches(event Event) bool {
+	if !f.Since.IsZero() && event.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Time.After(f.Until) {
+		return false
+	}
+	if f.Tool != "" {
+		if ok, _ := path.Match(f.Tool, event.Tool); !ok {
+			return false
+		}
+	}
+	if f.Outcome != "" && event.Outcome != f.Outcome {
+		return false
+	}
+	return true
+}
+
+// redactArguments masks secrets in the call arguments before they are stored
+func redactArguments(arguments map[string]interface{}) map[string]interface{} {
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return map[string]interface{}{"error": "arguments could not be encoded"}
+	}
+
+	redacted, ok := redact.JSON(data)
+	if !ok {
+		return map[string]interface{}{"error": "arguments could not be encoded"}
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(redacted, &result); err != nil {
+		return map[string]interface{}{"error": "arguments could not be encoded"}
+	}
+	return result
+}
+
+// resultText returns the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func truncate(s string) string {
+	if len(s) <= maxErrorLength {
+		return s
+	}
+	return s[:maxErrorLength] + "..."
+}
@@ -0,0 +1,242 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Safety limits of one benchmark
+const (
+	MaxRequests    = 1000
+	MaxConcurrency = 50
+	MaxDuration    = 5 * time.Minute
+)
+
+// Options describes the load of a benchmark
+type Options struct {
+	Requests    int           // total requests to send
+	Concurrency int           // requests in flight at the same time
+	Duration    time.Duration // stop sending after this long, 0 for no limit
+	Timeout     time.Duration // timeout of each request
+}
+
+// Validate checks the options against the safety limits
+func (o Options) Validate() error {
+	if o.Requests < 1 || o.Requests > MaxRequests {
+		return fmt.Errorf("requests must be between 1 and %d", MaxRequests)
+	}
+	if o.Concurrency < 1 || o.Concurrency > MaxConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d", MaxConcurrency)
+	}
+	if o.Duration < 0 || o.Duration > MaxDuration {
+		return fmt.Errorf("duration must be at most %s", MaxDuration)
+	}
+	if o.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	return nil
+}
+
+// Sample is the outcome of one request
+type Sample struct {
+	Status  int           // HTTP status, 0 if no response was received
+	TTFB    time.Duration // until the response headers
+	Latency time.Duration // until the end of the response body
+	Err     error         // transport or read error
+}
+
+// Request sends one request
+type Request func(ctx context.Context) Sample
+
+// Percentiles summarizes a set of durations, in milliseconds
+type Percentiles struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// Report is the result of a benchmark
+type Report struct {
+	Requests      int            `json:"requests"`  // sent
+	Succeeded     int            `json:"succeeded"` // 2xx responses
+	Failed        int            `json:"failed"`
+	Stopped       string         `json:"stopped,omitempty"` // why fewer requests than planned were sent
+	DurationMs    int64          `json:"durationMs"`
+	ThroughputRPS float64        `json:"throughputRps"` // completed requests per second
+	Latency       *Percentiles   `json:"latencyMs,omitempty"`
+	TTFB          *Percentiles   `json:"ttfbMs,omitempty"`
+	Statuses      map[string]int `json:"statuses"`         // responses by status code
+	Errors        map[string]int `json:"errors,omitempty"` // failures by status code, "timeout" or "error"
+	SampleErrors  []string       `json:"sampleErrors,omitempty"`
+}
+
+// maxSampleErrors bounds the distinct error messages kept in a report
+const maxSampleErrors = 5
+
+// Run sends requests with the load of opts until all are sent, the duration
+// has elapsed or ctx is cancelled. progress, when not nil, is called after
+// each request from the goroutine that sent it.
+func Run(ctx context.Context, opts Options, do Request, progress func(completed, failed int)) *Report {
+	sendCtx := ctx
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		sendCtx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		samples   = make([]Sample, 0, opts.Requests)
+		failed    int
+		next      = make(chan struct{})
+		start     = time.Now()
+		completed int
+	)
+
+	for i := 0; i < min(opts.Concurrency, opts.Requests); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range next {
+				reqCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+				sample := do(reqCtx)
+				cancel()
+				if sample.Err != nil && errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+					sample.Err = context.DeadlineExceeded
+				}
+
+				mu.Lock()
+				samples = append(samples, sample)
+				completed++
+				if !succeeded(sample) {
+					failed++
+				}
+				c, f := completed, failed
+				mu.Unlock()
+
+				if progress != nil {
+					progress(c, f)
+				}
+			}
+		}()
+	}
+
+	stopped := ""
+dispatch:
+	for sent := 0; sent < opts.Requests; sent++ {
+		select {
+		case next <- struct{}{}:
+		case <-sendCtx.Done():
+			stopped = "duration elapsed"
+			if ctx.Err() != nil {
+				stopped = "cancelled"
+			}
+			break dispatch
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	return summarize(samples, time.Since(start), stopped)
+}
+
+// summarize computes the report of the samples of a benchmark
+func summarize(samples []Sample, elapsed time.Duration, stopped string) *Report {
+	report := &Report{
+		Requests:   len(samples),
+		Stopped:    stopped,
+		DurationMs: elapsed.Milliseconds(),
+		Statuses:   map[string]int{},
+		Errors:     map[string]int{},
+	}
+	if elapsed > 0 {
+		report.ThroughputRPS = round(float64(len(samples)) / elapsed.Seconds())
+	}
+
+	var latencies, ttfbs []time.Duration
+	seen := map[string]bool{}
+	for _, sample := range samples {
+		if sample.Status != 0 {
+			report.Statuses[strconv.Itoa(sample.Status)]++
+			ttfbs = append(ttfbs, sample.TTFB)
+		}
+		if succeeded(sample) {
+			report.Succeeded++
+			latencies = append(latencies, sample.Latency)
+			continue
+		}
+
+		report.Failed++
+		report.Errors[errorKey(sample)]++
+		if sample.Err != nil && !seen[sample.Err.Error()] && len(report.SampleErrors) < maxSampleErrors {
+			seen[sample.Err.Error()] = true
+			report.SampleErrors = append(report.SampleErrors, sample.Err.Error())
+		}
+	}
+
+	// Latencies are those of successful requests, so that fast failures do
+	// not flatter the numbers; TTFB covers every response
+	report.Latency = percentiles(latencies)
+	report.TTFB = percentiles(ttfbs)
+	return report
+}
+
+func succeeded(sample Sample) bool {
+	return sample.Err == nil && sample.Status >= 200 && sample.Status < 300
+}
+
+// errorKey classifies a failed request
+func errorKey(sample Sample) string {
+	switch {
+	case errors.Is(sample.Err, context.DeadlineExceeded):
+		return "timeout"
+	case sample.Status != 0:
+		return strconv.Itoa(sample.Status)
+	}
+	return "error"
+}
+
+// percentiles summarizes durations with the nearest-rank method
+func percentiles(durations []time.Duration) *Percentiles {
+	if len(durations) == 0 {
+		return nil
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(durations)))) - 1
+		return ms(durations[max(i, 0)])
+	}
+
+	return &Percentiles{
+		Min:  ms(durations[0]),
+		Mean: ms(total / time.Duration(len(durations))),
+		P50:  rank(50),
+		P90:  rank(90),
+		P99:  rank(99),
+		Max:  ms(durations[len(durations)-1]),
+	}
+}
+
+func ms(d time.Duration) float64 {
+	return round(float64(d) / float64(time.Millisecond))
+}
+
+// round rounds to two decimals
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,113 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func durations(ms ...int) []time.Duration {
+	result := make([]time.Duration, 0, len(ms))
+	for _, m := range ms {
+		result = append(result, time.Duration(m)*time.Millisecond)
+	}
+	return result
+}
+
+func TestPercentiles(t *testing.T) {
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = 100 - i // 100 down to 1
+	}
+
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		want      *Percentiles
+	}{
+		{"empty", nil, nil},
+		{"one", durations(7), &Percentiles{Min: 7, Mean: 7, P50: 7, P90: 7, P99: 7, Max: 7}},
+		{"two", durations(30, 10), &Percentiles{Min: 10, Mean: 20, P50: 10, P90: 30, P99: 30, Max: 30}},
+		{"ten", durations(10, 9, 8, 7, 6, 5, 4, 3, 2, 1), &Percentiles{Min: 1, Mean: 5.5, P50: 5, P90: 9, P99: 10, Max: 10}},
+		{"hundred", durations(hundred...), &Percentiles{Min: 1, Mean: 50.5, P50: 50, P90: 90, P99: 99, Max: 100}},
+		{"sub-millisecond", []time.Duration{1500 * time.Microsecond, 2250 * time.Microsecond}, &Percentiles{Min: 1.5, Mean: 1.88, P50: 1.5, P90: 2.25, P99: 2.25, Max: 2.25}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := percentiles(tt.durations)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("percentiles() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	samples := []Sample{
+		{Status: 200, TTFB: 5 * time.Millisecond, Latency: 10 * time.Millisecond},
+		{Status: 200, TTFB: 5 * time.Millisecond, Latency: 30 * time.Millisecond},
+		{Status: 500, TTFB: 1 * time.Millisecond, Latency: 1 * time.Millisecond},
+		{Err: context.DeadlineExceeded},
+		{Err: errors.New("connection refused")},
+		{Err: errors.New("connection refused")},
+	}
+
+	report := summarize(samples, 2*time.Second, "")
+	if report.Requests != 6 || report.Succeeded != 2 || report.Failed != 4 || report.ThroughputRPS != 3 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Latency.Max != 30 || report.Latency.Min != 10 {
+		t.Errorf("latency = %+v, want successful requests only", report.Latency)
+	}
+	if report.TTFB.Min != 1 {
+		t.Errorf("ttfb = %+v, want every response", report.TTFB)
+	}
+	if report.Statuses["200"] != 2 || report.Statuses["500"] != 1 {
+		t.Errorf("statuses = %v", report.Statuses)
+	}
+	if report.Errors["500"] != 1 || report.Errors["timeout"] != 1 || report.Errors["error"] != 2 {
+		t.Errorf("errors = %v", report.Errors)
+	}
+	if len(report.SampleErrors) != 2 {
+		t.Errorf("sample errors = %v, want distinct messages", report.SampleErrors)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	valid := Options{Requests: 10, Concurrency: 2, Timeout: time.Second}
+	tests := []struct {
+		name  string
+		edit  func(*Options)
+		valid bool
+	}{
+		{"valid", func(*Options) {}, true},
+		{"no requests", func(o *Options) { o.Requests = 0 }, false},
+		{"too many requests", func(o *Options) { o.Requests = MaxRequests + 1 }, false},
+		{"too much concurrency", func(o *Options) { o.Concurrency = MaxConcurrency + 1 }, false},
+		{"too long", func(o *Options) { o.Duration = MaxDuration + time.Second }, false},
+		{"no timeout", func(o *Options) { o.Timeout = 0 }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.edit(&opts)
+			if err := opts.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid=%v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	completed := 0
+	report := Run(context.Background(), Options{Requests: 20, Concurrency: 4, Timeout: time.Second}, func(ctx context.Context) Sample {
+		return Sample{Status: 200, Latency: time.Millisecond}
+	}, func(c, f int) { completed = max(completed, c) })
+
+	if report.Requests != 20 || report.Succeeded != 20 || completed != 20 || report.Stopped != "" {
+		t.Errorf("report = %+v after %d completions", report, completed)
+	}
+}
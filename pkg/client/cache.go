@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+)
+
+// Resource names used to build cache keys and invalidate entries
+const (
+	ResourceAgents       = "agents"
+	ResourceModels       = "models"
+	ResourceFunctions    = "functions"
+	ResourceSandboxes    = "sandboxes"
+	ResourceJobs         = "jobs"
+	ResourceIntegrations = "integrations"
+)
+
+// statusResponse is implemented by every SDK *WithResponse result
+type statusResponse interface {
+	StatusCode() int
+}
+
+type refreshKey struct{}
+
+// WithRefresh returns a context that makes Fetch bypass the cache when refresh is true
+func WithRefresh(ctx context.Context, refresh bool) context.Context {
+	return context.WithValue(ctx, refreshKey{}, refresh)
+}
+
+// isRefresh reports whether the caller asked to bypass the cache
+func isRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
+// CacheStats holds hit/miss counters for one operation
+type CacheStats struct {
+	Hits          int `json:"hits"`
+	Misses        int `json:"misses"`
+	Bypassed      int `json:"bypassed"`
+	Invalidations int `json:"invalidations"`
+}
+
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// ResponseCache is an in-memory read-through cache for SDK list/get calls,
+// keyed by workspace, operation and resource name
+type ResponseCache struct {
+	mu        sync.Mutex
+	workspace string
+	ttl       time.Duration
+	entries   map[string]cacheEntry
+	stats     map[string]*CacheStats
+}
+
+var (
+	cachesMu sync.Mutex
+	caches   = make(map[string]*ResponseCache)
+)
+
+// SharedCache returns the cache for the configured workspace. Handlers share
+// one instance so that a write in one toolset invalidates reads in another.
+func SharedCache(cfg *config.Config) *ResponseCache {
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
+
+	if cache, ok := caches[cfg.Workspace]; ok {
+		return cache
+	}
+
+	cache := &ResponseCache{
+		workspace: cfg.Workspace,
+		ttl:       cfg.CacheTTL,
+		entries:   make(map[string]cacheEntry),
+		stats:     make(map[string]*CacheStats),
+	}
+	caches[cfg.Workspace] = cache
+	return cache
+}
+
+// Fetch returns the cached response for resource/operation/name, or calls fetch
+// and caches its result when the API answered 200 OK.
+//
+// Cached responses are shared by concurrent calls and must be treated as
+// immutable. Each call gets its own copy of the response struct, its body and
+// its list slices, so callers may sort, filter or append to a list; the items
+// of the list and the fields they point to are shared and must not be modified.
+func Fetch[T statusResponse](ctx context.Context, c *ResponseCache, resource, operation, name string, fetch func() (T, error)) (T, error) {
+	if c == nil || c.ttl <= 0 {
+		return fetch()
+	}
+
+	key := c.key(resource, operation, name)
+	statsKey := resource + "." + operation
+
+	if isRefresh(ctx) {
+		c.record(statsKey, func(s *CacheStats) { s.Bypassed++ })
+	} else if value, ok := c.get(key); ok {
+		if typed, ok := value.(T); ok {
+			c.record(statsKey, func(s *CacheStats) { s.Hits++ })
+			logger.Debugf("Cache hit for %s", key)
+			return clone(typed), nil
+		}
+	} else {
+		c.record(statsKey, func(s *CacheStats) { s.Misses++ })
+	}
+
+	resp, err := fetch()
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode() == http.StatusOK {
+		c.set(key, clone(resp))
+	}
+
+	return resp, nil
+}
+
+// Invalidate drops the cached list for resource and, when name is set, the
+// cached get for that resource
+func (c *ResponseCache) Invalidate(resource, name string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	listKey := c.key(resource, "list", "")
+	delete(c.entries, listKey)
+	if name != "" {
+		delete(c.entries, c.key(resource, "get", name))
+	}
+
+	stats := c.statsFor(resource + ".list")
+	stats.Invalidations++
+}
+
+// Stats returns a snapshot of the hit/miss counters per operation
+func (c *ResponseCache) Stats() map[string]CacheStats {
+	if c == nil {
+		return map[string]CacheStats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[string]CacheStats, len(c.stats))
+	for operation, stats := range c.stats {
+		snapshot[operation] = *stats
+	}
+	return snapshot
+}
+
+// TTL returns how long entries stay valid
+func (c *ResponseCache) TTL() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.ttl
+}
+
+// Size returns the number of live entries
+func (c *ResponseCache) Size() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := 0
+	now := time.Now()
+	for _, entry := range c.entries {
+		if now.Before(entry.expiresAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// clone copies an SDK response: the struct it points to, and the byte and
+// list slices of its fields, so that callers do not share them with the cache
+func clone[T any](resp T) T {
+	value := reflect.ValueOf(resp)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return resp
+	}
+
+	copied := reflect.New(value.Elem().Type())
+	copied.Elem().Set(value.Elem())
+	for i := 0; i < copied.Elem().NumField(); i++ {
+		field := copied.Elem().Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Slice && !field.IsNil():
+			field.Set(cloneSlice(field))
+		case field.Kind() == reflect.Pointer && !field.IsNil() && field.Elem().Kind() == reflect.Slice:
+			list := reflect.New(field.Elem().Type())
+			if !field.Elem().IsNil() {
+				list.Elem().Set(cloneSlice(field.Elem()))
+			}
+			field.Set(list)
+		}
+	}
+	return copied.Interface().(T)
+}
+
+func cloneSlice(slice reflect.Value) reflect.Value {
+	copied := reflect.MakeSlice(slice.Type(), slice.Len(), slice.Len())
+	reflect.Copy(copied, slice)
+	return copied
+}
+
+func (c *ResponseCache) key(resource, operation, name string) string {
+	return strings.Join([]string{c.workspace, resource, operation, name}, "/")
+}
+
+func (c *ResponseCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *ResponseCache) set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+func (c *ResponseCache) record(statsKey string, update func(*CacheStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	update(c.statsFor(statsKey))
+}
+
+// statsFor returns the counters for an operation. The caller must hold c.mu.
+func (c *ResponseCache) statsFor(statsKey string) *CacheStats {
+	stats, ok := c.stats[statsKey]
+	if !ok {
+		stats = &CacheStats{}
+		c.stats[statsKey] = stats
+	}
+	return stats
+}
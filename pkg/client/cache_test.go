@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+)
+
+// listResponse is shaped like an SDK list response
+type listResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]string
+}
+
+func (r *listResponse) StatusCode() int {
+	if r.HTTPResponse == nil {
+		return 0
+	}
+	return r.HTTPResponse.StatusCode
+}
+
+func newTestCache(workspace string) *ResponseCache {
+	return SharedCache(&config.Config{Workspace: workspace, CacheTTL: time.Minute})
+}
+
+func fetchList(cache *ResponseCache, calls *int) (*listResponse, error) {
+	return Fetch(context.Background(), cache, ResourceAgents, "list", "", func() (*listResponse, error) {
+		*calls++
+		items := []string{"c", "a", "b"}
+		return &listResponse{Body: []byte(`["c","a","b"]`), HTTPResponse: &http.Response{StatusCode: http.StatusOK}, JSON200: &items}, nil
+	})
+}
+
+func TestFetchCaches(t *testing.T) {
+	cache := newTestCache("fetch-caches")
+	calls := 0
+
+	for i := 0; i < 3; i++ {
+		if _, err := fetchList(cache, &calls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+	if stats := cache.Stats()["agents.list"]; stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	cache.Invalidate(ResourceAgents, "")
+	_, _ = fetchList(cache, &calls)
+	if calls != 2 {
+		t.Errorf("fetch called %d times after invalidation, want 2", calls)
+	}
+
+	_, _ = Fetch(WithRefresh(context.Background(), true), cache, ResourceAgents, "list", "", func() (*listResponse, error) {
+		calls++
+		return &listResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil
+	})
+	if calls != 3 {
+		t.Errorf("refresh should bypass the cache")
+	}
+}
+
+func TestFetchReturnsCopies(t *testing.T) {
+	cache := newTestCache("fetch-copies")
+	calls := 0
+
+	first, _ := fetchList(cache, &calls)
+	sort.Strings(*first.JSON200)
+	first.Body[0] = 'x'
+	*first.JSON200 = append(*first.JSON200, "d")
+
+	second, _ := fetchList(cache, &calls)
+	if got := *second.JSON200; len(got) != 3 || got[0] != "c" {
+		t.Errorf("cached list = %v, modified by a caller", got)
+	}
+	if string(second.Body) != `["c","a","b"]` {
+		t.Errorf("cached body = %s, modified by a caller", second.Body)
+	}
+	sort.Strings(*second.JSON200)
+
+	third, _ := fetchList(cache, &calls)
+	if (*third.JSON200)[0] != "c" || calls != 1 {
+		t.Errorf("cached list = %v after %d calls", *third.JSON200, calls)
+	}
+}
+
+func TestFetchConcurrentSort(t *testing.T) {
+	cache := newTestCache("fetch-concurrent")
+	calls := 0
+	_, _ = fetchList(cache, &calls)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _ := Fetch(context.Background(), cache, ResourceAgents, "list", "", func() (*listResponse, error) {
+				t.Error("unexpected miss")
+				return nil, nil
+			})
+			sort.Strings(*resp.JSON200)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFetchSkipsErrors(t *testing.T) {
+	cache := newTestCache("fetch-errors")
+	calls := 0
+	for i := 0; i < 2; i++ {
+		_, _ = Fetch(context.Background(), cache, ResourceAgents, "get", "a", func() (*listResponse, error) {
+			calls++
+			return &listResponse{HTTPResponse: &http.Response{StatusCode: http.StatusNotFound}}, nil
+		})
+	}
+	if calls != 2 {
+		t.Errorf("non-200 responses should not be cached")
+	}
+}
+
+func TestCloneNil(t *testing.T) {
+	var resp *listResponse
+	if clone(resp) != nil {
+		t.Error("clone(nil) should be nil")
+	}
+	empty := &listResponse{}
+	if copied := clone(empty); copied == empty || copied.JSON200 != nil {
+		t.Errorf("clone() = %+v", copied)
+	}
+}
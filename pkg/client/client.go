@@ -19,7 +19,8 @@ type ServiceAccount struct {
 }
 
 // NewSDKClient creates a new SDK ClientWithResponses using the toolkit approach
-// This mimics how the CLI initializes its client
+// This mimics how the CLI initializes its client. No http.Client is passed, so
+// requests go through http.DefaultTransport, which credentials.Install wraps.
 func NewSDKClient(cfg *config.Config) (*sdk.ClientWithResponses, error) {
 	// Build user agent like the CLI
 	osName := runtime.GOOS
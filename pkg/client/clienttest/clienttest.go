@@ -0,0 +1,58 @@
+// Package clienttest provides a fake resource behind client.ResourceOps, for
+// the tests of tools that read-modify-write resources
+package clienttest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+)
+
+// Resource holds one resource of type T and records the updates sent to it
+type Resource[T any] struct {
+	Item         T
+	GetStatus    int              // status of reads; http.StatusOK when zero
+	UpdateStatus int              // status of updates; http.StatusOK when zero
+	OnRead       map[int]func(*T) // changes Item before the given read, counted from 1
+	Reads        int
+	Updates      []T // every update sent, accepted or not
+}
+
+// New returns a fake holding item
+func New[T any](item T) *Resource[T] {
+	return &Resource[T]{Item: item}
+}
+
+// Ops returns ops with Get and Update replaced by the fake. Reads return a
+// deep copy of Item, like a new response, and accepted updates replace it.
+func (r *Resource[T]) Ops(ops client.ResourceOps[T]) client.ResourceOps[T] {
+	ops.Get = func() (int, *T, error) {
+		r.Reads++
+		if change, ok := r.OnRead[r.Reads]; ok {
+			change(&r.Item)
+		}
+		if r.GetStatus != 0 && r.GetStatus != http.StatusOK {
+			return r.GetStatus, nil, nil
+		}
+		item := clone(r.Item)
+		return http.StatusOK, &item, nil
+	}
+	ops.Update = func(item T) (int, error) {
+		r.Updates = append(r.Updates, clone(item))
+		if r.UpdateStatus != 0 && r.UpdateStatus != http.StatusOK {
+			return r.UpdateStatus, nil
+		}
+		r.Item = clone(item)
+		return http.StatusOK, nil
+	}
+	return ops
+}
+
+// clone copies an API object through its JSON form, which every SDK type has
+func clone[T any](item T) T {
+	var copied T
+	data, _ := json.Marshal(item)
+	_ = json.Unmarshal(data, &copied)
+	return copied
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// Labels returns the metadata labels of a resource, reading through the cache.
+// found is false when the resource does not exist.
+func Labels(ctx context.Context, sdkClient *sdk.ClientWithResponses, cache *ResponseCache, resource, name string) (map[string]string, bool, error) {
+	var (
+		status   int
+		metadata *sdk.Metadata
+		err      error
+	)
+
+	switch resource {
+	case ResourceAgents:
+		var resp *sdk.GetAgentResponse
+		resp, err = Fetch(ctx, cache, resource, "get", name, func() (*sdk.GetAgentResponse, error) {
+			return sdkClient.GetAgentWithResponse(ctx, name)
+		})
+		if err == nil {
+			status = resp.StatusCode()
+			if resp.JSON200 != nil {
+				metadata = resp.JSON200.Metadata
+			}
+		}
+	case ResourceModels:
+		var resp *sdk.GetModelResponse
+		resp, err = Fetch(ctx, cache, resource, "get", name, func() (*sdk.GetModelResponse, error) {
+			return sdkClient.GetModelWithResponse(ctx, name)
+		})
+		if err == nil {
+			status = resp.StatusCode()
+			if resp.JSON200 != nil {
+				metadata = resp.JSON200.Metadata
+			}
+		}
+	case ResourceFunctions:
+		var resp *sdk.GetFunctionResponse
+		resp, err = Fetch(ctx, cache, resource, "get", name, func() (*sdk.GetFunctionResponse, error) {
+			return sdkClient.GetFunctionWithResponse(ctx, name)
+		})
+		if err == nil {
+			status = resp.StatusCode()
+			if resp.JSON200 != nil {
+				metadata = resp.JSON200.Metadata
+			}
+		}
+	case ResourceSandboxes:
+		var resp *sdk.GetSandboxResponse
+		resp, err = Fetch(ctx, cache, resource, "get", name, func() (*sdk.GetSandboxResponse, error) {
+			return sdkClient.GetSandboxWithResponse(ctx, name)
+		})
+		if err == nil {
+			status = resp.StatusCode()
+			if resp.JSON200 != nil {
+				metadata = resp.JSON200.Metadata
+			}
+		}
+	case ResourceJobs:
+		var resp *sdk.GetJobResponse
+		resp, err = Fetch(ctx, cache, resource, "get", name, func() (*sdk.GetJobResponse, error) {
+			return sdkClient.GetJobWithResponse(ctx, name)
+		})
+		if err == nil {
+			status = resp.StatusCode()
+			if resp.JSON200 != nil {
+				metadata = resp.JSON200.Metadata
+			}
+		}
+	case ResourceIntegrations:
+		var resp *sdk.GetIntegrationConnectionResponse
+		resp, err = Fetch(ctx, cache, resource, "get", name, func() (*sdk.GetIntegrationConnectionResponse, error) {
+			return sdkClient.GetIntegrationConnectionWithResponse(ctx, name)
+		})
+		if err == nil {
+			status = resp.StatusCode()
+			if resp.JSON200 != nil {
+				metadata = resp.JSON200.Metadata
+			}
+		}
+	default:
+		return nil, false, fmt.Errorf("resource type '%s' has no labels", resource)
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get %s '%s': %w", resource, name, err)
+	}
+	if status == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if status != http.StatusOK {
+		return nil, false, fmt.Errorf("failed to get %s '%s' with status %d", resource, name, status)
+	}
+
+	labels := map[string]string{}
+	if metadata != nil && metadata.Labels != nil {
+		labels = *metadata.Labels
+	}
+	return labels, true, nil
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/credentials"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// logsPath is the observability endpoint serving resource logs, which the SDK
+// does not wrap. It is called as
+//
+//	GET /observability/logs?resourceType=agent&resourceName=NAME&startTime=RFC3339[&endTime=RFC3339]
+//
+// with the workspace and bearer token headers used by the SDK, and answers
+// with a JSON array of LogEntry, in no guaranteed order.
+const logsPath = "/observability/logs"
+
+// LogEntry is one log line of a resource
+type LogEntry struct {
+	Timestamp time.Time `json:"timestamp"` // RFC3339 with nanoseconds
+	Severity  string    `json:"severity,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// LogQuery selects the logs of one resource in a time window
+type LogQuery struct {
+	ResourceType string // agent, function, job or sandbox
+	Name         string
+	Start        time.Time
+	End          time.Time // zero for now
+}
+
+// LogsClient reads resource logs from the observability API
+type LogsClient struct {
+	endpoint    string
+	workspace   string
+	credentials sdk.Credentials
+	httpClient  *http.Client
+}
+
+// NewLogsClient creates a logs client for the configured workspace
+func NewLogsClient(cfg *config.Config) *LogsClient {
+	return &LogsClient{
+		endpoint:    strings.TrimRight(cfg.APIEndpoint, "/"),
+		workspace:   cfg.Workspace,
+		credentials: cfg.Credentials,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Fetch returns the log entries matching query, oldest first
+func (c *LogsClient) Fetch(ctx context.Context, query LogQuery) ([]LogEntry, error) {
+	params := url.Values{}
+	params.Set("resourceType", query.ResourceType)
+	params.Set("resourceName", query.Name)
+	params.Set("startTime", query.Start.UTC().Format(time.RFC3339Nano))
+	if !query.End.IsZero() {
+		params.Set("endTime", query.End.UTC().Format(time.RFC3339Nano))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+logsPath+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logs request: %w", err)
+	}
+
+	token, err := bearerToken(ctx, c.credentials)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Blaxel-Authorization", "Bearer "+token)
+	req.Header.Set("X-Blaxel-Workspace", c.workspace)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch logs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch logs failed with status %d", resp.StatusCode)
+	}
+
+	entries, err := decodeLogs(body)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	return entries, nil
+}
+
+// bearerToken returns the bearer token of the current credentials, for the
+// endpoints called without the SDK
+func bearerToken(ctx context.Context, creds sdk.Credentials) (string, error) {
+	if manager := credentials.Default(); manager != nil {
+		return manager.Token(ctx)
+	}
+	if creds.APIKey != "" {
+		return creds.APIKey, nil
+	}
+	if creds.AccessToken != "" {
+		return creds.AccessToken, nil
+	}
+	return "", fmt.Errorf("no credentials available to call the Blaxel API")
+}
+
+// decodeLogs decodes the array of entries answered by the logs endpoint
+func decodeLogs(body []byte) ([]LogEntry, error) {
+	entries := []LogEntry{}
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode logs: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func TestLogsClientFetch(t *testing.T) {
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != logsPath {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		for key, want := range map[string]string{
+			"resourceType": "agent",
+			"resourceName": "my-agent",
+			"startTime":    "2024-01-02T15:00:00Z",
+			"endTime":      "2024-01-02T16:00:00Z",
+		} {
+			if got := query.Get(key); got != want {
+				t.Errorf("%s = %q, want %q", key, got, want)
+			}
+		}
+		if got := r.Header.Get("X-Blaxel-Authorization"); got != "Bearer test-key" {
+			t.Errorf("authorization = %q", got)
+		}
+		if got := r.Header.Get("X-Blaxel-Workspace"); got != "test" {
+			t.Errorf("workspace = %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"timestamp": "2024-01-02T15:00:02.5Z", "severity": "ERROR", "message": "second"},
+			{"timestamp": "2024-01-02T15:00:01Z", "severity": "INFO", "message": "first"}
+		]`))
+	}))
+	defer server.Close()
+
+	logs := NewLogsClient(&config.Config{APIEndpoint: server.URL + "/", Workspace: "test", Credentials: sdk.Credentials{APIKey: "test-key"}})
+	entries, err := logs.Fetch(context.Background(), LogQuery{ResourceType: "agent", Name: "my-agent", Start: start, End: end})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Message != "first" || entries[1].Message != "second" {
+		t.Fatalf("Fetch() = %+v, want the entries oldest first", entries)
+	}
+	if entries[1].Severity != "ERROR" || !entries[1].Timestamp.Equal(start.Add(2500*time.Millisecond)) {
+		t.Errorf("entry = %+v", entries[1])
+	}
+}
+
+func TestLogsClientFetchErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"status", http.StatusForbidden, `{"error": "forbidden"}`},
+		{"not an array", http.StatusOK, `{"logs": []}`},
+		{"invalid timestamp", http.StatusOK, `[{"timestamp": "yesterday", "message": "x"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			logs := NewLogsClient(&config.Config{APIEndpoint: server.URL, Credentials: sdk.Credentials{APIKey: "test-key"}})
+			if _, err := logs.Fetch(context.Background(), LogQuery{ResourceType: "job", Name: "j", Start: time.Now()}); err == nil {
+				t.Error("Fetch() should fail")
+			}
+		})
+	}
+}
+
+func TestDecodeLogs(t *testing.T) {
+	entries, err := decodeLogs([]byte(`[]`))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("decodeLogs([]) = %v, %v", entries, err)
+	}
+
+	entries, err = decodeLogs([]byte(`[{"timestamp": "2024-01-02T15:04:05.123456789Z", "message": "no severity"}]`))
+	if err != nil {
+		t.Fatalf("decodeLogs() error = %v", err)
+	}
+	if entries[0].Severity != "" || entries[0].Timestamp.Nanosecond() != 123456789 {
+		t.Errorf("decodeLogs() = %+v", entries)
+	}
+}
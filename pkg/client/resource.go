@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/utils"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// ResourceOps reads and writes one resource of a given type, for the tools
+// that read-modify-write resources of several types the same way
+type ResourceOps[T any] struct {
+	Kind     string // human-readable kind used in messages
+	Resource string // Resource* name, to invalidate the cache
+	Path     string // API path, shown in dry runs
+	Get      func() (int, *T, error)
+	Update   func(T) (int, error)
+	Metadata func(*T) **sdk.Metadata
+	Runtime  func(*T) **sdk.Runtime // nil for types without a runtime
+	Status   func(*T) *string       // nil for types without a status
+}
+
+// Read fetches the resource, bypassing the cache
+func (o ResourceOps[T]) Read(name string) (*T, error) {
+	status, item, err := o.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", o.Kind, err)
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("%s '%s' not found", o.Kind, name)
+	}
+	if status != http.StatusOK || item == nil {
+		return nil, fmt.Errorf("get %s failed with status %d", o.Kind, status)
+	}
+	return item, nil
+}
+
+// Write updates the resource and invalidates its cached responses
+func (o ResourceOps[T]) Write(cache *ResponseCache, name string, item T) error {
+	status, err := o.Update(item)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", o.Kind, err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("update %s failed with status %d", o.Kind, status)
+	}
+	cache.Invalidate(o.Resource, name)
+	return nil
+}
+
+// RuntimeOf returns the runtime of a resource, creating it when unset
+func (o ResourceOps[T]) RuntimeOf(item *T) *sdk.Runtime {
+	runtime := o.Runtime(item)
+	if *runtime == nil {
+		*runtime = &sdk.Runtime{}
+	}
+	return *runtime
+}
+
+// Wait waits for the resource to redeploy after an update. waitForCompletion
+// is the tool argument: empty or "true" waits, anything else does not. It
+// reports whether it waited, and the error of the status check.
+func (o ResourceOps[T]) Wait(ctx context.Context, name, waitForCompletion string) (bool, error) {
+	if waitForCompletion != "" && waitForCompletion != "true" {
+		logger.Printf("Skipping status wait for %s '%s'", o.Kind, name)
+		return false, nil
+	}
+	logger.Printf("Waiting for %s '%s' to redeploy...", o.Kind, name)
+	return true, utils.WaitForResourceStatus(ctx, name, ResourceStatusChecker[T]{o})
+}
+
+// ResourceStatusChecker implements utils.StatusChecker for any ResourceOps
+type ResourceStatusChecker[T any] struct {
+	Ops ResourceOps[T]
+}
+
+// GetResource gets the resource, or nil if it is not found
+func (c ResourceStatusChecker[T]) GetResource(ctx context.Context, name string) (interface{}, error) {
+	status, item, err := c.Ops.Get()
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || item == nil {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// ExtractStatus extracts the status of the resource
+func (c ResourceStatusChecker[T]) ExtractStatus(resource interface{}) string {
+	if item, ok := resource.(*T); ok && c.Ops.Status != nil {
+		if status := c.Ops.Status(item); status != nil {
+			return *status
+		}
+	}
+	return "DEPLOYING" // Default assumption
+}
+
+// GetResourceType returns the resource type
+func (c ResourceStatusChecker[T]) GetResourceType() utils.ResourceType {
+	return utils.ResourceType(c.Ops.Kind)
+}
+
+// AgentOps returns the operations on an agent
+func AgentOps(ctx context.Context, c *sdk.ClientWithResponses, name string) ResourceOps[sdk.Agent] {
+	return ResourceOps[sdk.Agent]{
+		Kind:     "agent",
+		Resource: ResourceAgents,
+		Path:     "/agents/" + name,
+		Get: func() (int, *sdk.Agent, error) {
+			resp, err := c.GetAgentWithResponse(ctx, name)
+			if err != nil {
+				return 0, nil, err
+			}
+			return resp.StatusCode(), resp.JSON200, nil
+		},
+		Update: func(agent sdk.Agent) (int, error) {
+			resp, err := c.UpdateAgentWithResponse(ctx, name, agent)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		},
+		Metadata: func(agent *sdk.Agent) **sdk.Metadata { return &agent.Metadata },
+		Runtime: func(agent *sdk.Agent) **sdk.Runtime {
+			if agent.Spec == nil {
+				agent.Spec = &sdk.AgentSpec{}
+			}
+			return &agent.Spec.Runtime
+		},
+		Status: func(agent *sdk.Agent) *string { return agent.Status },
+	}
+}
+
+// ModelOps returns the operations on a model API
+func ModelOps(ctx context.Context, c *sdk.ClientWithResponses, name string) ResourceOps[sdk.Model] {
+	return ResourceOps[sdk.Model]{
+		Kind:     "model API",
+		Resource: ResourceModels,
+		Path:     "/models/" + name,
+		Get: func() (int, *sdk.Model, error) {
+			resp, err := c.GetModelWithResponse(ctx, name)
+			if err != nil {
+				return 0, nil, err
+			}
+			return resp.StatusCode(), resp.JSON200, nil
+		},
+		Update: func(model sdk.Model) (int, error) {
+			resp, err := c.UpdateModelWithResponse(ctx, name, model)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		},
+		Metadata: func(model *sdk.Model) **sdk.Metadata { return &model.Metadata },
+		Runtime: func(model *sdk.Model) **sdk.Runtime {
+			if model.Spec == nil {
+				model.Spec = &sdk.ModelSpec{}
+			}
+			return &model.Spec.Runtime
+		},
+		Status: func(model *sdk.Model) *string { return model.Status },
+	}
+}
+
+// FunctionOps returns the operations on an MCP server
+func FunctionOps(ctx context.Context, c *sdk.ClientWithResponses, name string) ResourceOps[sdk.Function] {
+	return ResourceOps[sdk.Function]{
+		Kind:     "MCP server",
+		Resource: ResourceFunctions,
+		Path:     "/functions/" + name,
+		Get: func() (int, *sdk.Function, error) {
+			resp, err := c.GetFunctionWithResponse(ctx, name)
+			if err != nil {
+				return 0, nil, err
+			}
+			return resp.StatusCode(), resp.JSON200, nil
+		},
+		Update: func(function sdk.Function) (int, error) {
+			resp, err := c.UpdateFunctionWithResponse(ctx, name, function)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		},
+		Metadata: func(function *sdk.Function) **sdk.Metadata { return &function.Metadata },
+		Runtime: func(function *sdk.Function) **sdk.Runtime {
+			if function.Spec == nil {
+				function.Spec = &sdk.FunctionSpec{}
+			}
+			return &function.Spec.Runtime
+		},
+		Status: func(function *sdk.Function) *string { return function.Status },
+	}
+}
+
+// SandboxOps returns the operations on a sandbox
+func SandboxOps(ctx context.Context, c *sdk.ClientWithResponses, name string) ResourceOps[sdk.Sandbox] {
+	return ResourceOps[sdk.Sandbox]{
+		Kind:     "sandbox",
+		Resource: ResourceSandboxes,
+		Path:     "/sandboxes/" + name,
+		Get: func() (int, *sdk.Sandbox, error) {
+			resp, err := c.GetSandboxWithResponse(ctx, name)
+			if err != nil {
+				return 0, nil, err
+			}
+			return resp.StatusCode(), resp.JSON200, nil
+		},
+		Update: func(sandbox sdk.Sandbox) (int, error) {
+			resp, err := c.UpdateSandboxWithResponse(ctx, name, sandbox)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		},
+		Metadata: func(sandbox *sdk.Sandbox) **sdk.Metadata { return &sandbox.Metadata },
+		Runtime: func(sandbox *sdk.Sandbox) **sdk.Runtime {
+			if sandbox.Spec == nil {
+				sandbox.Spec = &sdk.SandboxSpec{}
+			}
+			return &sandbox.Spec.Runtime
+		},
+		Status: func(sandbox *sdk.Sandbox) *string { return sandbox.Status },
+	}
+}
+
+// JobOps returns the operations on a job
+func JobOps(ctx context.Context, c *sdk.ClientWithResponses, name string) ResourceOps[sdk.Job] {
+	return ResourceOps[sdk.Job]{
+		Kind:     "job",
+		Resource: ResourceJobs,
+		Path:     "/jobs/" + name,
+		Get: func() (int, *sdk.Job, error) {
+			resp, err := c.GetJobWithResponse(ctx, name)
+			if err != nil {
+				return 0, nil, err
+			}
+			return resp.StatusCode(), resp.JSON200, nil
+		},
+		Update: func(job sdk.Job) (int, error) {
+			resp, err := c.UpdateJobWithResponse(ctx, name, job)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		},
+		Metadata: func(job *sdk.Job) **sdk.Metadata { return &job.Metadata },
+		Runtime: func(job *sdk.Job) **sdk.Runtime {
+			if job.Spec == nil {
+				job.Spec = &sdk.JobSpec{}
+			}
+			return &job.Spec.Runtime
+		},
+		Status: func(job *sdk.Job) *string { return job.Status },
+	}
+}
+
+// IntegrationOps returns the operations on an integration connection
+func IntegrationOps(ctx context.Context, c *sdk.ClientWithResponses, name string) ResourceOps[sdk.IntegrationConnection] {
+	return ResourceOps[sdk.IntegrationConnection]{
+		Kind:     "integration",
+		Resource: ResourceIntegrations,
+		Path:     "/integrations/connections/" + name,
+		Get: func() (int, *sdk.IntegrationConnection, error) {
+			resp, err := c.GetIntegrationConnectionWithResponse(ctx, name)
+			if err != nil {
+				return 0, nil, err
+			}
+			return resp.StatusCode(), resp.JSON200, nil
+		},
+		Update: func(integration sdk.IntegrationConnection) (int, error) {
+			resp, err := c.UpdateIntegrationConnectionWithResponse(ctx, name, integration)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		},
+		Metadata: func(integration *sdk.IntegrationConnection) **sdk.Metadata { return &integration.Metadata },
+	}
+}
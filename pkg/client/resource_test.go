@@ -0,0 +1,108 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client/clienttest"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func agentOps(agent *clienttest.Resource[sdk.Agent]) client.ResourceOps[sdk.Agent] {
+	return agent.Ops(client.AgentOps(context.Background(), nil, "a"))
+}
+
+func TestResourceOpsRead(t *testing.T) {
+	deployed := "DEPLOYED"
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"found", http.StatusOK, ""},
+		{"not found", http.StatusNotFound, "agent 'a' not found"},
+		{"server error", http.StatusInternalServerError, "get agent failed with status 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := clienttest.New(sdk.Agent{Status: &deployed})
+			agent.GetStatus = tt.status
+			item, err := agentOps(agent).Read("a")
+			if tt.wantErr == "" {
+				if err != nil || item == nil || *item.Status != deployed {
+					t.Errorf("Read() = %v, %v", item, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	ops := agentOps(clienttest.New(sdk.Agent{}))
+	ops.Get = func() (int, *sdk.Agent, error) { return http.StatusOK, nil, nil }
+	if _, err := ops.Read("a"); err == nil || !strings.Contains(err.Error(), "get agent failed with status 200") {
+		t.Errorf("Read() of an empty body error = %v", err)
+	}
+	ops.Get = func() (int, *sdk.Agent, error) { return 0, nil, errors.New("connection refused") }
+	if _, err := ops.Read("a"); err == nil || !strings.Contains(err.Error(), "failed to get agent") {
+		t.Errorf("Read() error = %v", err)
+	}
+}
+
+func TestResourceOpsWrite(t *testing.T) {
+	agent := clienttest.New(sdk.Agent{})
+	if err := agentOps(agent).Write(nil, "a", sdk.Agent{}); err != nil || len(agent.Updates) != 1 {
+		t.Errorf("Write() = %v, updates = %d", err, len(agent.Updates))
+	}
+
+	agent.UpdateStatus = http.StatusConflict
+	if err := agentOps(agent).Write(nil, "a", sdk.Agent{}); err == nil || !strings.Contains(err.Error(), "status 409") {
+		t.Errorf("Write() error = %v", err)
+	}
+}
+
+func TestResourceOpsRuntimeOf(t *testing.T) {
+	ops := agentOps(clienttest.New(sdk.Agent{}))
+	agent := &sdk.Agent{}
+	runtime := ops.RuntimeOf(agent)
+	if runtime == nil || agent.Spec == nil || agent.Spec.Runtime != runtime {
+		t.Errorf("RuntimeOf() should create the spec and runtime of the agent")
+	}
+}
+
+func TestResourceOpsWait(t *testing.T) {
+	deployed := "DEPLOYED"
+	ops := agentOps(clienttest.New(sdk.Agent{Status: &deployed}))
+
+	if waited, err := ops.Wait(context.Background(), "a", "false"); waited || err != nil {
+		t.Errorf("Wait(false) = %v, %v, want no wait", waited, err)
+	}
+	if waited, err := ops.Wait(context.Background(), "a", ""); !waited || err != nil {
+		t.Errorf("Wait() = %v, %v, want a wait on a deployed agent", waited, err)
+	}
+}
+
+func TestResourceStatusChecker(t *testing.T) {
+	deployed := "DEPLOYED"
+	checker := client.ResourceStatusChecker[sdk.Agent]{Ops: agentOps(clienttest.New(sdk.Agent{Status: &deployed}))}
+
+	resource, err := checker.GetResource(context.Background(), "a")
+	if err != nil || checker.ExtractStatus(resource) != "DEPLOYED" {
+		t.Errorf("status = %v, %v", resource, err)
+	}
+	if got := checker.ExtractStatus(&sdk.Agent{}); got != "DEPLOYING" {
+		t.Errorf("ExtractStatus() without status = %s", got)
+	}
+
+	integration := client.IntegrationOps(context.Background(), nil, "i")
+	if got := (client.ResourceStatusChecker[sdk.IntegrationConnection]{Ops: integration}).ExtractStatus(&sdk.IntegrationConnection{}); got != "DEPLOYING" {
+		t.Errorf("ExtractStatus() of a type without status = %s", got)
+	}
+}
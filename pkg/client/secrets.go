@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// secretsPath is the endpoint of workspace secrets, which the SDK does not wrap.
+// A secret is created or replaced with
+//
+//	PUT /secrets/NAME {"name": "NAME", "value": "VALUE"}
+//
+// answering 200, 201 or 204, and deleted with
+//
+//	DELETE /secrets/NAME
+//
+// answering 200 or 204, or 404 when it does not exist. Both take the workspace
+// and bearer token headers used by the SDK. Environment values reference a
+// secret as ${secrets.NAME}, resolved by the runtime when the resource is
+// deployed, so a resource must be redeployed to see a new value.
+const secretsPath = "/secrets"
+
+// secretNamePattern matches the names accepted for workspace secrets
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,127}$`)
+
+// secretReferencePattern matches an environment value that references a
+// workspace secret
+var secretReferencePattern = regexp.MustCompile(`^\$\{secrets\.([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// SecretReference returns the environment value referencing a workspace secret,
+// which the runtime resolves when the resource is deployed
+func SecretReference(name string) string {
+	return "${secrets." + name + "}"
+}
+
+// ReferencedSecret returns the workspace secret an environment value refers to
+func ReferencedSecret(value string) (string, bool) {
+	match := secretReferencePattern.FindStringSubmatch(value)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ValidateSecretName checks that a name can be used for a workspace secret
+func ValidateSecretName(name string) error {
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name '%s' (letters, digits and '_', not starting with a digit)", name)
+	}
+	return nil
+}
+
+// SecretsClient writes workspace secrets
+type SecretsClient struct {
+	endpoint    string
+	workspace   string
+	credentials sdk.Credentials
+	httpClient  *http.Client
+}
+
+// NewSecretsClient creates a secrets client for the configured workspace
+func NewSecretsClient(cfg *config.Config) *SecretsClient {
+	return &SecretsClient{
+		endpoint:    strings.TrimRight(cfg.APIEndpoint, "/"),
+		workspace:   cfg.Workspace,
+		credentials: cfg.Credentials,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Put creates or replaces a workspace secret
+func (c *SecretsClient) Put(ctx context.Context, name, value string) error {
+	if err := ValidateSecretName(name); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{"name": name, "value": value})
+	if err != nil {
+		return fmt.Errorf("failed to encode secret: %w", err)
+	}
+
+	status, err := c.do(ctx, http.MethodPut, name, body)
+	if err != nil {
+		return fmt.Errorf("failed to store secret '%s': %w", name, err)
+	}
+	if status != http.StatusOK && status != http.StatusCreated && status != http.StatusNoContent {
+		return fmt.Errorf("store secret '%s' failed with status %d", name, status)
+	}
+	return nil
+}
+
+// Delete deletes a workspace secret; deleting a missing secret succeeds
+func (c *SecretsClient) Delete(ctx context.Context, name string) error {
+	if err := ValidateSecretName(name); err != nil {
+		return err
+	}
+
+	status, err := c.do(ctx, http.MethodDelete, name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete secret '%s': %w", name, err)
+	}
+	if status != http.StatusOK && status != http.StatusNoContent && status != http.StatusNotFound {
+		return fmt.Errorf("delete secret '%s' failed with status %d", name, status)
+	}
+	return nil
+}
+
+// do sends a request on one secret and returns the status code
+func (c *SecretsClient) do(ctx context.Context, method, name string, body []byte) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+secretsPath+"/"+url.PathEscape(name), reader)
+	if err != nil {
+		return 0, err
+	}
+
+	token, err := bearerToken(ctx, c.credentials)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-Blaxel-Authorization", "Bearer "+token)
+	req.Header.Set("X-Blaxel-Workspace", c.workspace)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// The response may echo the value, so it is never shown
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func TestSecretsClient(t *testing.T) {
+	stored := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Blaxel-Authorization"); got != "Bearer test-key" {
+			t.Errorf("authorization = %q", got)
+		}
+		if got := r.Header.Get("X-Blaxel-Workspace"); got != "test" {
+			t.Errorf("workspace = %q", got)
+		}
+		name, ok := strings.CutPrefix(r.URL.Path, secretsPath+"/")
+		if !ok {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			var secret struct{ Name, Value string }
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &secret); err != nil || secret.Name != name {
+				t.Errorf("body = %s", body)
+			}
+			stored[name] = secret.Value
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body) // echoes the value, which must not leak
+		case http.MethodDelete:
+			if _, ok := stored[name]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(stored, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	secrets := NewSecretsClient(&config.Config{APIEndpoint: server.URL, Workspace: "test", Credentials: sdk.Credentials{APIKey: "test-key"}})
+	ctx := context.Background()
+
+	if err := secrets.Put(ctx, "AGENT_A_KEY", "sk-value"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if stored["AGENT_A_KEY"] != "sk-value" {
+		t.Errorf("stored = %v", stored)
+	}
+	if err := secrets.Delete(ctx, "AGENT_A_KEY"); err != nil || len(stored) != 0 {
+		t.Errorf("Delete() = %v, stored = %v", err, stored)
+	}
+	if err := secrets.Delete(ctx, "AGENT_A_KEY"); err != nil {
+		t.Errorf("Delete() of a missing secret = %v", err)
+	}
+	if err := secrets.Put(ctx, "1INVALID", "v"); err == nil {
+		t.Error("Put() should validate the name")
+	}
+}
+
+func TestSecretsClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"value": "sk-echoed"}`))
+	}))
+	defer server.Close()
+
+	secrets := NewSecretsClient(&config.Config{APIEndpoint: server.URL, Workspace: "test", Credentials: sdk.Credentials{APIKey: "test-key"}})
+	err := secrets.Put(context.Background(), "KEY", "sk-echoed")
+	if err == nil || !strings.Contains(err.Error(), "status 403") || strings.Contains(err.Error(), "sk-echoed") {
+		t.Errorf("Put() error = %v", err)
+	}
+	if err := secrets.Delete(context.Background(), "KEY"); err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
+
+func TestSecretReference(t *testing.T) {
+	tests := []struct {
+		value  string
+		secret string
+		ok     bool
+	}{
+		{SecretReference("AGENT_A_KEY"), "AGENT_A_KEY", true},
+		{"${secrets.KEY}", "KEY", true},
+		{"prefix ${secrets.KEY}", "", false},
+		{"${secrets.1KEY}", "", false},
+		{"${env.KEY}", "", false},
+		{"plain", "", false},
+	}
+
+	for _, tt := range tests {
+		secret, ok := ReferencedSecret(tt.value)
+		if secret != tt.secret || ok != tt.ok {
+			t.Errorf("ReferencedSecret(%q) = %q, %v, want %q, %v", tt.value, secret, ok, tt.secret, tt.ok)
+		}
+	}
+}
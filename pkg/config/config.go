@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blaxel-ai/toolkit/sdk"
 )
@@ -18,6 +20,71 @@ type Config struct {
 	// Server configuration
 	ReadOnly bool
 	Debug    bool
+	// CacheTTL is how long list/get responses are cached (0 disables the cache)
+	CacheTTL time.Duration
+	// Glob patterns selecting which tools are exposed (disable wins over enable)
+	EnableTools  []string
+	DisableTools []string
+	// DynamicToolsets exposes only the toolset discovery tools at startup
+	DynamicToolsets bool
+	// AuditLog is the JSONL file write tool calls are recorded to
+	// (empty for the default location in the log directory, "off" to disable)
+	AuditLog string
+	// AllowRevealSecrets lets callers turn redaction off with revealSecrets,
+	// outside read-only mode
+	AllowRevealSecrets bool
+	// DryRun makes every create/update/delete tool return the request it would
+	// send instead of sending it
+	DryRun bool
+	// Protected lists the resources that delete, update and run tools must not touch
+	Protected Protection
+	// Limits caps how often and how many times at once tools may be called
+	Limits Limits
+	// OutputFormat is the default format of list and get results
+	// (text, json, yaml or markdown)
+	OutputFormat string
+	// MaxOutputChars caps the length of tool results; longer results are
+	// shortened and kept for read_output (0 disables the cap)
+	MaxOutputChars int
+}
+
+// Limits configures token-bucket rate limits and concurrency caps
+type Limits struct {
+	// Session limits every call made by one client session, whatever the tool
+	Session *Rate `yaml:"session"`
+	// Tools limits the tools matching each rule's glob pattern; every matching rule applies
+	Tools []ToolLimit `yaml:"tools"`
+}
+
+// Rate is a token bucket refilled at PerMinute tokens per minute, holding at
+// most Burst tokens (PerMinute when unset)
+type Rate struct {
+	PerMinute float64 `yaml:"perMinute"`
+	Burst     int     `yaml:"burst"`
+}
+
+// ToolLimit limits the calls to the tools matching Tool
+type ToolLimit struct {
+	Tool          string  `yaml:"tool"`
+	PerMinute     float64 `yaml:"perMinute"`
+	Burst         int     `yaml:"burst"`
+	MaxConcurrent int     `yaml:"maxConcurrent"`
+}
+
+// Empty reports whether no limit is configured
+func (l Limits) Empty() bool {
+	return l.Session == nil && len(l.Tools) == 0
+}
+
+// Protection selects protected resources by name glob or by label
+type Protection struct {
+	Names  []string          `yaml:"names"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// Empty reports whether no resource is protected
+func (p Protection) Empty() bool {
+	return len(p.Names) == 0 && len(p.Labels) == 0
 }
 
 // Load loads configuration from environment variables
@@ -62,19 +129,63 @@ func Load() (*Config, error) {
 		runEndpoint = "https://run.blaxel.dev"
 	}
 
+	// Cache list/get responses for a short time by default
+	cacheTTL := 30 * time.Second
+	if ttl := os.Getenv("BL_CACHE_TTL"); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BL_CACHE_TTL %q: %w", ttl, err)
+		}
+		cacheTTL = parsed
+	}
+
+	// Tool results are not capped unless asked to
+	maxOutputChars := 0
+	if chars := os.Getenv("BL_MAX_OUTPUT_CHARS"); chars != "" {
+		parsed, err := strconv.Atoi(chars)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid BL_MAX_OUTPUT_CHARS %q: expected a non-negative number", chars)
+		}
+		maxOutputChars = parsed
+	}
+
 	cfg := &Config{
-		APIEndpoint: apiEndpoint,
-		RunEndpoint: runEndpoint,
-		Workspace:   workspace,
-		Env:         env,
-		Debug:       os.Getenv("BL_DEBUG") == "true",
-		ReadOnly:    os.Getenv("BL_READ_ONLY") == "true",
-		Credentials: credentials,
+		APIEndpoint:        apiEndpoint,
+		RunEndpoint:        runEndpoint,
+		Workspace:          workspace,
+		Env:                env,
+		Debug:              os.Getenv("BL_DEBUG") == "true",
+		ReadOnly:           os.Getenv("BL_READ_ONLY") == "true",
+		DryRun:             os.Getenv("BL_DRY_RUN") == "true",
+		AllowRevealSecrets: os.Getenv("BL_ALLOW_REVEAL_SECRETS") == "true",
+		OutputFormat:       os.Getenv("BL_OUTPUT_FORMAT"),
+		Credentials:        credentials,
+		CacheTTL:           cacheTTL,
+		AuditLog:           os.Getenv("BL_AUDIT_LOG"),
+		MaxOutputChars:     maxOutputChars,
+	}
+
+	if path := os.Getenv("BL_MCP_CONFIG"); path != "" {
+		if err := cfg.ApplyFile(path); err != nil {
+			return nil, err
+		}
 	}
 
 	return cfg, nil
 }
 
+// ParseList parses a comma-separated list, dropping empty entries
+func ParseList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // ParseToolsets parses a comma-separated list of toolsets
 func ParseToolsets(toolsets string) map[string]bool {
 	result := make(map[string]bool)
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the optional server configuration file (YAML or JSON), passed with
+// --config or BL_MCP_CONFIG
+type File struct {
+	ReadOnly           bool       `yaml:"readOnly"`
+	EnableTools        []string   `yaml:"enableTools"`
+	DisableTools       []string   `yaml:"disableTools"`
+	DynamicToolsets    bool       `yaml:"dynamicToolsets"`
+	AuditLog           string     `yaml:"auditLog"`
+	DryRun             bool       `yaml:"dryRun"`
+	AllowRevealSecrets bool       `yaml:"allowRevealSecrets"`
+	Protected          Protection `yaml:"protected"`
+	Limits             Limits     `yaml:"limits"`
+	OutputFormat       string     `yaml:"outputFormat"`
+	MaxOutputChars     int        `yaml:"maxOutputChars"`
+}
+
+// LoadFile reads and parses a configuration file
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return &file, nil
+}
+
+// ApplyFile merges the settings of a configuration file into the config.
+// Command-line flags are applied afterwards and take precedence.
+func (c *Config) ApplyFile(path string) error {
+	file, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if file.ReadOnly {
+		c.ReadOnly = true
+	}
+	if len(file.EnableTools) > 0 {
+		c.EnableTools = file.EnableTools
+	}
+	if len(file.DisableTools) > 0 {
+		c.DisableTools = file.DisableTools
+	}
+	if file.DynamicToolsets {
+		c.DynamicToolsets = true
+	}
+	if file.AuditLog != "" {
+		c.AuditLog = file.AuditLog
+	}
+	if file.DryRun {
+		c.DryRun = true
+	}
+	if file.AllowRevealSecrets {
+		c.AllowRevealSecrets = true
+	}
+	if !file.Protected.Empty() {
+		c.Protected = file.Protected
+	}
+	if !file.Limits.Empty() {
+		c.Limits = file.Limits
+	}
+	if file.OutputFormat != "" {
+		c.OutputFormat = file.OutputFormat
+	}
+	if file.MaxOutputChars > 0 {
+		c.MaxOutputChars = file.MaxOutputChars
+	}
+
+	return nil
+}
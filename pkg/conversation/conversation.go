@@ -0,0 +1,210 @@
+package conversation
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
+)
+
+// Roles of the turns of a conversation
+const (
+	RoleUser  = "user"
+	RoleAgent = "agent"
+)
+
+// DirName is the directory created in the log directory to keep transcripts
+const DirName = "conversations"
+
+// ErrNotFound is returned when a thread has no transcript
+var ErrNotFound = errors.New("conversation not found")
+
+// threadIDPattern restricts thread IDs to what can safely name a file
+var threadIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Turn is one message of a conversation, stored as one line of its transcript
+type Turn struct {
+	Time    time.Time `json:"time"`
+	Agent   string    `json:"agent"`
+	Role    string    `json:"role"`
+	Content string    `json:"content"`
+}
+
+// Thread is the transcript of a conversation with an agent
+type Thread struct {
+	ID    string `json:"threadId"`
+	Agent string `json:"agent"`
+	Turns []Turn `json:"turns"`
+}
+
+// Summary describes a thread without its turns
+type Summary struct {
+	ID        string    `json:"threadId"`
+	Agent     string    `json:"agent"`
+	Turns     int       `json:"turns"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Store keeps conversation transcripts as JSONL files, one per thread, in a
+// directory per workspace
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// Open returns the store of a workspace in the log directory
+func Open(workspace string) (*Store, error) {
+	logDir, err := logger.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(filepath.Join(logDir, DirName, workspace)), nil
+}
+
+// NewStore returns a store keeping transcripts in dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// NewThreadID returns a random thread ID
+func NewThreadID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("thread-%d", time.Now().UnixNano())
+	}
+	return "thread-" + hex.EncodeToString(b)
+}
+
+// ValidateThreadID checks that a thread ID given by a client can be stored
+func ValidateThreadID(id string) error {
+	if !threadIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid threadId %q: use up to 128 letters, digits, '.', '_' or '-'", id)
+	}
+	return nil
+}
+
+// Append adds turns to the transcript of a thread. A thread belongs to the
+// agent it was started with. Secrets in the content are masked like in tool
+// results before it is written, whatever revealSecrets says, so transcripts
+// never hold them.
+func (s *Store) Append(threadID string, turns ...Turn) error {
+	if err := ValidateThreadID(threadID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create conversation directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path(threadID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open conversation: %w", err)
+	}
+	defer file.Close()
+
+	for _, turn := range turns {
+		turn.Content = redact.Output(turn.Content)
+		data, err := json.Marshal(turn)
+		if err != nil {
+			return fmt.Errorf("failed to encode conversation turn: %w", err)
+		}
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write conversation: %w", err)
+		}
+	}
+	return nil
+}
+
+// Read returns the transcript of a thread
+func (s *Store) Read(threadID string) (*Thread, error) {
+	if err := ValidateThreadID(threadID); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path(threadID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open conversation: %w", err)
+	}
+	defer file.Close()
+
+	thread := &Thread{ID: threadID, Turns: []Turn{}}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var turn Turn
+		if err := json.Unmarshal(scanner.Bytes(), &turn); err != nil {
+			// Skip a line left incomplete by an interrupted write
+			continue
+		}
+		if thread.Agent == "" {
+			thread.Agent = turn.Agent
+		}
+		thread.Turns = append(thread.Turns, turn)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read conversation: %w", err)
+	}
+
+	return thread, nil
+}
+
+// List returns the threads with an agent, or with every agent when agent is
+// empty, most recently updated first
+func (s *Store) List(agent string) ([]Summary, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Summary{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+
+	summaries := []Summary{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		thread, err := s.Read(id)
+		if err != nil || len(thread.Turns) == 0 {
+			continue
+		}
+		if agent != "" && thread.Agent != agent {
+			continue
+		}
+		summaries = append(summaries, Summary{
+			ID:        id,
+			Agent:     thread.Agent,
+			Turns:     len(thread.Turns),
+			UpdatedAt: thread.Turns[len(thread.Turns)-1].Time,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt) })
+	return summaries, nil
+}
+
+func (s *Store) path(threadID string) string {
+	return filepath.Join(s.dir, threadID+".jsonl")
+}
@@ -0,0 +1,56 @@
+package conversation
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendRedactsContent(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name    string
+		content string
+		secret  string
+		kept    string
+	}{
+		{"text", "use OPENAI_API_KEY=sk-abc123 for the call", "sk-abc123", "for the call"},
+		{"json", `{"answer": "done", "token": "t0k3n"}`, "t0k3n", `"answer": "done"`},
+		{"bearer", "Authorization: Bearer abc.def-ghi", "abc.def-ghi", "Authorization: Bearer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := "thread-" + tt.name
+			if err := store.Append(id, Turn{Time: now, Agent: "a", Role: RoleAgent, Content: tt.content}); err != nil {
+				t.Fatalf("Append() error = %v", err)
+			}
+
+			data, err := os.ReadFile(store.path(id))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), tt.secret) {
+				t.Errorf("transcript holds the secret: %s", data)
+			}
+
+			thread, err := store.Read(id)
+			if err != nil || len(thread.Turns) != 1 {
+				t.Fatalf("Read() = %v, %v", thread, err)
+			}
+			if content := thread.Turns[0].Content; strings.Contains(content, tt.secret) || !strings.Contains(content, tt.kept) {
+				t.Errorf("content = %q", content)
+			}
+		})
+	}
+}
+
+func TestAppendInvalidThread(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Append("../escape", Turn{Content: "x"}); err == nil {
+		t.Error("Append() should reject a thread ID that is not a file name")
+	}
+}
@@ -0,0 +1,348 @@
+package credentials
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// jwt returns an unsigned token expiring at exp, or without exp when zero
+func jwt(t *testing.T, exp time.Time, claims map[string]interface{}) string {
+	t.Helper()
+	if claims == nil {
+		claims = map[string]interface{}{}
+	}
+	if !exp.IsZero() {
+		claims["exp"] = exp.Unix()
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// tokenServer is an OAuth token endpoint answering with fresh tokens
+type tokenServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []map[string]string
+	auth     []string
+	status   int
+	token    string
+}
+
+func newTokenServer(t *testing.T, token string) *tokenServer {
+	s := &tokenServer{status: http.StatusOK, token: token}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/oauth/token" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var payload map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, payload)
+		s.auth = append(s.auth, r.Header.Get("Authorization"))
+		w.WriteHeader(s.status)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": s.token, "refresh_token": "rotated", "expires_in": 3600})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func newManager(t *testing.T, endpoint string, creds sdk.Credentials) *Manager {
+	t.Setenv("HOME", t.TempDir()) // refreshed credentials are saved like the CLI does
+	return &Manager{
+		workspace:   "test",
+		apiEndpoint: endpoint,
+		credentials: creds,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func TestParseClaims(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	claims, err := parseClaims(jwt(t, exp, map[string]interface{}{"email": "me@example.com"}))
+	if err != nil {
+		t.Fatalf("parseClaims() error = %v", err)
+	}
+	if got, ok := expiryFromClaims(claims); !ok || !got.Equal(exp) {
+		t.Errorf("expiryFromClaims() = %v, %v, want %v", got, ok, exp)
+	}
+
+	claims, _ = parseClaims(jwt(t, time.Time{}, nil))
+	if _, ok := expiryFromClaims(claims); ok {
+		t.Error("a token without exp has no expiry")
+	}
+
+	for _, token := range []string{"bl_apikey", "a.b", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte("not json")) + ".c"} {
+		if _, err := parseClaims(token); err == nil {
+			t.Errorf("parseClaims(%q) should fail", token)
+		}
+	}
+}
+
+func TestManagerTokenRefreshesProactively(t *testing.T) {
+	fresh := jwt(t, time.Now().Add(time.Hour), nil)
+	server := newTokenServer(t, fresh)
+
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		refreshed bool
+	}{
+		{"valid", time.Hour, false},
+		{"within the skew", refreshSkew / 2, true},
+		{"expired", -time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := jwt(t, time.Now().Add(tt.expiresIn), nil)
+			manager := newManager(t, server.URL, sdk.Credentials{AccessToken: current, RefreshToken: "refresh", DeviceCode: "device"})
+			before := server.calls()
+
+			token, err := manager.Token(context.Background())
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if refreshed := server.calls() > before; refreshed != tt.refreshed {
+				t.Fatalf("refreshed = %v, want %v", refreshed, tt.refreshed)
+			}
+			if tt.refreshed {
+				if token != fresh || manager.credentials.RefreshToken != "rotated" || manager.credentials.ExpiresIn != 3600 {
+					t.Errorf("credentials after refresh = %+v", manager.credentials)
+				}
+				payload := server.requests[len(server.requests)-1]
+				if payload["grant_type"] != "refresh_token" || payload["refresh_token"] != "refresh" || payload["device_code"] != "device" {
+					t.Errorf("refresh payload = %v", payload)
+				}
+			} else if token != current {
+				t.Errorf("Token() = %q, want the current token", token)
+			}
+		})
+	}
+}
+
+func TestManagerRefreshClientCredentials(t *testing.T) {
+	server := newTokenServer(t, "new-token")
+	manager := newManager(t, server.URL, sdk.Credentials{ClientCredentials: "Y2xpZW50OnNlY3JldA=="})
+
+	token, err := manager.Token(context.Background())
+	if err != nil || token != "new-token" {
+		t.Fatalf("Token() = %q, %v", token, err)
+	}
+	if server.requests[0]["grant_type"] != "client_credentials" || server.auth[0] != "Basic Y2xpZW50OnNlY3JldA==" {
+		t.Errorf("request = %v with %q", server.requests[0], server.auth[0])
+	}
+}
+
+func TestManagerRefreshErrors(t *testing.T) {
+	expired := jwt(t, time.Now().Add(-time.Hour), nil)
+
+	manager := newManager(t, "http://unused", sdk.Credentials{AccessToken: expired})
+	if _, err := manager.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "no refresh token") {
+		t.Errorf("Token() without refresh token error = %v", err)
+	}
+
+	server := newTokenServer(t, "")
+	server.status = http.StatusUnauthorized
+	manager = newManager(t, server.URL, sdk.Credentials{AccessToken: expired, RefreshToken: "revoked"})
+	if _, err := manager.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("Token() with a rejected refresh error = %v", err)
+	}
+
+	server.status = http.StatusOK
+	if _, err := manager.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "no access token") {
+		t.Errorf("Token() with an empty refresh response error = %v", err)
+	}
+}
+
+func TestManagerAPIKey(t *testing.T) {
+	manager := newManager(t, "http://unused", sdk.Credentials{APIKey: "bl_key"})
+	if token, err := manager.Token(context.Background()); err != nil || token != "bl_key" {
+		t.Errorf("Token() = %q, %v", token, err)
+	}
+	if err := manager.Refresh(context.Background()); err != nil {
+		t.Errorf("Refresh() of an API key = %v", err)
+	}
+}
+
+// apiServer answers 401 to tokens other than valid, and records what it received
+type apiServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	valid  string
+	tokens []string
+	bodies []string
+}
+
+func newAPIServer(t *testing.T, valid string) *apiServer {
+	s := &apiServer{valid: valid}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		token := strings.TrimPrefix(r.Header.Get("X-Blaxel-Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.tokens = append(s.tokens, token)
+		s.bodies = append(s.bodies, string(body))
+		if token != s.valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestTransportRefreshesAndReplaysOn401(t *testing.T) {
+	// The current token looks valid, but the API revoked it
+	revoked := jwt(t, time.Now().Add(time.Hour), map[string]interface{}{"sub": "old"})
+	fresh := jwt(t, time.Now().Add(time.Hour), map[string]interface{}{"sub": "new"})
+	tokens := newTokenServer(t, fresh)
+	api := newAPIServer(t, fresh)
+
+	manager := newManager(t, tokens.URL, sdk.Credentials{AccessToken: revoked, RefreshToken: "refresh"})
+	transport := NewTransport(http.DefaultTransport, manager, &config.Config{APIEndpoint: api.URL})
+
+	req, _ := http.NewRequest(http.MethodPut, api.URL+"/agents/a", strings.NewReader(`{"spec":{}}`))
+	req.Header.Set("X-Blaxel-Authorization", "Bearer "+revoked)
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want the replayed request to succeed", resp.StatusCode)
+	}
+	if len(api.tokens) != 2 || api.tokens[0] != revoked || api.tokens[1] != fresh {
+		t.Errorf("tokens sent = %v", api.tokens)
+	}
+	if api.bodies[1] != `{"spec":{}}` {
+		t.Errorf("replayed body = %q", api.bodies[1])
+	}
+	if tokens.calls() != 1 {
+		t.Errorf("refreshes = %d, want 1", tokens.calls())
+	}
+}
+
+func TestTransportDoesNotReplayNonReplayableBody(t *testing.T) {
+	token := jwt(t, time.Now().Add(time.Hour), nil)
+	tokens := newTokenServer(t, "unused")
+	api := newAPIServer(t, "other")
+
+	manager := newManager(t, tokens.URL, sdk.Credentials{AccessToken: token, RefreshToken: "refresh"})
+	transport := NewTransport(http.DefaultTransport, manager, &config.Config{APIEndpoint: api.URL})
+
+	// A body without GetBody cannot be sent twice
+	req, _ := http.NewRequest(http.MethodPost, api.URL+"/jobs", io.NopCloser(strings.NewReader("stream")))
+	req.GetBody = nil
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized || len(api.tokens) != 1 || tokens.calls() != 0 {
+		t.Errorf("status = %d after %d requests and %d refreshes, want the 401 as is", resp.StatusCode, len(api.tokens), tokens.calls())
+	}
+}
+
+func TestTransportHostFiltering(t *testing.T) {
+	token := jwt(t, time.Now().Add(time.Hour), nil)
+	api := newAPIServer(t, token)
+	other := newAPIServer(t, token)
+
+	manager := newManager(t, "http://unused", sdk.Credentials{AccessToken: token})
+	transport := NewTransport(http.DefaultTransport, manager, &config.Config{APIEndpoint: api.URL + "/v0", RunEndpoint: "https://run.example.com"})
+
+	if !transport.hosts[strings.TrimPrefix(api.URL, "http://")] || !transport.hosts["run.example.com"] || len(transport.hosts) != 2 {
+		t.Errorf("hosts = %v", transport.hosts)
+	}
+
+	for _, server := range []*apiServer{api, other} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if api.tokens[0] != token {
+		t.Errorf("the API should receive the token, got %q", api.tokens[0])
+	}
+	if other.tokens[0] != "" {
+		t.Errorf("another host should not receive the token, got %q", other.tokens[0])
+	}
+}
+
+func TestTransportAPIKeyUntouched(t *testing.T) {
+	api := newAPIServer(t, "bl_key")
+	manager := newManager(t, "http://unused", sdk.Credentials{APIKey: "bl_key"})
+	transport := NewTransport(http.DefaultTransport, manager, &config.Config{APIEndpoint: api.URL})
+
+	req, _ := http.NewRequest(http.MethodGet, api.URL, nil)
+	req.Header.Set("X-Blaxel-Authorization", "Bearer bl_key")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || api.tokens[0] != "bl_key" {
+		t.Errorf("status = %d with %v", resp.StatusCode, api.tokens)
+	}
+}
+
+func TestInstallWrapsDefaultTransport(t *testing.T) {
+	base := http.DefaultTransport
+	t.Cleanup(func() {
+		http.DefaultTransport = base
+		defaultManager = nil
+	})
+
+	fresh := jwt(t, time.Now().Add(time.Hour), nil)
+	tokens := newTokenServer(t, fresh)
+	api := newAPIServer(t, fresh)
+	expired := jwt(t, time.Now().Add(-time.Minute), nil)
+
+	t.Setenv("HOME", t.TempDir())
+	manager := Install(&config.Config{Workspace: "test", APIEndpoint: api.URL, Credentials: sdk.Credentials{AccessToken: expired, RefreshToken: "refresh"}})
+	manager.apiEndpoint = tokens.URL
+	if Default() != manager {
+		t.Error("Install should set the default manager")
+	}
+
+	// A zero http.Client, as the SDK client uses, goes through the wrapped transport
+	req, _ := http.NewRequest(http.MethodGet, api.URL+"/agents", nil)
+	req.Header.Set("X-Blaxel-Authorization", "Bearer "+expired)
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || api.tokens[0] != fresh {
+		t.Errorf("status = %d with tokens %v, want the refreshed token", resp.StatusCode, api.tokens)
+	}
+}
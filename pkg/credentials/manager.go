@@ -0,0 +1,302 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// Authentication methods reported by Manager.Method
+const (
+	MethodAPIKey            = "api_key"
+	MethodDeviceLogin       = "device_login"
+	MethodClientCredentials = "client_credentials"
+)
+
+// refreshSkew is how long before the actual expiry a token is considered expired,
+// so that a request never leaves with a token that dies in flight
+const refreshSkew = 60 * time.Second
+
+// Manager keeps the credentials of the running server up to date.
+// API keys are used as-is; access tokens obtained through `bl login` or client
+// credentials are refreshed transparently when they are about to expire.
+type Manager struct {
+	mu          sync.Mutex
+	workspace   string
+	apiEndpoint string
+	credentials sdk.Credentials
+	httpClient  *http.Client
+}
+
+// LoginStatus describes the authentication state reported by the login_status tool
+type LoginStatus struct {
+	Method      string     `json:"method"`
+	Workspace   string     `json:"workspace"`
+	Identity    string     `json:"identity,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	ExpiresIn   string     `json:"expiresIn,omitempty"`
+	Expired     bool       `json:"expired"`
+	Refreshable bool       `json:"refreshable"`
+}
+
+var (
+	// Default manager instance
+	defaultManager *Manager
+)
+
+// Init creates the default credentials manager from the loaded configuration
+func Init(cfg *config.Config) *Manager {
+	defaultManager = &Manager{
+		workspace:   cfg.Workspace,
+		apiEndpoint: strings.TrimRight(cfg.APIEndpoint, "/"),
+		credentials: cfg.Credentials,
+		// Use the unwrapped transport so refresh calls never recurse into Transport
+		httpClient: &http.Client{
+			Transport: http.DefaultTransport,
+			Timeout:   30 * time.Second,
+		},
+	}
+	return defaultManager
+}
+
+// Default returns the manager created by Init, or nil if Init was not called
+func Default() *Manager {
+	return defaultManager
+}
+
+// Method returns the authentication method in use
+func (m *Manager) Method() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.method()
+}
+
+func (m *Manager) method() string {
+	switch {
+	case m.credentials.ClientCredentials != "":
+		return MethodClientCredentials
+	case m.credentials.AccessToken != "":
+		return MethodDeviceLogin
+	default:
+		return MethodAPIKey
+	}
+}
+
+// Token returns a valid bearer token, refreshing the access token first if it
+// has expired or is about to
+func (m *Manager) Token(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.method() == MethodAPIKey {
+		return m.credentials.APIKey, nil
+	}
+
+	if m.credentials.AccessToken == "" || m.expiresWithin(refreshSkew) {
+		if err := m.refresh(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return m.credentials.AccessToken, nil
+}
+
+// Refresh forces a token refresh, regardless of the current expiry
+func (m *Manager) Refresh(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.method() == MethodAPIKey {
+		return nil
+	}
+	return m.refresh(ctx)
+}
+
+// Status reports the authentication method, identity and token expiry.
+// An expired token is refreshed first when possible so the report reflects
+// what the next tool call will use.
+func (m *Manager) Status(ctx context.Context) LoginStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	method := m.method()
+	if method != MethodAPIKey && m.expiresWithin(0) && m.refreshable() {
+		if err := m.refresh(ctx); err != nil {
+			logger.Warnf("Could not refresh credentials while reporting login status: %v", err)
+		}
+	}
+
+	status := LoginStatus{
+		Method:      method,
+		Workspace:   m.workspace,
+		Refreshable: m.refreshable(),
+	}
+
+	if method == MethodAPIKey {
+		return status
+	}
+
+	claims, err := parseClaims(m.credentials.AccessToken)
+	if err != nil {
+		logger.Warnf("Could not decode access token: %v", err)
+		return status
+	}
+
+	for _, key := range []string{"email", "preferred_username", "name", "sub"} {
+		if value, ok := claims[key].(string); ok && value != "" {
+			status.Identity = value
+			break
+		}
+	}
+
+	if expiresAt, ok := expiryFromClaims(claims); ok {
+		status.ExpiresAt = &expiresAt
+		remaining := time.Until(expiresAt)
+		status.Expired = remaining <= 0
+		if !status.Expired {
+			status.ExpiresIn = remaining.Round(time.Second).String()
+		}
+	}
+
+	return status
+}
+
+// refreshable reports whether the manager holds what it needs to get a new token
+func (m *Manager) refreshable() bool {
+	return m.credentials.RefreshToken != "" || m.credentials.ClientCredentials != ""
+}
+
+// expiresWithin reports whether the access token expires within the given window.
+// Tokens without a readable expiry are assumed to be valid.
+func (m *Manager) expiresWithin(window time.Duration) bool {
+	claims, err := parseClaims(m.credentials.AccessToken)
+	if err != nil {
+		return false
+	}
+	expiresAt, ok := expiryFromClaims(claims)
+	if !ok {
+		return false
+	}
+	return time.Until(expiresAt) <= window
+}
+
+// refresh obtains a new access token. The caller must hold m.mu.
+func (m *Manager) refresh(ctx context.Context) error {
+	var payload map[string]string
+	var basicAuth string
+
+	switch {
+	case m.credentials.RefreshToken != "":
+		payload = map[string]string{
+			"grant_type":    "refresh_token",
+			"refresh_token": m.credentials.RefreshToken,
+			"device_code":   m.credentials.DeviceCode,
+			"client_id":     "blaxel",
+		}
+	case m.credentials.ClientCredentials != "":
+		payload = map[string]string{
+			"grant_type": "client_credentials",
+		}
+		basicAuth = m.credentials.ClientCredentials
+	default:
+		return fmt.Errorf("access token expired and no refresh token is available (run 'bl login %s')", m.workspace)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal refresh request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.apiEndpoint+"/oauth/token", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build refresh request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if basicAuth != "" {
+		req.Header.Set("Authorization", "Basic "+basicAuth)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to refresh access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read refresh response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token refresh failed with status %d (run 'bl login %s')", resp.StatusCode, m.workspace)
+	}
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(respBody, &token); err != nil {
+		return fmt.Errorf("failed to parse refresh response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("token refresh returned no access token")
+	}
+
+	m.credentials.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		m.credentials.RefreshToken = token.RefreshToken
+	}
+	if token.ExpiresIn > 0 {
+		m.credentials.ExpiresIn = token.ExpiresIn
+	}
+
+	// Persist like the CLI does, so `bl` and other servers share the rotated token
+	if m.credentials.RefreshToken != "" {
+		sdk.SaveCredentials(m.workspace, m.credentials)
+	}
+
+	logger.Printf("Refreshed access token for workspace '%s'", m.workspace)
+	return nil
+}
+
+// parseClaims decodes the payload of a JWT without verifying its signature.
+// The token is only inspected locally to find its expiry and subject.
+func parseClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token payload: %w", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse token payload: %w", err)
+	}
+
+	return claims, nil
+}
+
+// expiryFromClaims extracts the exp claim as a time
+func expiryFromClaims(claims map[string]interface{}) (time.Time, bool) {
+	exp, ok := claims["exp"].(float64)
+	if !ok || exp <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
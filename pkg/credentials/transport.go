@@ -0,0 +1,110 @@
+package credentials
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+)
+
+// authHeaders are the headers the SDK may carry the bearer token in
+var authHeaders = []string{"X-Blaxel-Authorization", "Authorization"}
+
+// Transport is an http.RoundTripper that keeps the bearer token of outgoing
+// Blaxel requests current. It refreshes the token before it expires and, if the
+// API still answers 401, forces one refresh and replays the request.
+type Transport struct {
+	Base    http.RoundTripper
+	Manager *Manager
+	hosts   map[string]bool
+}
+
+// NewTransport wraps base so that requests to the configured Blaxel endpoints
+// use the manager's current token. Requests to any other host are untouched.
+func NewTransport(base http.RoundTripper, manager *Manager, cfg *config.Config) *Transport {
+	hosts := make(map[string]bool)
+	for _, endpoint := range []string{cfg.APIEndpoint, cfg.RunEndpoint} {
+		if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+			hosts[u.Host] = true
+		}
+	}
+
+	return &Transport{
+		Base:    base,
+		Manager: manager,
+		hosts:   hosts,
+	}
+}
+
+// Install creates the default manager and wraps http.DefaultTransport with it.
+// The SDK client is created without an http.Client of its own, so it sends its
+// requests through http.DefaultTransport, like any zero http.Client; wrapping
+// it lets every handler pick up refreshed tokens without rebuilding clients.
+func Install(cfg *config.Config) *Manager {
+	manager := Init(cfg)
+	http.DefaultTransport = NewTransport(http.DefaultTransport, manager, cfg)
+	return manager
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.hosts[req.URL.Host] || t.Manager.Method() == MethodAPIKey {
+		return t.Base.RoundTrip(req)
+	}
+
+	token, err := t.Manager.Token(req.Context())
+	if err != nil {
+		// Let the request go out with whatever the SDK set; the API error is more useful
+		logger.Warnf("Could not refresh access token: %v", err)
+		return t.Base.RoundTrip(req)
+	}
+
+	resp, err := t.Base.RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The token was rejected before its advertised expiry; refresh once and retry
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	if refreshErr := t.Manager.Refresh(req.Context()); refreshErr != nil {
+		logger.Warnf("Access token rejected and refresh failed: %v", refreshErr)
+		return resp, nil
+	}
+	token, err = t.Manager.Token(req.Context())
+	if err != nil {
+		return resp, nil
+	}
+
+	retry := withToken(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+
+	resp.Body.Close()
+	return t.Base.RoundTrip(retry)
+}
+
+// withToken returns a copy of req carrying the given bearer token
+func withToken(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+
+	replaced := false
+	for _, header := range authHeaders {
+		if clone.Header.Get(header) != "" {
+			clone.Header.Set(header, "Bearer "+token)
+			replaced = true
+		}
+	}
+	if !replaced {
+		clone.Header.Set(authHeaders[0], "Bearer "+token)
+	}
+
+	return clone
+}
@@ -0,0 +1,99 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/truncate"
+)
+
+// CheckResult is the outcome of one check on an output
+type CheckResult struct {
+	Type   string `json:"type"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// compile validates a check and prepares its regular expression
+func (c *Check) compile() error {
+	switch c.Type {
+	case CheckExact:
+	case CheckContains:
+		if c.Value == "" {
+			return fmt.Errorf("contains needs a value")
+		}
+	case CheckRegex:
+		pattern := c.Pattern
+		if pattern == "" {
+			return fmt.Errorf("regex needs a pattern")
+		}
+		if c.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		c.regex = regex
+	case CheckJSONSchema:
+		if c.Schema == nil {
+			return fmt.Errorf("jsonSchema needs a schema")
+		}
+		if err := checkSchema(c.Schema, "$"); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown check type '%s' (expected one of %s)", c.Type, strings.Join(CheckTypes, ", "))
+	}
+	return nil
+}
+
+// Evaluate runs the check on the output of a case
+func (c *Check) Evaluate(output string) CheckResult {
+	result := CheckResult{Type: c.Type}
+
+	switch c.Type {
+	case CheckExact:
+		actual, expected := strings.TrimSpace(output), strings.TrimSpace(c.Value)
+		if c.IgnoreCase {
+			result.Passed = strings.EqualFold(actual, expected)
+		} else {
+			result.Passed = actual == expected
+		}
+		if !result.Passed {
+			result.Detail = fmt.Sprintf("expected %q", truncate.Elide(expected, 200))
+		}
+
+	case CheckContains:
+		if c.IgnoreCase {
+			result.Passed = strings.Contains(strings.ToLower(output), strings.ToLower(c.Value))
+		} else {
+			result.Passed = strings.Contains(output, c.Value)
+		}
+		if !result.Passed {
+			result.Detail = fmt.Sprintf("does not contain %q", c.Value)
+		}
+
+	case CheckRegex:
+		result.Passed = c.regex.MatchString(output)
+		if !result.Passed {
+			result.Detail = fmt.Sprintf("does not match %s", c.Pattern)
+		}
+
+	case CheckJSONSchema:
+		var value interface{}
+		if err := json.Unmarshal([]byte(output), &value); err != nil {
+			result.Detail = "output is not JSON"
+			break
+		}
+		violations := validateSchema(c.Schema, value, "$")
+		result.Passed = len(violations) == 0
+		if !result.Passed {
+			result.Detail = strings.Join(violations, "; ")
+		}
+	}
+
+	return result
+}
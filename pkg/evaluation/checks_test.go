@@ -0,0 +1,64 @@
+package evaluation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  Check
+		output string
+		passed bool
+		detail string
+	}{
+		{"exact", Check{Type: CheckExact, Value: "Paris"}, " Paris\n", true, ""},
+		{"exact mismatch", Check{Type: CheckExact, Value: "Paris"}, "paris", false, `expected "Paris"`},
+		{"exact ignore case", Check{Type: CheckExact, Value: "Paris", IgnoreCase: true}, "PARIS", true, ""},
+		{"contains", Check{Type: CheckContains, Value: "42"}, "the answer is 42.", true, ""},
+		{"contains mismatch", Check{Type: CheckContains, Value: "Yes"}, "yes", false, `does not contain "Yes"`},
+		{"contains ignore case", Check{Type: CheckContains, Value: "Yes", IgnoreCase: true}, "yes", true, ""},
+		{"regex", Check{Type: CheckRegex, Pattern: `^\d{3}-\d{4}$`}, "555-1234", true, ""},
+		{"regex mismatch", Check{Type: CheckRegex, Pattern: `^\d+$`}, "abc", false, `does not match ^\d+$`},
+		{"regex ignore case", Check{Type: CheckRegex, Pattern: "^ok$", IgnoreCase: true}, "OK", true, ""},
+		{"json schema", Check{Type: CheckJSONSchema, Schema: map[string]interface{}{"type": "object", "required": []interface{}{"id"}}}, `{"id": 1}`, true, ""},
+		{"json schema violation", Check{Type: CheckJSONSchema, Schema: map[string]interface{}{"type": "object", "required": []interface{}{"id"}}}, `{}`, false, "$: missing required property 'id'"},
+		{"not json", Check{Type: CheckJSONSchema, Schema: map[string]interface{}{"type": "object"}}, "Sure! {", false, "output is not JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.check.compile(); err != nil {
+				t.Fatalf("compile() error = %v", err)
+			}
+			result := tt.check.Evaluate(tt.output)
+			if result.Passed != tt.passed || result.Detail != tt.detail || result.Type != tt.check.Type {
+				t.Errorf("Evaluate() = %+v, want passed=%v detail=%q", result, tt.passed, tt.detail)
+			}
+		})
+	}
+}
+
+func TestCheckCompile(t *testing.T) {
+	tests := []struct {
+		name  string
+		check Check
+		err   string
+	}{
+		{"contains without value", Check{Type: CheckContains}, "contains needs a value"},
+		{"regex without pattern", Check{Type: CheckRegex}, "regex needs a pattern"},
+		{"invalid regex", Check{Type: CheckRegex, Pattern: "("}, "invalid regex"},
+		{"schema missing", Check{Type: CheckJSONSchema}, "jsonSchema needs a schema"},
+		{"unsupported keyword", Check{Type: CheckJSONSchema, Schema: map[string]interface{}{"oneOf": []interface{}{}}}, "unsupported schema keyword 'oneOf'"},
+		{"unknown type", Check{Type: "llm"}, "unknown check type 'llm'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.check.compile(); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("compile() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package evaluation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// MaxCases bounds the number of cases of one evaluation run
+const MaxCases = 200
+
+// Check types
+const (
+	CheckExact      = "exact"
+	CheckContains   = "contains"
+	CheckRegex      = "regex"
+	CheckJSONSchema = "jsonSchema"
+)
+
+// CheckTypes are the supported check types
+var CheckTypes = []string{CheckExact, CheckContains, CheckRegex, CheckJSONSchema}
+
+// Case is one input sent to the agent and the checks its output must pass
+type Case struct {
+	ID       string          `json:"id"`
+	Input    string          `json:"input"`
+	Context  json.RawMessage `json:"context,omitempty"`
+	Expected *string         `json:"expected,omitempty"` // shorthand for an exact check
+	Checks   []Check         `json:"checks,omitempty"`
+}
+
+// Check is a rubric check on the output of a case
+type Check struct {
+	Type       string                 `json:"type"`
+	Value      string                 `json:"value,omitempty"`      // exact and contains
+	Pattern    string                 `json:"pattern,omitempty"`    // regex
+	Schema     map[string]interface{} `json:"schema,omitempty"`     // jsonSchema
+	IgnoreCase bool                   `json:"ignoreCase,omitempty"` // exact, contains and regex
+
+	regex *regexp.Regexp
+}
+
+// ParseCases reads cases from a JSON array or from JSON lines, and validates them
+func ParseCases(data []byte) ([]Case, error) {
+	var cases []Case
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &cases); err != nil {
+			return nil, fmt.Errorf("invalid dataset: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "//") {
+				continue
+			}
+			var c Case
+			if err := json.Unmarshal([]byte(text), &c); err != nil {
+				return nil, fmt.Errorf("invalid dataset line %d: %w", line, err)
+			}
+			cases = append(cases, c)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read dataset: %w", err)
+		}
+	}
+
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("the dataset has no cases")
+	}
+	if len(cases) > MaxCases {
+		return nil, fmt.Errorf("the dataset has %d cases, more than the maximum of %d", len(cases), MaxCases)
+	}
+
+	seen := make(map[string]bool, len(cases))
+	for i := range cases {
+		c := &cases[i]
+		if c.ID == "" {
+			c.ID = fmt.Sprintf("case-%d", i+1)
+		}
+		if seen[c.ID] {
+			return nil, fmt.Errorf("duplicate case id '%s'", c.ID)
+		}
+		seen[c.ID] = true
+
+		if c.Input == "" {
+			return nil, fmt.Errorf("case '%s': input is required", c.ID)
+		}
+		if c.Expected != nil {
+			c.Checks = append([]Check{{Type: CheckExact, Value: *c.Expected}}, c.Checks...)
+		}
+		if len(c.Checks) == 0 {
+			return nil, fmt.Errorf("case '%s': expected or at least one check is required", c.ID)
+		}
+		for j := range c.Checks {
+			if err := c.Checks[j].compile(); err != nil {
+				return nil, fmt.Errorf("case '%s', check %d: %w", c.ID, j+1, err)
+			}
+		}
+	}
+
+	return cases, nil
+}
+
+// LoadCases reads cases from a JSONL (or JSON array) file
+func LoadCases(path string) ([]Case, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return ParseCases(data)
+}
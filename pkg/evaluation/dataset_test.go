@@ -0,0 +1,80 @@
+package evaluation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseCases(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		ids     []string
+		checks  []int
+		wantErr string
+	}{
+		{
+			name:   "json lines",
+			data:   "{\"id\": \"a\", \"input\": \"hi\", \"expected\": \"hello\"}\n\n// comment\n{\"input\": \"2+2\", \"checks\": [{\"type\": \"contains\", \"value\": \"4\"}]}\n",
+			ids:    []string{"a", "case-2"},
+			checks: []int{1, 1},
+		},
+		{
+			name:   "json array",
+			data:   `[{"input": "x", "expected": "y", "checks": [{"type": "regex", "pattern": "y"}]}]`,
+			ids:    []string{"case-1"},
+			checks: []int{2},
+		},
+		{name: "empty", data: "  \n", wantErr: "the dataset has no cases"},
+		{name: "invalid line", data: "{\"input\": \"a\", \"expected\": \"b\"}\nnot json", wantErr: "invalid dataset line 2"},
+		{name: "invalid array", data: `[{"input": 1}]`, wantErr: "invalid dataset"},
+		{name: "duplicate id", data: `[{"id": "a", "input": "x", "expected": "y"}, {"id": "a", "input": "x", "expected": "y"}]`, wantErr: "duplicate case id 'a'"},
+		{name: "missing input", data: `[{"id": "a", "expected": "y"}]`, wantErr: "case 'a': input is required"},
+		{name: "no checks", data: `[{"id": "a", "input": "x"}]`, wantErr: "case 'a': expected or at least one check is required"},
+		{name: "invalid check", data: `[{"id": "a", "input": "x", "checks": [{"type": "jsonSchema", "schema": {"anyOf": []}}]}]`, wantErr: "case 'a', check 1: unsupported schema keyword 'anyOf'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases, err := ParseCases([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseCases() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCases() error = %v", err)
+			}
+			if len(cases) != len(tt.ids) {
+				t.Fatalf("ParseCases() = %d cases, want %d", len(cases), len(tt.ids))
+			}
+			for i, c := range cases {
+				if c.ID != tt.ids[i] || len(c.Checks) != tt.checks[i] {
+					t.Errorf("case %d = %s with %d checks, want %s with %d", i, c.ID, len(c.Checks), tt.ids[i], tt.checks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCasesExpectedFirst(t *testing.T) {
+	cases, err := ParseCases([]byte(`[{"input": "x", "expected": "y", "checks": [{"type": "contains", "value": "y"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cases[0].Checks[0].Type != CheckExact || cases[0].Checks[0].Value != "y" {
+		t.Errorf("expected should become the first check, got %+v", cases[0].Checks)
+	}
+}
+
+func TestParseCasesMaximum(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i <= MaxCases; i++ {
+		fmt.Fprintf(&b, "{\"input\": \"%d\", \"expected\": \"x\"}\n", i)
+	}
+	if _, err := ParseCases([]byte(b.String())); err == nil || !strings.Contains(err.Error(), "more than the maximum") {
+		t.Errorf("ParseCases() error = %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package evaluation
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/truncate"
+)
+
+// maxOutputChars bounds the agent output kept for each case
+const maxOutputChars = 500
+
+// Invoker sends the input of a case to the agent and returns its output
+type Invoker func(ctx context.Context, c Case) (string, error)
+
+// CaseResult is the outcome of one case
+type CaseResult struct {
+	ID        string        `json:"id"`
+	Passed    bool          `json:"passed"`
+	LatencyMs int64         `json:"latencyMs"`
+	Checks    []CheckResult `json:"checks,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	Output    string        `json:"output,omitempty"`
+}
+
+// Run is the result of an evaluation of an agent on a dataset
+type Run struct {
+	ID           string       `json:"runId"`
+	Agent        string       `json:"agent"`
+	StartedAt    time.Time    `json:"startedAt"`
+	DurationMs   int64        `json:"durationMs"`
+	Cases        int          `json:"cases"`
+	Passed       int          `json:"passed"`
+	Failed       int          `json:"failed"` // outputs failing a check
+	Errors       int          `json:"errors"` // invocations that failed
+	Score        float64      `json:"score"`
+	AvgLatencyMs int64        `json:"avgLatencyMs"`
+	MaxLatencyMs int64        `json:"maxLatencyMs"`
+	Results      []CaseResult `json:"results"`
+}
+
+// Execute runs the cases through invoke, at most concurrency at a time, each
+// within timeout. done is called after each case, from the goroutine that ran it.
+func Execute(ctx context.Context, agent string, cases []Case, concurrency int, timeout time.Duration, invoke Invoker, done func(result CaseResult, completed int)) *Run {
+	run := &Run{
+		ID:        NewRunID(),
+		Agent:     agent,
+		StartedAt: time.Now().UTC(),
+		Cases:     len(cases),
+		Results:   make([]CaseResult, len(cases)),
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+	slots := make(chan struct{}, concurrency)
+
+	for i := range cases {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				run.Results[i] = CaseResult{ID: cases[i].ID, Error: ctx.Err().Error()}
+				return
+			}
+
+			result := runCase(ctx, cases[i], timeout, invoke)
+			run.Results[i] = result
+
+			mu.Lock()
+			completed++
+			n := completed
+			mu.Unlock()
+			if done != nil {
+				done(result, n)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var totalLatency int64
+	for _, result := range run.Results {
+		switch {
+		case result.Passed:
+			run.Passed++
+		case result.Error != "":
+			run.Errors++
+		default:
+			run.Failed++
+		}
+		totalLatency += result.LatencyMs
+		run.MaxLatencyMs = max(run.MaxLatencyMs, result.LatencyMs)
+	}
+	run.Score = ratio(run.Passed, run.Cases)
+	run.AvgLatencyMs = totalLatency / int64(run.Cases)
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+
+	return run
+}
+
+// runCase invokes the agent on one case and runs its checks on the output
+func runCase(ctx context.Context, c Case, timeout time.Duration, invoke Invoker) CaseResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	output, err := invoke(ctx, c)
+	result := CaseResult{ID: c.ID, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Output = truncate.Elide(output, maxOutputChars)
+	result.Passed = true
+	for i := range c.Checks {
+		check := c.Checks[i].Evaluate(output)
+		result.Checks = append(result.Checks, check)
+		result.Passed = result.Passed && check.Passed
+	}
+	return result
+}
+
+// LatencyChange is the latency of a case in two runs
+type LatencyChange struct {
+	ID          string `json:"id"`
+	BaselineMs  int64  `json:"baselineMs"`
+	CandidateMs int64  `json:"candidateMs"`
+	DeltaMs     int64  `json:"deltaMs"`
+}
+
+// Comparison is the difference between two runs, matching cases by id
+type Comparison struct {
+	Baseline          string          `json:"baseline"`
+	Candidate         string          `json:"candidate"`
+	BaselineScore     float64         `json:"baselineScore"`
+	CandidateScore    float64         `json:"candidateScore"`
+	ScoreDelta        float64         `json:"scoreDelta"`
+	AvgLatencyDeltaMs int64           `json:"avgLatencyDeltaMs"`
+	Regressions       []string        `json:"regressions"`  // passed in the baseline, not in the candidate
+	Fixes             []string        `json:"fixes"`        // failed in the baseline, passed in the candidate
+	StillFailing      []string        `json:"stillFailing"` // failed in both
+	Added             []string        `json:"added,omitempty"`
+	Removed           []string        `json:"removed,omitempty"`
+	Latency           []LatencyChange `json:"latency"`
+}
+
+// Compare diffs a candidate run against a baseline
+func Compare(baseline, candidate *Run) *Comparison {
+	comparison := &Comparison{
+		Baseline:          baseline.ID,
+		Candidate:         candidate.ID,
+		BaselineScore:     baseline.Score,
+		CandidateScore:    candidate.Score,
+		ScoreDelta:        math.Round((candidate.Score-baseline.Score)*1000) / 1000,
+		AvgLatencyDeltaMs: candidate.AvgLatencyMs - baseline.AvgLatencyMs,
+		Regressions:       []string{},
+		Fixes:             []string{},
+		StillFailing:      []string{},
+		Latency:           []LatencyChange{},
+	}
+
+	before := make(map[string]CaseResult, len(baseline.Results))
+	for _, result := range baseline.Results {
+		before[result.ID] = result
+	}
+
+	after := make(map[string]bool, len(candidate.Results))
+	for _, result := range candidate.Results {
+		after[result.ID] = true
+		previous, ok := before[result.ID]
+		if !ok {
+			comparison.Added = append(comparison.Added, result.ID)
+			continue
+		}
+
+		switch {
+		case previous.Passed && !result.Passed:
+			comparison.Regressions = append(comparison.Regressions, result.ID)
+		case !previous.Passed && result.Passed:
+			comparison.Fixes = append(comparison.Fixes, result.ID)
+		case !previous.Passed && !result.Passed:
+			comparison.StillFailing = append(comparison.StillFailing, result.ID)
+		}
+		comparison.Latency = append(comparison.Latency, LatencyChange{
+			ID:          result.ID,
+			BaselineMs:  previous.LatencyMs,
+			CandidateMs: result.LatencyMs,
+			DeltaMs:     result.LatencyMs - previous.LatencyMs,
+		})
+	}
+
+	for _, result := range baseline.Results {
+		if !after[result.ID] {
+			comparison.Removed = append(comparison.Removed, result.ID)
+		}
+	}
+
+	// Largest slowdowns first
+	sort.SliceStable(comparison.Latency, func(i, j int) bool {
+		return comparison.Latency[i].DeltaMs > comparison.Latency[j].DeltaMs
+	})
+
+	return comparison
+}
+
+// ratio returns n/total rounded to three decimals
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(total)*1000) / 1000
+}
@@ -0,0 +1,57 @@
+package evaluation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := &Run{ID: "base", Score: 0.5, AvgLatencyMs: 100, Results: []CaseResult{
+		{ID: "a", Passed: true, LatencyMs: 100},
+		{ID: "b", Passed: false, LatencyMs: 100},
+		{ID: "c", Passed: false, LatencyMs: 100},
+		{ID: "d", Passed: true, LatencyMs: 100},
+		{ID: "gone", Passed: true, LatencyMs: 100},
+	}}
+	candidate := &Run{ID: "cand", Score: 0.6667, AvgLatencyMs: 150, Results: []CaseResult{
+		{ID: "a", Passed: false, LatencyMs: 300},
+		{ID: "b", Passed: true, LatencyMs: 50},
+		{ID: "c", Passed: false, LatencyMs: 100},
+		{ID: "d", Passed: true, LatencyMs: 200},
+		{ID: "new", Passed: true, LatencyMs: 10},
+	}}
+
+	c := Compare(baseline, candidate)
+
+	if c.Baseline != "base" || c.Candidate != "cand" || c.ScoreDelta != 0.167 || c.AvgLatencyDeltaMs != 50 {
+		t.Errorf("Compare() = %+v", c)
+	}
+	for name, pair := range map[string][2][]string{
+		"regressions":  {c.Regressions, {"a"}},
+		"fixes":        {c.Fixes, {"b"}},
+		"stillFailing": {c.StillFailing, {"c"}},
+		"added":        {c.Added, {"new"}},
+		"removed":      {c.Removed, {"gone"}},
+	} {
+		if !reflect.DeepEqual(pair[0], pair[1]) {
+			t.Errorf("%s = %v, want %v", name, pair[0], pair[1])
+		}
+	}
+
+	// Largest slowdowns first
+	order := []string{}
+	for _, l := range c.Latency {
+		order = append(order, l.ID)
+	}
+	if !reflect.DeepEqual(order, []string{"a", "d", "c", "b"}) || c.Latency[0].DeltaMs != 200 {
+		t.Errorf("latency = %+v", c.Latency)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	run := &Run{ID: "r", Results: []CaseResult{{ID: "a", Passed: true}}}
+	c := Compare(run, run)
+	if len(c.Regressions) != 0 || len(c.Fixes) != 0 || len(c.StillFailing) != 0 || c.Added != nil || c.Removed != nil {
+		t.Errorf("Compare() = %+v, want no changes", c)
+	}
+}
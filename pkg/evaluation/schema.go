@@ -0,0 +1,265 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// schemaKeywords are the keywords validateSchema supports; checkSchema rejects
+// the others, rather than let a check pass without applying them
+var schemaKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true,
+	"properties": true, "required": true, "additionalProperties": true,
+	"items": true, "minItems": true, "maxItems": true,
+	"minLength": true, "maxLength": true, "pattern": true,
+	"minimum": true, "maximum": true,
+}
+
+// schemaAnnotations are keywords that do not constrain values
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true,
+	"title": true, "description": true, "default": true, "examples": true,
+}
+
+// checkSchema reports the first keyword of a schema, or of its subschemas,
+// that validateSchema does not support, and invalid patterns
+func checkSchema(schema map[string]interface{}, path string) error {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if schemaAnnotations[key] {
+			continue
+		}
+		if !schemaKeywords[key] {
+			return fmt.Errorf("unsupported schema keyword '%s' at %s (supported: type, enum, const, properties, required, additionalProperties, items, minItems, maxItems, minLength, maxLength, pattern, minimum, maximum)", key, path)
+		}
+
+		switch value := schema[key].(type) {
+		case map[string]interface{}:
+			switch key {
+			case "properties":
+				names := make([]string, 0, len(value))
+				for name := range value {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					property, ok := value[name].(map[string]interface{})
+					if !ok {
+						return fmt.Errorf("property '%s' at %s must be a schema", name, path)
+					}
+					if err := checkSchema(property, path+"."+name); err != nil {
+						return err
+					}
+				}
+			case "additionalProperties":
+				if err := checkSchema(value, path+".*"); err != nil {
+					return err
+				}
+			case "items":
+				if err := checkSchema(value, path+"[]"); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			if key == "items" {
+				return fmt.Errorf("unsupported tuple 'items' at %s (items must be one schema)", path)
+			}
+		case string:
+			if key == "pattern" {
+				if _, err := regexp.Compile(value); err != nil {
+					return fmt.Errorf("invalid pattern at %s: %w", path, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateSchema checks value against a JSON schema and returns the
+// violations found. It supports the keywords used to describe agent outputs:
+// type, enum, const, properties, required, additionalProperties, items,
+// minItems, maxItems, minLength, maxLength, pattern, minimum and maximum.
+// Schemas are checked by checkSchema first, since other keywords are ignored.
+func validateSchema(schema map[string]interface{}, value interface{}, path string) []string {
+	var violations []string
+	fail := func(format string, args ...interface{}) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("expected %v, got %s", joinTypes(types), typeOf(value))
+			return violations
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value is not one of %s", encode(enum))
+		}
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		fail("expected %s", encode(constant))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, present := v[key]; !present {
+						fail("missing required property '%s'", key)
+					}
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				violations = append(violations, validateSchema(property, v[key], path+"."+key)...)
+			} else if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				fail("unexpected property '%s'", key)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				violations = append(violations, validateSchema(additional, v[key], path+"."+key)...)
+			}
+		}
+
+	case []interface{}:
+		if min, ok := number(schema["minItems"]); ok && float64(len(v)) < min {
+			fail("expected at least %v items, got %d", min, len(v))
+		}
+		if max, ok := number(schema["maxItems"]); ok && float64(len(v)) > max {
+			fail("expected at most %v items, got %d", max, len(v))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				violations = append(violations, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if min, ok := number(schema["minLength"]); ok && length < min {
+			fail("expected at least %v characters, got %v", min, length)
+		}
+		if max, ok := number(schema["maxLength"]); ok && length > max {
+			fail("expected at most %v characters, got %v", max, length)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("invalid pattern %s: %v", pattern, err)
+			} else if !regex.MatchString(v) {
+				fail("does not match %s", pattern)
+			}
+		}
+
+	case float64:
+		if min, ok := number(schema["minimum"]); ok && v < min {
+			fail("expected at least %v, got %v", min, v)
+		}
+		if max, ok := number(schema["maximum"]); ok && v > max {
+			fail("expected at most %v, got %v", max, v)
+		}
+	}
+
+	return violations
+}
+
+// schemaTypes returns the types allowed by a type keyword, a name or a list
+func schemaTypes(keyword interface{}) []string {
+	switch t := keyword.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// hasType reports whether a decoded JSON value is of a schema type
+func hasType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	}
+	return typeOf(value) == schemaType
+}
+
+// typeOf returns the schema type name of a decoded JSON value
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return fmt.Sprintf("one of %v", types)
+}
+
+func number(value interface{}) (float64, bool) {
+	n, ok := value.(float64)
+	return n, ok
+}
+
+func encode(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
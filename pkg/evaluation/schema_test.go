@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, data string) map[string]interface{} {
+	t.Helper()
+	var value map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	return value
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["id", "status"],
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"status": {"enum": ["open", "closed"]},
+			"name": {"type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[a-z]+$"},
+			"tags": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string"}},
+			"score": {"type": ["number", "null"], "maximum": 1},
+			"kind": {"const": "order"}
+		}
+	}`
+
+	tests := []struct {
+		name       string
+		value      string
+		violations []string
+	}{
+		{"valid", `{"id": 1, "status": "open", "name": "abc", "tags": ["a"], "score": null, "kind": "order"}`, nil},
+		{"wrong type", `[]`, []string{"$: expected object, got array"}},
+		{"missing required", `{"id": 1}`, []string{"$: missing required property 'status'"}},
+		{"additional property", `{"id": 1, "status": "open", "extra": true}`, []string{"$: unexpected property 'extra'"}},
+		{"not an integer", `{"id": 1.5, "status": "open"}`, []string{"$.id: expected integer, got number"}},
+		{"below minimum", `{"id": 0, "status": "open"}`, []string{"$.id: expected at least 1, got 0"}},
+		{"not in enum", `{"id": 1, "status": "pending"}`, []string{`$.status: value is not one of ["open","closed"]`}},
+		{"string bounds", `{"id": 1, "status": "open", "name": "ABCDEF"}`, []string{"$.name: expected at most 5 characters, got 6", "$.name: does not match ^[a-z]+$"}},
+		{"multibyte length", `{"id": 1, "status": "open", "name": "é"}`, []string{"$.name: expected at least 2 characters, got 1", "$.name: does not match ^[a-z]+$"}},
+		{"array bounds and items", `{"id": 1, "status": "open", "tags": ["a", 2, "c"]}`, []string{"$.tags: expected at most 2 items, got 3", "$.tags[1]: expected string, got number"}},
+		{"type list", `{"id": 1, "status": "open", "score": "high"}`, []string{"$.score: expected one of [number null], got string"}},
+		{"above maximum", `{"id": 1, "status": "open", "score": 2}`, []string{"$.score: expected at most 1, got 2"}},
+		{"const", `{"id": 1, "status": "open", "kind": "invoice"}`, []string{`$.kind: expected "order"`}},
+	}
+
+	compiled := decodeJSON(t, schema)
+	if err := checkSchema(compiled, "$"); err != nil {
+		t.Fatalf("checkSchema() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			got := validateSchema(compiled, value, "$")
+			if strings.Join(got, "\n") != strings.Join(tt.violations, "\n") {
+				t.Errorf("validateSchema() = %q, want %q", got, tt.violations)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaAdditionalPropertiesSchema(t *testing.T) {
+	schema := decodeJSON(t, `{"type": "object", "additionalProperties": {"type": "number"}}`)
+	got := validateSchema(schema, map[string]interface{}{"a": 1.0, "b": "x"}, "$")
+	if len(got) != 1 || got[0] != "$.b: expected number, got string" {
+		t.Errorf("validateSchema() = %q", got)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{"supported", `{"type": "object", "properties": {"a": {"type": "string"}}}`, ""},
+		{"annotations", `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "t", "description": "d", "type": "string", "default": "x"}`, ""},
+		{"ref", `{"$ref": "#/definitions/order"}`, "unsupported schema keyword '$ref' at $"},
+		{"anyOf", `{"anyOf": [{"type": "string"}]}`, "unsupported schema keyword 'anyOf' at $"},
+		{"oneOf", `{"oneOf": [{"type": "string"}]}`, "'oneOf'"},
+		{"allOf", `{"allOf": [{"type": "string"}]}`, "'allOf'"},
+		{"not", `{"not": {"type": "string"}}`, "'not'"},
+		{"format", `{"type": "string", "format": "email"}`, "'format'"},
+		{"exclusiveMinimum", `{"type": "number", "exclusiveMinimum": 0}`, "'exclusiveMinimum'"},
+		{"nested property", `{"properties": {"a": {"properties": {"b": {"format": "date"}}}}}`, "'format' at $.a.b"},
+		{"nested items", `{"items": {"uniqueItems": true}}`, "'uniqueItems' at $[]"},
+		{"nested additional properties", `{"additionalProperties": {"minProperties": 1}}`, "'minProperties' at $.*"},
+		{"tuple items", `{"items": [{"type": "string"}]}`, "unsupported tuple 'items' at $"},
+		{"property not a schema", `{"properties": {"a": true}}`, "property 'a' at $ must be a schema"},
+		{"invalid pattern", `{"pattern": "("}`, "invalid pattern at $"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSchema(decodeJSON(t, tt.schema), "$")
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("checkSchema() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("checkSchema() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
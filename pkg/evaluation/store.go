@@ -0,0 +1,84 @@
+package evaluation
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+)
+
+// DirName is the directory created in the log directory to keep runs
+const DirName = "evaluations"
+
+// runIDPattern matches the IDs given by NewRunID
+var runIDPattern = regexp.MustCompile(`^eval-[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+// NewRunID returns an ID sortable by start time
+func NewRunID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("eval-%s-%s", time.Now().UTC().Format("20060102-150405"), hex.EncodeToString(b))
+}
+
+// Store keeps evaluation runs as JSON files, in a directory per workspace
+type Store struct {
+	dir string
+}
+
+// Open returns the store of a workspace in the log directory
+func Open(workspace string) (*Store, error) {
+	logDir, err := logger.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(filepath.Join(logDir, DirName, workspace)), nil
+}
+
+// NewStore returns a store keeping runs in dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save writes a run
+func (s *Store) Save(run *Run) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create evaluation directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode evaluation: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, run.ID+".json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to save evaluation: %w", err)
+	}
+	return nil
+}
+
+// Load reads a run by ID
+func (s *Store) Load(id string) (*Run, error) {
+	if !runIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid evaluation run id '%s'", id)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("evaluation run '%s' not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evaluation: %w", err)
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to decode evaluation '%s': %w", id, err)
+	}
+	return &run, nil
+}
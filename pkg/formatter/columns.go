@@ -0,0 +1,139 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
+)
+
+// Markdown table columns of each resource model
+
+// AgentColumns maps an agent to table columns
+var AgentColumns = []Column[AgentModel]{
+	{"Name", func(a AgentModel) string { return a.Name }},
+	{"Status", func(a AgentModel) string { return a.Status }},
+	{"Image", func(a AgentModel) string { return stringValue(a.Image) }},
+	{"Generation", func(a AgentModel) string { return stringValue(a.Generation) }},
+	{"Memory (MB)", func(a AgentModel) string { return intValue(a.Memory) }},
+	{"Max Tasks", func(a AgentModel) string { return intValue(a.MaxTasks) }},
+	{"Labels", func(a AgentModel) string { return labelsValue(a.Labels) }},
+	{"Created", func(a AgentModel) string { return timeValue(a.CreatedAt) }},
+}
+
+// JobColumns maps a job to table columns
+var JobColumns = []Column[JobModel]{
+	{"Name", func(j JobModel) string { return j.Name }},
+	{"Status", func(j JobModel) string { return j.Status }},
+	{"Image", func(j JobModel) string { return stringValue(j.Image) }},
+	{"Memory (MB)", func(j JobModel) string { return intValue(j.Memory) }},
+	{"Max Tasks", func(j JobModel) string { return intValue(j.MaxTasks) }},
+	{"Max Retries", func(j JobModel) string { return intValue(j.MaxRetries) }},
+	{"Labels", func(j JobModel) string { return labelsValue(j.Labels) }},
+	{"Created", func(j JobModel) string { return timeValue(j.CreatedAt) }},
+}
+
+// ModelColumns maps a model API to table columns
+var ModelColumns = []Column[ModelAPI]{
+	{"Name", func(m ModelAPI) string { return m.Name }},
+	{"Status", func(m ModelAPI) string { return m.Status }},
+	{"Type", func(m ModelAPI) string { return stringValue(m.Type) }},
+	{"Model", func(m ModelAPI) string { return stringValue(m.ModelName) }},
+	{"Memory (MB)", func(m ModelAPI) string { return intValue(m.Memory) }},
+	{"Labels", func(m ModelAPI) string { return labelsValue(m.Labels) }},
+	{"Created", func(m ModelAPI) string { return timeValue(m.CreatedAt) }},
+}
+
+// FunctionColumns maps an MCP server to table columns
+var FunctionColumns = []Column[FunctionModel]{
+	{"Name", func(f FunctionModel) string { return f.Name }},
+	{"Status", func(f FunctionModel) string { return f.Status }},
+	{"Image", func(f FunctionModel) string { return stringValue(f.Image) }},
+	{"Generation", func(f FunctionModel) string { return stringValue(f.Generation) }},
+	{"Memory (MB)", func(f FunctionModel) string { return intValue(f.Memory) }},
+	{"Integrations", func(f FunctionModel) string { return strings.Join(f.IntegrationConnections, ", ") }},
+	{"Labels", func(f FunctionModel) string { return labelsValue(f.Labels) }},
+	{"Created", func(f FunctionModel) string { return timeValue(f.CreatedAt) }},
+}
+
+// SandboxColumns maps a sandbox to table columns
+var SandboxColumns = []Column[SandboxModel]{
+	{"Name", func(s SandboxModel) string { return s.Name }},
+	{"Status", func(s SandboxModel) string { return s.Status }},
+	{"Image", func(s SandboxModel) string { return stringValue(s.Image) }},
+	{"Memory (MB)", func(s SandboxModel) string { return intValue(s.Memory) }},
+	{"Ports", func(s SandboxModel) string { return intsValue(s.Ports) }},
+	{"TTL", func(s SandboxModel) string { return stringValue(s.TTL) }},
+	{"Expires", func(s SandboxModel) string { return timeValue(s.Expires) }},
+	{"Labels", func(s SandboxModel) string { return labelsValue(s.Labels) }},
+	{"Created", func(s SandboxModel) string { return timeValue(s.CreatedAt) }},
+}
+
+// IntegrationColumns maps an integration to table columns. Secrets are
+// listed by key name only.
+var IntegrationColumns = []Column[IntegrationModel]{
+	{"Name", func(i IntegrationModel) string { return i.Name }},
+	{"Secrets", func(i IntegrationModel) string { return strings.Join(redact.Keys(i.Secrets), ", ") }},
+	{"Config", func(i IntegrationModel) string { return labelsValue(i.Config) }},
+	{"Labels", func(i IntegrationModel) string { return labelsValue(i.Labels) }},
+	{"Created", func(i IntegrationModel) string { return timeValue(i.CreatedAt) }},
+}
+
+// UserColumns maps a workspace user to table columns
+var UserColumns = []Column[UserModel]{
+	{"Email", func(u UserModel) string { return u.Email }},
+	{"Name", func(u UserModel) string { return u.Name }},
+	{"Role", func(u UserModel) string { return u.Role }},
+	{"Accepted", func(u UserModel) string { return strconv.FormatBool(u.Accepted) }},
+	{"Email Verified", func(u UserModel) string { return strconv.FormatBool(u.EmailVerified) }},
+}
+
+// ServiceAccountColumns maps a service account to table columns
+var ServiceAccountColumns = []Column[ServiceAccountModel]{
+	{"Name", func(s ServiceAccountModel) string { return s.Name }},
+	{"Client ID", func(s ServiceAccountModel) string { return s.ClientID }},
+	{"Description", func(s ServiceAccountModel) string { return s.Description }},
+	{"Created", func(s ServiceAccountModel) string { return timeValue(s.CreatedAt) }},
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intValue(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func intsValue(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func timeValue(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// labelsValue renders labels as sorted key=value pairs
+func labelsValue(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
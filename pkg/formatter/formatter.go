@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
 )
 
 // FormatAgents formats a list of agent models into a readable string
@@ -263,7 +265,8 @@ func FormatIntegrations(integrations []IntegrationModel) string {
 		}
 
 		if len(integration.Secrets) > 0 {
-			b.WriteString(fmt.Sprintf("  Secrets: %v\n", formatLabels(integration.Secrets)))
+			// Only key names and fingerprints, never the values
+			b.WriteString(fmt.Sprintf("  Secrets: %v\n", formatLabels(redact.MaskMap(integration.Secrets))))
 		}
 
 		if len(integration.Config) > 0 {
@@ -8,96 +8,96 @@ import (
 
 // AgentModel represents a simple agent model
 type AgentModel struct {
-	Name       string
-	Status     string
-	Image      *string
-	Memory     *int
-	Generation *string
-	MaxTasks   *int
-	Labels     map[string]string
-	CreatedAt  *time.Time
+	Name       string            `json:"name"`
+	Status     string            `json:"status,omitempty"`
+	Image      *string           `json:"image,omitempty"`
+	Memory     *int              `json:"memory,omitempty"`
+	Generation *string           `json:"generation,omitempty"`
+	MaxTasks   *int              `json:"maxConcurrentTasks,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	CreatedAt  *time.Time        `json:"createdAt,omitempty"`
 }
 
 // JobModel represents a simple job model
 type JobModel struct {
-	Name       string
-	Status     string
-	Image      *string
-	Memory     *int
-	MaxTasks   *int
-	MaxRetries *int
-	Labels     map[string]string
-	CreatedAt  *time.Time
+	Name       string            `json:"name"`
+	Status     string            `json:"status,omitempty"`
+	Image      *string           `json:"image,omitempty"`
+	Memory     *int              `json:"memory,omitempty"`
+	MaxTasks   *int              `json:"maxConcurrentTasks,omitempty"`
+	MaxRetries *int              `json:"maxRetries,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	CreatedAt  *time.Time        `json:"createdAt,omitempty"`
 }
 
 // ModelAPI represents a simple model API model
 type ModelAPI struct {
-	Name      string
-	Status    string
-	Type      *string
-	ModelName *string
-	Memory    *int
-	Labels    map[string]string
-	CreatedAt *time.Time
+	Name      string            `json:"name"`
+	Status    string            `json:"status,omitempty"`
+	Type      *string           `json:"type,omitempty"`
+	ModelName *string           `json:"model,omitempty"`
+	Memory    *int              `json:"memory,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt *time.Time        `json:"createdAt,omitempty"`
 }
 
 // FunctionModel represents a simple function/MCP server model
 type FunctionModel struct {
-	Name                   string
-	Status                 string
-	Image                  *string
-	Memory                 *int
-	Generation             *string
-	IntegrationConnections []string
-	Labels                 map[string]string
-	CreatedAt              *time.Time
+	Name                   string            `json:"name"`
+	Status                 string            `json:"status,omitempty"`
+	Image                  *string           `json:"image,omitempty"`
+	Memory                 *int              `json:"memory,omitempty"`
+	Generation             *string           `json:"generation,omitempty"`
+	IntegrationConnections []string          `json:"integrationConnections,omitempty"`
+	Labels                 map[string]string `json:"labels,omitempty"`
+	CreatedAt              *time.Time        `json:"createdAt,omitempty"`
 }
 
 // SandboxModel represents a simple sandbox model
 type SandboxModel struct {
-	Name       string
-	Status     string
-	Image      *string
-	Memory     *int
-	Generation *string
-	TTL        *string
-	Expires    *time.Time
-	Ports      []int
-	Labels     map[string]string
-	CreatedAt  *time.Time
+	Name       string            `json:"name"`
+	Status     string            `json:"status,omitempty"`
+	Image      *string           `json:"image,omitempty"`
+	Memory     *int              `json:"memory,omitempty"`
+	Generation *string           `json:"generation,omitempty"`
+	TTL        *string           `json:"ttl,omitempty"`
+	Expires    *time.Time        `json:"expires,omitempty"`
+	Ports      []int             `json:"ports,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	CreatedAt  *time.Time        `json:"createdAt,omitempty"`
 }
 
 // IntegrationModel represents a simple integration model
 type IntegrationModel struct {
-	Name      string
-	Secrets   map[string]string
-	Config    map[string]string
-	Labels    map[string]string
-	CreatedAt *time.Time
+	Name      string            `json:"name"`
+	Secrets   map[string]string `json:"secrets,omitempty"`
+	Config    map[string]string `json:"config,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt *time.Time        `json:"createdAt,omitempty"`
 }
 
 // UserModel represents a simple user model
 type UserModel struct {
-	Email         string
-	Name          string
-	Role          string
-	Accepted      bool
-	EmailVerified bool
+	Email         string `json:"email"`
+	Name          string `json:"name"`
+	Role          string `json:"role,omitempty"`
+	Accepted      bool   `json:"accepted"`
+	EmailVerified bool   `json:"emailVerified"`
 }
 
 // ServiceAccountModel represents a simple service account model
 type ServiceAccountModel struct {
-	Name        string
-	ClientID    string
-	Description string
-	CreatedAt   *time.Time
+	Name        string     `json:"name"`
+	ClientID    string     `json:"clientId,omitempty"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
 }
 
 // TemplateModel represents a simple template model
 type TemplateModel struct {
-	Name          string
-	Description   *string
-	Topics        []string
-	StarCount     *int
-	DownloadCount *int
+	Name          string   `json:"name"`
+	Description   *string  `json:"description,omitempty"`
+	Topics        []string `json:"topics,omitempty"`
+	StarCount     *int     `json:"stars,omitempty"`
+	DownloadCount *int     `json:"downloads,omitempty"`
 }
@@ -0,0 +1,270 @@
+package formatter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format for list and get results
+type Format string
+
+const (
+	// FormatText is the historical output: prose for lists, indented JSON for gets
+	FormatText Format = "text"
+	// FormatJSON emits indented JSON
+	FormatJSON Format = "json"
+	// FormatYAML emits YAML
+	FormatYAML Format = "yaml"
+	// FormatMarkdown emits a markdown table
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats lists the accepted format names
+var Formats = []string{string(FormatText), string(FormatJSON), string(FormatYAML), string(FormatMarkdown)}
+
+// ParseFormat validates a format name; empty means text
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML:
+		return FormatYAML, nil
+	case FormatMarkdown:
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (expected one of %s)", name, strings.Join(Formats, ", "))
+}
+
+type formatKey struct{}
+
+// WithFormat returns a context carrying the output format of the current call
+func WithFormat(ctx context.Context, format Format) context.Context {
+	return context.WithValue(ctx, formatKey{}, format)
+}
+
+// FormatFromContext returns the output format of the current call, text by default
+func FormatFromContext(ctx context.Context) Format {
+	if format, ok := ctx.Value(formatKey{}).(Format); ok {
+		return format
+	}
+	return FormatText
+}
+
+// Column maps a model to one markdown table column
+type Column[T any] struct {
+	Header string
+	Value  func(T) string
+}
+
+// Render formats a list of models. text renders the historical prose output,
+// columns the markdown table; json and yaml use the models' JSON tags.
+func Render[T any](format Format, items []T, columns []Column[T], text func([]T) string) (string, error) {
+	switch format {
+	case FormatJSON, FormatYAML:
+		if items == nil {
+			items = []T{}
+		}
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to format response: %w", err)
+		}
+		if format == FormatJSON {
+			return string(data), nil
+		}
+		return toYAML(data)
+
+	case FormatMarkdown:
+		headers := make([]string, len(columns))
+		for i, column := range columns {
+			headers[i] = column.Header
+		}
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			row := make([]string, len(columns))
+			for j, column := range columns {
+				row[j] = column.Value(item)
+			}
+			rows[i] = row
+		}
+		return MarkdownTable(headers, rows), nil
+
+	default:
+		return text(items), nil
+	}
+}
+
+// Convert re-encodes a JSON result in another format. It returns false when
+// the result is not JSON or the format needs no conversion, so results that a
+// handler already rendered are left alone.
+func Convert(format Format, result string) (string, bool) {
+	if format != FormatYAML && format != FormatMarkdown {
+		return "", false
+	}
+
+	trimmed := strings.TrimSpace(result)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return "", false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	if format == FormatYAML {
+		converted, err := toYAML([]byte(trimmed))
+		if err != nil {
+			return "", false
+		}
+		return converted, true
+	}
+
+	// An array of objects becomes one row per object, anything else one row
+	// per field, with nested fields flattened to dotted paths
+	if items, ok := value.([]interface{}); ok {
+		var headers []string
+		seen := map[string]bool{}
+		flattened := make([]map[string]string, len(items))
+		for i, item := range items {
+			flattened[i] = map[string]string{}
+			flatten("", item, flattened[i])
+			keys := make([]string, 0, len(flattened[i]))
+			for key := range flattened[i] {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if !seen[key] {
+					seen[key] = true
+					headers = append(headers, key)
+				}
+			}
+		}
+
+		rows := make([][]string, len(flattened))
+		for i, fields := range flattened {
+			row := make([]string, len(headers))
+			for j, header := range headers {
+				row[j] = fields[header]
+			}
+			rows[i] = row
+		}
+		return MarkdownTable(headers, rows), true
+	}
+
+	fields := map[string]string{}
+	flatten("", value, fields)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, len(keys))
+	for i, key := range keys {
+		rows[i] = []string{key, fields[key]}
+	}
+	return MarkdownTable([]string{"Field", "Value"}, rows), true
+}
+
+// MarkdownTable renders a markdown table; cells are escaped so that pipes and
+// newlines do not break the layout
+func MarkdownTable(headers []string, rows [][]string) string {
+	if len(rows) == 0 {
+		return "_No results_\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(escapeCells(headers), " | ") + " |\n")
+	separators := make([]string, len(headers))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	b.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+	for _, row := range rows {
+		b.WriteString("| " + strings.Join(escapeCells(row), " | ") + " |\n")
+	}
+	return b.String()
+}
+
+func escapeCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		escaped[i] = strings.ReplaceAll(cell, "\n", "<br>")
+	}
+	return escaped
+}
+
+// flatten collects the scalar fields of a decoded JSON value under dotted paths
+func flatten(prefix string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flatten(path, item, fields)
+		}
+	case []interface{}:
+		scalars := make([]string, 0, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				flatten(fmt.Sprintf("%s[%d]", prefix, i), item, fields)
+			default:
+				scalars = append(scalars, fmt.Sprint(item))
+			}
+		}
+		if len(scalars) > 0 {
+			fields[prefix] = strings.Join(scalars, ", ")
+		}
+	case nil:
+		fields[prefix] = ""
+	default:
+		fields[prefix] = fmt.Sprint(v)
+	}
+}
+
+// toYAML converts a JSON document to YAML, keeping the JSON field names and
+// their order
+func toYAML(data []byte) (string, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return "", fmt.Errorf("failed to format response: %w", err)
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return "", fmt.Errorf("failed to format response: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// blockStyle drops the JSON flow style and quoting so the document reads as YAML
+func blockStyle(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if node.Tag == "!!str" {
+			node.Style = 0
+		}
+	} else {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
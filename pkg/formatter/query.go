@@ -0,0 +1,343 @@
+package formatter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Query narrows the output of list and get tools: Fields projects each item
+// on dotted paths (metadata.name, spec.runtime.envs[0].name) and SortBy orders
+// list items by one of those paths
+type Query struct {
+	Fields     []string
+	SortBy     string
+	Descending bool
+}
+
+// Empty reports whether the query leaves results untouched
+func (q Query) Empty() bool {
+	return len(q.Fields) == 0 && q.SortBy == ""
+}
+
+type queryKey struct{}
+
+// WithQuery returns a context carrying the query of the current call
+func WithQuery(ctx context.Context, query Query) context.Context {
+	return context.WithValue(ctx, queryKey{}, query)
+}
+
+// QueryFromContext returns the query of the current call
+func QueryFromContext(ctx context.Context) Query {
+	query, _ := ctx.Value(queryKey{}).(Query)
+	return query
+}
+
+// ParseFields splits a comma-separated list of field paths
+func ParseFields(fields string) ([]string, error) {
+	var paths []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimPrefix(strings.TrimSpace(field), "$.")
+		if field == "" {
+			continue
+		}
+		if _, err := parsePath(field); err != nil {
+			return nil, err
+		}
+		paths = append(paths, field)
+	}
+	return paths, nil
+}
+
+// Shape applies the query of the current call to SDK items. It returns them
+// sorted, in a new slice so that cached responses are left untouched, and when
+// fields are requested the projected items as JSON with done set, to be
+// returned as is.
+func Shape[T any](ctx context.Context, items []T) (sorted []T, data []byte, done bool, err error) {
+	sorted, err = SortItems(ctx, items)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	data, done, err = ProjectItems(ctx, sorted)
+	return sorted, data, done, err
+}
+
+// SortItems returns SDK items sorted by the sortBy field of the current call.
+// items is never modified; it is returned as is when no sort is requested.
+func SortItems[T any](ctx context.Context, items []T) ([]T, error) {
+	query := QueryFromContext(ctx)
+	if query.SortBy == "" {
+		return items, nil
+	}
+
+	values, err := toValues(items)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := sortOrder(values, query.SortBy, query.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]T, len(items))
+	for i, index := range order {
+		sorted[i] = items[index]
+	}
+	return sorted, nil
+}
+
+// ProjectItems returns SDK items projected on the fields of the current call
+// as JSON. done is false when no fields were requested.
+func ProjectItems[T any](ctx context.Context, items []T) (data []byte, done bool, err error) {
+	query := QueryFromContext(ctx)
+	if len(query.Fields) == 0 {
+		return nil, false, nil
+	}
+
+	values, err := toValues(items)
+	if err != nil {
+		return nil, false, err
+	}
+
+	projected := make([]interface{}, len(values))
+	for i, value := range values {
+		projected[i] = project(value, query.Fields)
+	}
+
+	data, err = json.MarshalIndent(projected, "", "  ")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to format response: %w", err)
+	}
+	return data, true, nil
+}
+
+// ApplyQuery applies a query to a JSON result: arrays are sorted and every
+// item projected, objects projected. Applying the same query twice gives the
+// same result, so handlers that already shaped their output are unaffected.
+// It returns false when the result is not JSON.
+func ApplyQuery(query Query, result string) (string, bool, error) {
+	trimmed := strings.TrimSpace(result)
+	if query.Empty() || (!strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[")) {
+		return "", false, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false, nil
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		if query.SortBy != "" {
+			order, err := sortOrder(v, query.SortBy, query.Descending)
+			if err != nil {
+				return "", false, err
+			}
+			sorted := make([]interface{}, len(v))
+			for i, index := range order {
+				sorted[i] = v[index]
+			}
+			v = sorted
+		}
+		if len(query.Fields) > 0 {
+			for i, item := range v {
+				v[i] = project(item, query.Fields)
+			}
+		}
+		value = v
+	default:
+		if len(query.Fields) > 0 {
+			value = project(value, query.Fields)
+		}
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to format response: %w", err)
+	}
+	return string(data), true, nil
+}
+
+// pathStep is one step of a field path: a key, optionally followed by an index
+type pathStep struct {
+	key   string
+	index int // -1 when the step has no index
+}
+
+func parsePath(field string) ([]pathStep, error) {
+	var steps []pathStep
+	for _, part := range strings.Split(field, ".") {
+		step := pathStep{key: part, index: -1}
+		if open := strings.Index(part, "["); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("invalid field path %q", field)
+			}
+			index, err := strconv.Atoi(part[open+1 : len(part)-1])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index in field path %q", field)
+			}
+			step = pathStep{key: part[:open], index: index}
+		}
+		if step.key == "" {
+			return nil, fmt.Errorf("invalid field path %q", field)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// lookup returns the value at a path, and false if any step is missing
+func lookup(value interface{}, steps []pathStep) (interface{}, bool) {
+	for _, step := range steps {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = object[step.key]
+		if !ok {
+			return nil, false
+		}
+		if step.index >= 0 {
+			items, ok := value.([]interface{})
+			if !ok || step.index >= len(items) {
+				return nil, false
+			}
+			value = items[step.index]
+		}
+	}
+	return value, true
+}
+
+// project keeps only the requested paths of a value, preserving its nesting.
+// Indexed steps become single-element arrays.
+func project(value interface{}, fields []string) interface{} {
+	result := map[string]interface{}{}
+	for _, field := range fields {
+		steps, err := parsePath(field)
+		if err != nil {
+			continue
+		}
+		found, ok := lookup(value, steps)
+		if !ok {
+			continue
+		}
+
+		target := result
+		for i, step := range steps {
+			last := i == len(steps)-1
+			if step.index >= 0 {
+				var child interface{} = found
+				if !last {
+					existing, _ := target[step.key].([]interface{})
+					var nested map[string]interface{}
+					if len(existing) == 1 {
+						nested, _ = existing[0].(map[string]interface{})
+					}
+					if nested == nil {
+						nested = map[string]interface{}{}
+					}
+					target[step.key] = []interface{}{nested}
+					target = nested
+					continue
+				}
+				target[step.key] = []interface{}{child}
+				break
+			}
+			if last {
+				target[step.key] = found
+				break
+			}
+			nested, ok := target[step.key].(map[string]interface{})
+			if !ok {
+				nested = map[string]interface{}{}
+				target[step.key] = nested
+			}
+			target = nested
+		}
+	}
+	return result
+}
+
+// sortOrder returns the indexes of values ordered by the value at sortBy.
+// Numbers compare numerically, everything else as text; items missing the
+// field always come last.
+func sortOrder(values []interface{}, sortBy string, descending bool) ([]int, error) {
+	steps, err := parsePath(strings.TrimPrefix(sortBy, "$."))
+	if err != nil {
+		return nil, err
+	}
+
+	type sortKey struct {
+		present bool
+		number  float64
+		numeric bool
+		text    string
+	}
+
+	keys := make([]sortKey, len(values))
+	for i, value := range values {
+		found, ok := lookup(value, steps)
+		if !ok || found == nil {
+			continue
+		}
+		key := sortKey{present: true, text: fmt.Sprint(found)}
+		switch n := found.(type) {
+		case json.Number:
+			if f, err := n.Float64(); err == nil {
+				key.number, key.numeric = f, true
+			}
+		case float64:
+			key.number, key.numeric = n, true
+		}
+		keys[i] = key
+	}
+
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		ka, kb := keys[order[a]], keys[order[b]]
+		if ka.present != kb.present {
+			return ka.present
+		}
+		if !ka.present {
+			return false
+		}
+
+		var less, greater bool
+		if ka.numeric && kb.numeric {
+			less, greater = ka.number < kb.number, ka.number > kb.number
+		} else {
+			less, greater = ka.text < kb.text, ka.text > kb.text
+		}
+		if descending {
+			return greater
+		}
+		return less
+	})
+	return order, nil
+}
+
+// toValues converts SDK items to decoded JSON values
+func toValues[T any](items []T) ([]interface{}, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode items: %w", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var values []interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to decode items: %w", err)
+	}
+	return values, nil
+}
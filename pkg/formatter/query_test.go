@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type item struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func names(items []item) string {
+	parts := make([]string, len(items))
+	for i, it := range items {
+		parts[i] = it.Name
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestSortItemsLeavesInputUntouched(t *testing.T) {
+	items := []item{{"b", 2}, {"c", 10}, {"a", 1}}
+	ctx := WithQuery(context.Background(), Query{SortBy: "count", Descending: true})
+
+	sorted, err := SortItems(ctx, items)
+	if err != nil {
+		t.Fatalf("SortItems() error = %v", err)
+	}
+	if got := names(sorted); got != "c,b,a" {
+		t.Errorf("sorted = %s, want c,b,a", got)
+	}
+	if got := names(items); got != "b,c,a" {
+		t.Errorf("input was reordered to %s", got)
+	}
+}
+
+func TestSortItemsWithoutSort(t *testing.T) {
+	items := []item{{"b", 2}, {"a", 1}}
+	sorted, err := SortItems(context.Background(), items)
+	if err != nil || names(sorted) != "b,a" {
+		t.Errorf("SortItems() = %s, %v, want the items as is", names(sorted), err)
+	}
+}
+
+func TestShape(t *testing.T) {
+	items := []item{{"b", 2}, {"a", 1}}
+
+	sorted, data, done, err := Shape(WithQuery(context.Background(), Query{SortBy: "name"}), items)
+	if err != nil || done || data != nil || names(sorted) != "a,b" {
+		t.Errorf("Shape() = %s, %s, %v, %v, want the sorted items only", names(sorted), data, done, err)
+	}
+
+	_, data, done, err = Shape(WithQuery(context.Background(), Query{SortBy: "name", Fields: []string{"name"}}), items)
+	if err != nil || !done {
+		t.Fatalf("Shape() = %v, %v, want projected items", done, err)
+	}
+	if got := strings.Join(strings.Fields(string(data)), ""); got != `[{"name":"a"},{"name":"b"}]` {
+		t.Errorf("projected = %s", got)
+	}
+}
+
+// Cached list responses are shared between concurrent calls; run with -race
+func TestSortItemsConcurrent(t *testing.T) {
+	shared := []item{{"b", 2}, {"c", 3}, {"a", 1}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(descending bool) {
+			defer wg.Done()
+			ctx := WithQuery(context.Background(), Query{SortBy: "count", Descending: descending})
+			if _, err := SortItems(ctx, shared); err != nil {
+				t.Errorf("SortItems() error = %v", err)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	if got := names(shared); got != "b,c,a" {
+		t.Errorf("shared items were reordered to %s", got)
+	}
+}
+
+func TestSortOrderMissingFieldsLast(t *testing.T) {
+	values := []interface{}{
+		map[string]interface{}{"name": "x"},
+		map[string]interface{}{"name": "y", "count": float64(2)},
+		map[string]interface{}{"name": "z", "count": float64(10)},
+	}
+	for _, descending := range []bool{false, true} {
+		order, err := sortOrder(values, "count", descending)
+		if err != nil {
+			t.Fatalf("sortOrder() error = %v", err)
+		}
+		if order[2] != 0 {
+			t.Errorf("descending=%v: order = %v, want the item without count last", descending, order)
+		}
+	}
+}
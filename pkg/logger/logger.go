@@ -6,6 +6,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
 )
 
 // Logger wraps the standard logger with file output for MCP stdio mode
@@ -20,6 +22,19 @@ var (
 	defaultLogger *Logger
 )
 
+// Dir returns the directory log files are written to: $LOG_DIR, or ~/.blaxel
+func Dir() (string, error) {
+	if logDir := os.Getenv("LOG_DIR"); logDir != "" {
+		return logDir, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".blaxel"), nil
+}
+
 // Init initializes the logger
 // If isStdio is true, logs will be written to a file
 // Otherwise, logs will be written to stderr
@@ -30,14 +45,9 @@ func Init(isStdio bool) error {
 
 	if isStdio {
 		// Create log directory
-		homeDir, err := os.UserHomeDir()
+		logDir, err := Dir()
 		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
-		}
-
-		logDir := filepath.Join(homeDir, ".blaxel")
-		if os.Getenv("LOG_DIR") != "" {
-			logDir = os.Getenv("LOG_DIR")
+			return err
 		}
 		if err := os.MkdirAll(logDir, 0755); err != nil {
 			return fmt.Errorf("failed to create log directory: %w", err)
@@ -86,44 +96,38 @@ func GetLogWriter() io.Writer {
 	return os.Stderr
 }
 
-// Printf logs a formatted message
-func Printf(format string, v ...interface{}) {
+// output writes a log line with secrets masked, attributing it to the caller
+// of the exported logging function
+func output(s string) {
+	s = redact.Text(s)
 	if defaultLogger == nil {
 		// Fallback to stderr if not initialized
-		log.Printf(format, v...)
+		log.Print(s)
 		return
 	}
-	defaultLogger.logger.Printf(format, v...)
+	defaultLogger.logger.Output(3, s)
+}
+
+// Printf logs a formatted message
+func Printf(format string, v ...interface{}) {
+	output(fmt.Sprintf(format, v...))
 }
 
 // Println logs a message with a newline
 func Println(v ...interface{}) {
-	if defaultLogger == nil {
-		// Fallback to stderr if not initialized
-		log.Println(v...)
-		return
-	}
-	defaultLogger.logger.Println(v...)
+	output(fmt.Sprintln(v...))
 }
 
 // Fatalf logs a formatted message and exits
 func Fatalf(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		// Fallback to stderr if not initialized
-		log.Fatalf(format, v...)
-		return
-	}
-	defaultLogger.logger.Fatalf(format, v...)
+	output(fmt.Sprintf(format, v...))
+	os.Exit(1)
 }
 
 // Fatal logs a message and exits
 func Fatal(v ...interface{}) {
-	if defaultLogger == nil {
-		// Fallback to stderr if not initialized
-		log.Fatal(v...)
-		return
-	}
-	defaultLogger.logger.Fatal(v...)
+	output(fmt.Sprint(v...))
+	os.Exit(1)
 }
 
 // Debugf logs a debug message (only if debug mode is enabled)
@@ -132,25 +136,17 @@ func Debugf(format string, v ...interface{}) {
 		return
 	}
 	// Could check a debug flag here if needed
-	defaultLogger.logger.Printf("[DEBUG] "+format, v...)
+	output(fmt.Sprintf("[DEBUG] "+format, v...))
 }
 
 // Warnf logs a warning message
 func Warnf(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		log.Printf("[WARNING] "+format, v...)
-		return
-	}
-	defaultLogger.logger.Printf("[WARNING] "+format, v...)
+	output(fmt.Sprintf("[WARNING] "+format, v...))
 }
 
 // Errorf logs an error message
 func Errorf(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		log.Printf("[ERROR] "+format, v...)
-		return
-	}
-	defaultLogger.logger.Printf("[ERROR] "+format, v...)
+	output(fmt.Sprintf("[ERROR] "+format, v...))
 }
 
 // IsStdio returns whether the logger is in stdio mode
@@ -0,0 +1,223 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// LimitError is returned when a call is over a limit. RetryAfter is zero when
+// the call has to wait for running calls rather than for a refill.
+type LimitError struct {
+	Tool       string
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: %s; retry after %s", e.Tool, e.Reason, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: %s; retry once one of them completes", e.Tool, e.Reason)
+}
+
+// bucket is a token bucket refilled continuously
+type bucket struct {
+	tokens    float64
+	capacity  float64
+	perSecond float64
+	last      time.Time
+}
+
+func newBucket(perMinute float64, burst int, now time.Time) *bucket {
+	capacity := float64(burst)
+	if burst <= 0 {
+		capacity = math.Max(1, perMinute)
+	}
+	return &bucket{
+		tokens:    capacity,
+		capacity:  capacity,
+		perSecond: perMinute / 60,
+		last:      now,
+	}
+}
+
+// wait refills the bucket and returns how long until a token is available
+func (b *bucket) wait(now time.Time) time.Duration {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.perSecond)
+	b.last = now
+
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.perSecond * float64(time.Second))
+}
+
+// Limiter enforces the configured rate limits and concurrency caps. Rate
+// limits are tracked per client session; concurrency caps are server-wide.
+type Limiter struct {
+	mu      sync.Mutex
+	session *config.Rate
+	rules   []config.ToolLimit
+	buckets map[string]*bucket
+	running map[int]int
+	now     func() time.Time
+}
+
+// New validates the limits and creates a limiter
+func New(limits config.Limits) (*Limiter, error) {
+	if limits.Session != nil && limits.Session.PerMinute <= 0 {
+		return nil, fmt.Errorf("session limit needs a positive perMinute")
+	}
+
+	for _, rule := range limits.Tools {
+		if _, err := path.Match(rule.Tool, ""); err != nil || rule.Tool == "" {
+			return nil, fmt.Errorf("invalid tool pattern %q in limits", rule.Tool)
+		}
+		if rule.PerMinute < 0 || rule.Burst < 0 || rule.MaxConcurrent < 0 {
+			return nil, fmt.Errorf("limits for %q cannot be negative", rule.Tool)
+		}
+		if rule.PerMinute == 0 && rule.MaxConcurrent == 0 {
+			return nil, fmt.Errorf("limits for %q need perMinute or maxConcurrent", rule.Tool)
+		}
+	}
+
+	return &Limiter{
+		session: limits.Session,
+		rules:   limits.Tools,
+		buckets: make(map[string]*bucket),
+		running: make(map[int]int),
+		now:     time.Now,
+	}, nil
+}
+
+// Acquire takes a token from every bucket that applies to the call and a slot
+// from every concurrency cap. Nothing is taken when any limit is hit. The
+// returned release function must be called once the call completes.
+func (l *Limiter) Acquire(sessionID, tool string) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	var matched []int
+	for i, rule := range l.rules {
+		if ok, _ := path.Match(rule.Tool, tool); ok {
+			matched = append(matched, i)
+		}
+	}
+
+	// Check every limit before taking anything
+	var taken []*bucket
+	if l.session != nil {
+		b := l.bucket(sessionID+"/session", l.session.PerMinute, l.session.Burst, now)
+		if wait := b.wait(now); wait > 0 {
+			return nil, &LimitError{
+				Tool:       tool,
+				Reason:     fmt.Sprintf("session rate limit exceeded (%g calls per minute)", l.session.PerMinute),
+				RetryAfter: roundUp(wait),
+			}
+		}
+		taken = append(taken, b)
+	}
+
+	for _, i := range matched {
+		rule := l.rules[i]
+		if rule.PerMinute > 0 {
+			b := l.bucket(fmt.Sprintf("%s/rule/%d", sessionID, i), rule.PerMinute, rule.Burst, now)
+			if wait := b.wait(now); wait > 0 {
+				return nil, &LimitError{
+					Tool:       tool,
+					Reason:     fmt.Sprintf("rate limit exceeded (%g calls per minute for '%s')", rule.PerMinute, rule.Tool),
+					RetryAfter: roundUp(wait),
+				}
+			}
+			taken = append(taken, b)
+		}
+		if rule.MaxConcurrent > 0 && l.running[i] >= rule.MaxConcurrent {
+			return nil, &LimitError{
+				Tool:   tool,
+				Reason: fmt.Sprintf("too many calls in progress (%d, max %d for '%s')", l.running[i], rule.MaxConcurrent, rule.Tool),
+			}
+		}
+	}
+
+	for _, b := range taken {
+		b.tokens--
+	}
+
+	var slots []int
+	for _, i := range matched {
+		if l.rules[i].MaxConcurrent > 0 {
+			l.running[i]++
+			slots = append(slots, i)
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			for _, i := range slots {
+				l.running[i]--
+			}
+		})
+	}, nil
+}
+
+// Forget drops the buckets of a session that has ended
+func (l *Limiter) Forget(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key := range l.buckets {
+		if strings.HasPrefix(key, sessionID+"/") {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware rejects calls over a limit with an error telling the model when
+// to retry
+func (l *Limiter) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sessionID := ""
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			sessionID = session.SessionID()
+		}
+
+		release, err := l.Acquire(sessionID, request.Params.Name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer release()
+
+		return next(ctx, request)
+	}
+}
+
+// bucket returns the bucket for key, creating it full. The caller must hold l.mu.
+func (l *Limiter) bucket(key string, perMinute float64, burst int, now time.Time) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = newBucket(perMinute, burst, now)
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// roundUp rounds a wait up to the next tenth of a second, so that retrying
+// after it always finds a token
+func roundUp(wait time.Duration) time.Duration {
+	const step = 100 * time.Millisecond
+	return (wait + step - 1) / step * step
+}
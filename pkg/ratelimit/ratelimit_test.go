@@ -0,0 +1,266 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// clock is a fake time source advanced by the tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newLimiter(t *testing.T, limits config.Limits) (*Limiter, *clock) {
+	t.Helper()
+	l, err := New(limits)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	c := &clock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l.now = func() time.Time { return c.now }
+	return l, c
+}
+
+// acquire calls Acquire and returns the limit error, if any
+func acquire(t *testing.T, l *Limiter, session, tool string) *LimitError {
+	t.Helper()
+	release, err := l.Acquire(session, tool)
+	if err == nil {
+		release()
+		return nil
+	}
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("Acquire() error = %v, want a LimitError", err)
+	}
+	return limitErr
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits config.Limits
+		err    string
+	}{
+		{"empty", config.Limits{}, ""},
+		{"valid", config.Limits{Session: &config.Rate{PerMinute: 60}, Tools: []config.ToolLimit{{Tool: "run_*", PerMinute: 10, MaxConcurrent: 2}}}, ""},
+		{"session without rate", config.Limits{Session: &config.Rate{Burst: 5}}, "positive perMinute"},
+		{"empty pattern", config.Limits{Tools: []config.ToolLimit{{PerMinute: 1}}}, "invalid tool pattern"},
+		{"invalid pattern", config.Limits{Tools: []config.ToolLimit{{Tool: "run_[", PerMinute: 1}}}, "invalid tool pattern"},
+		{"negative", config.Limits{Tools: []config.ToolLimit{{Tool: "run_*", PerMinute: -1}}}, "cannot be negative"},
+		{"no limit", config.Limits{Tools: []config.ToolLimit{{Tool: "run_*", Burst: 3}}}, "need perMinute or maxConcurrent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.limits)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("New() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("New() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRefill(t *testing.T) {
+	l, c := newLimiter(t, config.Limits{Tools: []config.ToolLimit{{Tool: "run_model", PerMinute: 60, Burst: 2}}})
+
+	steps := []struct {
+		advance    time.Duration
+		retryAfter time.Duration // zero when the call is allowed
+	}{
+		{0, 0},
+		{0, 0},
+		{0, time.Second}, // the burst is spent, one token per second
+		{400 * time.Millisecond, 600 * time.Millisecond},
+		{550 * time.Millisecond, 100 * time.Millisecond}, // 50ms rounded up
+		{50 * time.Millisecond, 0},
+		{time.Hour, 0}, // refilled up to the burst only
+		{0, 0},
+		{0, time.Second},
+	}
+
+	for i, step := range steps {
+		c.advance(step.advance)
+		err := acquire(t, l, "s", "run_model")
+		switch {
+		case step.retryAfter == 0 && err != nil:
+			t.Errorf("step %d: Acquire() error = %v", i, err)
+		case step.retryAfter != 0 && (err == nil || err.RetryAfter != step.retryAfter):
+			t.Errorf("step %d: Acquire() error = %v, want a retry after %s", i, err, step.retryAfter)
+		}
+	}
+
+	if err := acquire(t, l, "s", "list_agents"); err != nil {
+		t.Errorf("a tool no rule matches should not be limited: %v", err)
+	}
+}
+
+func TestBurst(t *testing.T) {
+	tests := []struct {
+		name      string
+		perMinute float64
+		burst     int
+		allowed   int
+	}{
+		{"explicit burst", 60, 3, 3},
+		{"burst defaults to the rate", 5, 0, 5},
+		{"slow rate allows one call", 0.5, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newLimiter(t, config.Limits{Session: &config.Rate{PerMinute: tt.perMinute, Burst: tt.burst}})
+			allowed := 0
+			for acquire(t, l, "s", "get_agent") == nil && allowed < 100 {
+				allowed++
+			}
+			if allowed != tt.allowed {
+				t.Errorf("allowed %d calls, want %d", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestSessions(t *testing.T) {
+	l, _ := newLimiter(t, config.Limits{Session: &config.Rate{PerMinute: 1}})
+
+	if err := acquire(t, l, "a", "list_agents"); err != nil {
+		t.Fatalf("first call of a: %v", err)
+	}
+	err := acquire(t, l, "a", "get_agent")
+	if err == nil || !strings.Contains(err.Error(), "session rate limit exceeded (1 calls per minute)") {
+		t.Errorf("second call of a: %v", err)
+	}
+	if err := acquire(t, l, "b", "list_agents"); err != nil {
+		t.Errorf("sessions should have their own buckets: %v", err)
+	}
+
+	l.Forget("a")
+	if err := acquire(t, l, "a", "list_agents"); err != nil {
+		t.Errorf("a forgotten session should start with a full bucket: %v", err)
+	}
+}
+
+func TestNothingTakenWhenLimited(t *testing.T) {
+	l, _ := newLimiter(t, config.Limits{
+		Session: &config.Rate{PerMinute: 10},
+		Tools: []config.ToolLimit{
+			{Tool: "run_*", PerMinute: 10},
+			{Tool: "run_model", PerMinute: 1},
+		},
+	})
+
+	if err := acquire(t, l, "s", "run_model"); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	err := acquire(t, l, "s", "run_model")
+	if err == nil || !strings.Contains(err.Error(), "'run_model'") {
+		t.Fatalf("second call: %v", err)
+	}
+
+	for key, want := range map[string]float64{"s/session": 9, "s/rule/0": 9, "s/rule/1": 0} {
+		if got := l.buckets[key].tokens; got != want {
+			t.Errorf("%s has %g tokens, want %g", key, got, want)
+		}
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	l, _ := newLimiter(t, config.Limits{Tools: []config.ToolLimit{{Tool: "run_*", MaxConcurrent: 2}}})
+
+	first, err := l.Acquire("a", "run_agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := l.Acquire("b", "run_job")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cap is server-wide, across sessions and matching tools
+	limitErr := acquire(t, l, "c", "run_model")
+	if limitErr == nil || limitErr.RetryAfter != 0 {
+		t.Fatalf("third call: %v", limitErr)
+	}
+	if want := "run_model: too many calls in progress (2, max 2 for 'run_*'); retry once one of them completes"; limitErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", limitErr.Error(), want)
+	}
+
+	// Releasing twice frees one slot only
+	first()
+	first()
+	if l.running[0] != 1 {
+		t.Errorf("running = %d after one release", l.running[0])
+	}
+	if err := acquire(t, l, "c", "run_model"); err != nil {
+		t.Errorf("a released slot should be available: %v", err)
+	}
+	second()
+	if l.running[0] != 0 {
+		t.Errorf("running = %d after every release", l.running[0])
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	err := &LimitError{Tool: "run_model", Reason: "rate limit exceeded", RetryAfter: 1500 * time.Millisecond}
+	if want := "run_model: rate limit exceeded; retry after 1.5s"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	tests := []struct {
+		wait, want time.Duration
+	}{
+		{0, 0},
+		{time.Nanosecond, 100 * time.Millisecond},
+		{100 * time.Millisecond, 100 * time.Millisecond},
+		{101 * time.Millisecond, 200 * time.Millisecond},
+		{59*time.Second + 950*time.Millisecond, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := roundUp(tt.wait); got != tt.want {
+			t.Errorf("roundUp(%s) = %s, want %s", tt.wait, got, tt.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	l, _ := newLimiter(t, config.Limits{Tools: []config.ToolLimit{{Tool: "run_model", PerMinute: 1}}})
+
+	calls := 0
+	handler := l.Middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "run_model"
+
+	for i, limited := range []bool{false, true} {
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+		if result.IsError != limited {
+			t.Errorf("call %d: isError = %v, want %v", i, result.IsError, limited)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("the handler ran %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,228 @@
+package redact
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretFields are field names (lowercase, without '_' and '-') whose string
+// values are always masked
+var secretFields = map[string]bool{
+	"apikey":        true,
+	"apitoken":      true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"sessiontoken":  true,
+	"bearertoken":   true,
+	"token":         true,
+	"clientsecret":  true,
+	"secret":        true,
+	"secretkey":     true,
+	"privatekey":    true,
+	"password":      true,
+	"authorization": true,
+	"devicecode":    true,
+}
+
+// secretContainers are field names whose every value is a secret, like the
+// Spec.Secret map of an integration connection
+var secretContainers = map[string]bool{
+	"secret":  true,
+	"secrets": true,
+}
+
+// envSecretMarkers flag environment variable names that hold secrets
+var envSecretMarkers = []string{"KEY", "TOKEN", "SECRET", "PASSWORD"}
+
+var (
+	// key=value or "key": "value" pairs whose key names a secret
+	assignmentPattern = regexp.MustCompile(`(?i)("?(?:api[_-]?key|api[_-]?token|access[_-]?token|refresh[_-]?token|client[_-]?secret|secret|password|token)"?\s*[:=]\s*"?)([^"\s,}&\[\]][^"\s,}&\]]*)`)
+	// Upper-case environment variables such as OPENAI_API_KEY=sk-...
+	envPattern = regexp.MustCompile(`\b([A-Z0-9_]*(?:KEY|TOKEN|SECRET|PASSWORD)[A-Z0-9_]*)=([^\s,}&\[\]][^\s,}&\]]*)`)
+	// Bearer tokens in headers
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)([A-Za-z0-9\-._~+/]+=*)`)
+	// Well-known credential shapes: OpenAI/Anthropic keys, GitHub tokens, JWTs
+	credentialPattern = regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_\-]{16,}|gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}|eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+)`)
+	// Values already masked by Mask, which must not be masked twice
+	maskedPattern = regexp.MustCompile(`^\[redacted sha256:[0-9a-f]{8}\]$`)
+)
+
+// Fingerprint returns a short, stable fingerprint of a secret so that two
+// values can be compared without revealing either
+func Fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// Mask replaces a secret value with its fingerprint
+func Mask(value string) string {
+	if value == "" || maskedPattern.MatchString(value) {
+		return value
+	}
+	return "[redacted sha256:" + Fingerprint(value) + "]"
+}
+
+// MaskMap returns the keys of a secret map with masked values
+func MaskMap(secrets map[string]string) map[string]string {
+	masked := make(map[string]string, len(secrets))
+	for key, value := range secrets {
+		masked[key] = Mask(value)
+	}
+	return masked
+}
+
+// Keys returns the sorted keys of a secret map
+func Keys(secrets map[string]string) []string {
+	keys := make([]string, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsSecretField reports whether a field name holds a secret value
+func IsSecretField(name string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	return secretFields[normalized] || IsSecretEnv(name)
+}
+
+// IsSecretEnv reports whether an environment variable name looks like it holds
+// a secret (*KEY*, *TOKEN*, *SECRET*, *PASSWORD*). Only upper-case names are
+// considered, so JSON fields like maxTokens are left alone.
+func IsSecretEnv(name string) bool {
+	if name == "" || name != strings.ToUpper(name) {
+		return false
+	}
+	for _, marker := range envSecretMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCredential reports whether a value has the shape of a well-known
+// credential, such as an OpenAI key, a GitHub token or a JWT
+func IsCredential(value string) bool {
+	return credentialPattern.MatchString(value)
+}
+
+// Text masks secrets in free-form text such as log lines and formatted output
+func Text(s string) string {
+	s = assignmentPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := assignmentPattern.FindStringSubmatch(match)
+		return parts[1] + Mask(parts[2])
+	})
+	s = envPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := envPattern.FindStringSubmatch(match)
+		return parts[1] + "=" + Mask(parts[2])
+	})
+	s = bearerPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := bearerPattern.FindStringSubmatch(match)
+		return parts[1] + Mask(parts[2])
+	})
+	return credentialPattern.ReplaceAllStringFunc(s, Mask)
+}
+
+// Output masks secrets in a tool result. JSON documents are walked field by
+// field; anything else is treated as text.
+func Output(s string) string {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		if redacted, ok := JSON([]byte(trimmed)); ok {
+			return string(redacted)
+		}
+	}
+	return Text(s)
+}
+
+// JSON masks secret fields in a JSON document. It returns false if data is
+// not valid JSON. The document is only re-encoded when something was masked.
+func JSON(data []byte) ([]byte, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+
+	redacted, changed := walk(value, false)
+	if !changed {
+		return data, true
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(redacted); err != nil {
+		return nil, false
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), true
+}
+
+// walk masks secrets in a decoded JSON value. Inside a secret container every
+// string is masked.
+func walk(value interface{}, secret bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		changed := false
+
+		// {"name": "OPENAI_API_KEY", "value": "sk-..."} environment entries
+		if name, ok := v["name"].(string); ok && IsSecretEnv(name) {
+			if raw, ok := v["value"].(string); ok && raw != Mask(raw) {
+				v["value"] = Mask(raw)
+				changed = true
+			}
+		}
+
+		for key, item := range v {
+			normalized := strings.ToLower(key)
+			if str, ok := item.(string); ok && (secret || IsSecretField(key)) {
+				if masked := Mask(str); masked != str {
+					v[key] = masked
+					changed = true
+				}
+				continue
+			}
+
+			redacted, itemChanged := walk(item, secret || secretContainers[normalized])
+			if itemChanged {
+				v[key] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+
+	case []interface{}:
+		changed := false
+		for i, item := range v {
+			redacted, itemChanged := walk(item, secret)
+			if itemChanged {
+				v[i] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+
+	case string:
+		if secret {
+			masked := Mask(v)
+			return masked, masked != v
+		}
+		// Free text inside a field, such as an error message or a log line
+		masked := Text(v)
+		return masked, masked != v
+
+	default:
+		return value, false
+	}
+}
@@ -0,0 +1,78 @@
+package selector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// Filter selects list items by label selector and status
+type Filter struct {
+	Labels   Selector
+	Statuses []string // any of them matches, case-insensitively
+}
+
+// Empty reports whether the filter keeps every item
+func (f Filter) Empty() bool {
+	return f.Labels.Empty() && len(f.Statuses) == 0
+}
+
+// Matches reports whether an item with the given metadata and status passes
+// the filter. Items without a status never match a status filter.
+func (f Filter) Matches(metadata *sdk.Metadata, status *string) bool {
+	if len(f.Statuses) > 0 {
+		if status == nil {
+			return false
+		}
+		matched := false
+		for _, s := range f.Statuses {
+			if strings.EqualFold(*status, s) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if !f.Labels.Empty() {
+		var labels map[string]string
+		if metadata != nil && metadata.Labels != nil {
+			labels = *metadata.Labels
+		}
+		return f.Labels.Matches(labels)
+	}
+	return true
+}
+
+type filterKey struct{}
+
+// WithFilter returns a context carrying the filter of the current call
+func WithFilter(ctx context.Context, filter Filter) context.Context {
+	return context.WithValue(ctx, filterKey{}, filter)
+}
+
+// FromContext returns the filter of the current call
+func FromContext(ctx context.Context) Filter {
+	filter, _ := ctx.Value(filterKey{}).(Filter)
+	return filter
+}
+
+// Apply keeps the items passing the filter of the current call. fields returns
+// the metadata and status of an item (nil when the resource has no status).
+func Apply[T any](ctx context.Context, items []T, fields func(T) (*sdk.Metadata, *string)) []T {
+	filter := FromContext(ctx)
+	if filter.Empty() {
+		return items
+	}
+
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if filter.Matches(fields(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,210 @@
+package selector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// operator is the comparison of one selector requirement
+type operator string
+
+const (
+	opEquals    operator = "="
+	opNotEquals operator = "!="
+	opIn        operator = "in"
+	opNotIn     operator = "notin"
+	opExists    operator = "exists"
+	opNotExists operator = "!"
+)
+
+// requirement is one comma-separated term of a selector
+type requirement struct {
+	key    string
+	op     operator
+	values []string
+}
+
+// Selector is a Kubernetes-style label selector such as
+// "env=prod,team in (ml,search),!deprecated". Every requirement must match.
+type Selector struct {
+	requirements []requirement
+	source       string
+}
+
+// Parse parses a label selector. The empty selector matches everything.
+//
+// Supported requirements are key=value (or key==value), key!=value,
+// key in (a,b), key notin (a,b), key (the label is set) and !key (it is not).
+func Parse(s string) (Selector, error) {
+	selector := Selector{source: strings.TrimSpace(s)}
+
+	terms, err := split(selector.source)
+	if err != nil {
+		return Selector{}, err
+	}
+
+	for _, term := range terms {
+		req, err := parseRequirement(term)
+		if err != nil {
+			return Selector{}, fmt.Errorf("invalid label selector %q: %w", selector.source, err)
+		}
+		selector.requirements = append(selector.requirements, req)
+	}
+	return selector, nil
+}
+
+// Empty reports whether the selector matches everything
+func (s Selector) Empty() bool {
+	return len(s.requirements) == 0
+}
+
+// String returns the selector as it was written
+func (s Selector) String() string {
+	return s.source
+}
+
+// Matches reports whether labels satisfy every requirement of the selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s.requirements {
+		value, ok := labels[req.key]
+		switch req.op {
+		case opEquals:
+			if !ok || value != req.values[0] {
+				return false
+			}
+		case opNotEquals:
+			if ok && value == req.values[0] {
+				return false
+			}
+		case opIn:
+			if !ok || !contains(req.values, value) {
+				return false
+			}
+		case opNotIn:
+			if ok && contains(req.values, value) {
+				return false
+			}
+		case opExists:
+			if !ok {
+				return false
+			}
+		case opNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// split cuts a selector at the commas outside of parentheses
+func split(s string) ([]string, error) {
+	var terms []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("invalid label selector %q: nested parentheses", s)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid label selector %q: unbalanced parentheses", s)
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid label selector %q: unbalanced parentheses", s)
+	}
+	terms = append(terms, s[start:])
+
+	var trimmed []string
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("invalid label selector %q: empty requirement", s)
+		}
+		trimmed = append(trimmed, term)
+	}
+	return trimmed, nil
+}
+
+func parseRequirement(term string) (requirement, error) {
+	if strings.HasPrefix(term, "!") {
+		key := strings.TrimSpace(term[1:])
+		if err := validKey(key); err != nil {
+			return requirement{}, err
+		}
+		return requirement{key: key, op: opNotExists}, nil
+	}
+
+	// Set-based requirements: key in (a,b) and key notin (a,b)
+	if open := strings.Index(term, "("); open >= 0 {
+		if !strings.HasSuffix(term, ")") {
+			return requirement{}, fmt.Errorf("%q: expected a closing parenthesis", term)
+		}
+		fields := strings.Fields(term[:open])
+		if len(fields) != 2 || (fields[1] != string(opIn) && fields[1] != string(opNotIn)) {
+			return requirement{}, fmt.Errorf("%q: expected 'key in (values)' or 'key notin (values)'", term)
+		}
+		if err := validKey(fields[0]); err != nil {
+			return requirement{}, err
+		}
+		var values []string
+		for _, value := range strings.Split(term[open+1:len(term)-1], ",") {
+			values = append(values, strings.TrimSpace(value))
+		}
+		sort.Strings(values)
+		return requirement{key: fields[0], op: operator(fields[1]), values: values}, nil
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		if index := strings.Index(term, op); index >= 0 {
+			key := strings.TrimSpace(term[:index])
+			value := strings.TrimSpace(term[index+len(op):])
+			if err := validKey(key); err != nil {
+				return requirement{}, err
+			}
+			if strings.ContainsAny(value, "=!") {
+				return requirement{}, fmt.Errorf("%q: invalid value", term)
+			}
+			if op == "!=" {
+				return requirement{key: key, op: opNotEquals, values: []string{value}}, nil
+			}
+			return requirement{key: key, op: opEquals, values: []string{value}}, nil
+		}
+	}
+
+	if err := validKey(term); err != nil {
+		return requirement{}, err
+	}
+	return requirement{key: term, op: opExists}, nil
+}
+
+// validKey rejects keys with spaces or operator characters, which usually
+// means a misspelled operator
+func validKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("missing label key")
+	}
+	if strings.ContainsAny(key, " \t=!(),") {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	index := sort.SearchStrings(values, value)
+	return index < len(values) && values[index] == value
+}
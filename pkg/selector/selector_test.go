@@ -0,0 +1,153 @@
+package selector
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "search", "tier": "1", "quoted": `"x"`}
+
+	tests := []struct {
+		selector string
+		matches  bool
+	}{
+		{"", true},
+		{"  ", true},
+		{"env=prod", true},
+		{"env==prod", true},
+		{" env = prod ", true},
+		{"env=dev", false},
+		{"env!=dev", true},
+		{"env!=prod", false},
+		{"missing!=x", true},
+		{"team in (ml,search)", true},
+		{"team in ( ml , search )", true},
+		{"team in (ml)", false},
+		{"missing in (ml)", false},
+		{"team notin (ml,vision)", true},
+		{"team notin (search)", false},
+		{"missing notin (search)", true},
+		{"tier", true},
+		{"missing", false},
+		{"!missing", true},
+		{"!env", false},
+		{"! env", false},
+		{"env=prod,team in (ml,search),!deprecated", true},
+		{"env=prod,team in (ml,search),tier", true},
+		{"env=prod,team notin (search)", false},
+		{"env=", false},
+		{"empty=", false},
+		// Quotes are not special: they are part of the value
+		{`quoted="x"`, true},
+		{`env="prod"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := Parse(tt.selector)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := selector.Matches(labels); got != tt.matches {
+				t.Errorf("Matches() = %v, want %v", got, tt.matches)
+			}
+		})
+	}
+
+	empty, _ := Parse("x=")
+	if !empty.Matches(map[string]string{"x": ""}) {
+		t.Error("x= should match an empty value")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		selector string
+		err      string
+	}{
+		{"env=prod,", "empty requirement"},
+		{",env=prod", "empty requirement"},
+		{"team in (a,(b))", "nested parentheses"},
+		{"team in (a", "unbalanced parentheses"},
+		{"team in a)", "unbalanced parentheses"},
+		{"team in (a) x", "expected a closing parenthesis"},
+		{"team within (a)", "expected 'key in (values)' or 'key notin (values)'"},
+		{"in (a)", "expected 'key in (values)'"},
+		{"=prod", "missing label key"},
+		{"!", "missing label key"},
+		{"my env=prod", "invalid label key"},
+		{"env=prod=dev", "invalid value"},
+		{"env=!prod", "invalid value"},
+		{"a b", "invalid label key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			_, err := Parse(tt.selector)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSelectorString(t *testing.T) {
+	selector, _ := Parse("  env=prod ")
+	if selector.String() != "env=prod" || selector.Empty() {
+		t.Errorf("String() = %q, Empty() = %v", selector.String(), selector.Empty())
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	type item struct {
+		name   string
+		labels map[string]string
+		status *string
+	}
+	deployed, failed := "DEPLOYED", "FAILED"
+	items := []item{
+		{"a", map[string]string{"env": "prod"}, &deployed},
+		{"b", map[string]string{"env": "dev"}, &deployed},
+		{"c", nil, &failed},
+		{"d", map[string]string{"env": "prod"}, nil},
+	}
+	fields := func(i item) (*sdk.Metadata, *string) {
+		if i.labels == nil {
+			return nil, i.status
+		}
+		labels := sdk.MetadataLabels(i.labels)
+		return &sdk.Metadata{Labels: &labels}, i.status
+	}
+
+	prod, _ := Parse("env=prod")
+	notProd, _ := Parse("env!=prod")
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{"no filter", Filter{}, "abcd"},
+		{"labels", Filter{Labels: prod}, "ad"},
+		{"negation matches unlabelled items", Filter{Labels: notProd}, "bc"},
+		{"status, case-insensitive", Filter{Statuses: []string{"deployed"}}, "ab"},
+		{"statuses", Filter{Statuses: []string{"failed", "DEPLOYED"}}, "abc"},
+		{"labels and status", Filter{Labels: prod, Statuses: []string{"DEPLOYED"}}, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept := Apply(WithFilter(context.Background(), tt.filter), items, fields)
+			got := ""
+			for _, i := range kept {
+				got += i.name
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,131 @@ package agents
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // AgentHandler defines the interface for agent operations
 type AgentHandler interface {
 	ListAgents(ctx context.Context, filter string) ([]byte, error)
 	GetAgent(ctx context.Context, name string) ([]byte, error)
+	CreateAgent(ctx context.Context, name string, options AgentOptions, waitForCompletion string) ([]byte, error)
+	UpdateAgent(ctx context.Context, name string, options AgentOptions, waitForCompletion string) ([]byte, error)
 	DeleteAgent(ctx context.Context, name string) ([]byte, error)
+	ListAgentRevisions(ctx context.Context, name string) ([]byte, error)
+	RollbackAgent(ctx context.Context, name, revision, waitForCompletion string) ([]byte, error)
+}
+
+// AgentOptions are the settings of create_agent and update_agent, the same
+// fields convertToAgentModel reads. Unset fields are left out on create and
+// kept as they are on update.
+type AgentOptions struct {
+	Image                  *string
+	Memory                 *int
+	Generation             *string
+	MaxConcurrentTasks     *int
+	Envs                   map[string]string
+	IntegrationConnections []string
+	Labels                 map[string]string
+}
+
+// agentArgs are the arguments shared by create_agent and update_agent
+type agentArgs struct {
+	Name                   string                 `json:"name"`
+	Image                  *string                `json:"image,omitempty"`
+	Memory                 *int                   `json:"memory,omitempty"`
+	Generation             *string                `json:"generation,omitempty"`
+	MaxConcurrentTasks     *int                   `json:"maxConcurrentTasks,omitempty"`
+	Envs                   map[string]interface{} `json:"envs,omitempty"`
+	IntegrationConnections []string               `json:"integrationConnections,omitempty"`
+	Labels                 map[string]interface{} `json:"labels,omitempty"`
+	WaitForCompletion      string                 `json:"waitForCompletion,omitempty"`
+}
+
+// options converts the arguments to agent options
+func (a agentArgs) options() (AgentOptions, error) {
+	options := AgentOptions{
+		Image:                  a.Image,
+		Memory:                 a.Memory,
+		Generation:             a.Generation,
+		MaxConcurrentTasks:     a.MaxConcurrentTasks,
+		IntegrationConnections: a.IntegrationConnections,
+	}
+
+	if a.Memory != nil && *a.Memory <= 0 {
+		return options, fmt.Errorf("memory must be a positive number of MB")
+	}
+	if a.Generation != nil && *a.Generation != "mk2" && *a.Generation != "mk3" {
+		return options, fmt.Errorf("generation must be 'mk2' or 'mk3'")
+	}
+	if a.MaxConcurrentTasks != nil && *a.MaxConcurrentTasks <= 0 {
+		return options, fmt.Errorf("maxConcurrentTasks must be positive")
+	}
+
+	var err error
+	if options.Envs, err = stringMap("envs", a.Envs); err != nil {
+		return options, err
+	}
+	if options.Labels, err = stringMap("labels", a.Labels); err != nil {
+		return options, err
+	}
+	return options, nil
+}
+
+// stringMap converts an object argument to a string map, keeping nil as nil
+// so that an omitted argument can be told apart from an empty one
+func stringMap(arg string, values map[string]interface{}) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			result[key] = v
+		case float64, bool:
+			result[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("%s: value of '%s' must be a string", arg, key)
+		}
+	}
+	return result, nil
+}
+
+// agentSettings are the tool options describing an agent, shared by
+// create_agent and update_agent
+func agentSettings() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("image",
+			mcp.Description("Container image of the agent (e.g. 'my-workspace/agent/my-agent:latest')"),
+		),
+		mcp.WithNumber("memory",
+			mcp.Description("Memory in MB (e.g. 2048)"),
+		),
+		mcp.WithString("generation",
+			mcp.Description("Runtime generation"),
+			mcp.Enum("mk2", "mk3"),
+		),
+		mcp.WithNumber("maxConcurrentTasks",
+			mcp.Description("Maximum number of requests handled at once by one instance"),
+		),
+		mcp.WithObject("envs",
+			mcp.Description("Environment variables as name/value pairs (e.g. {\"LOG_LEVEL\": \"debug\"})"),
+		),
+		mcp.WithArray("integrationConnections",
+			mcp.Description("Names of the integration connections the agent uses"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("Labels as key/value pairs (e.g. {\"env\": \"prod\"})"),
+		),
+		mcp.WithString("waitForCompletion",
+			mcp.Description("Whether to wait for the agent to reach a final status (true/false, default: true)"),
+		),
+	}
 }
 
 // AgentHandlerWithReadOnly extends AgentHandler with readonly capability
@@ -21,7 +136,7 @@ type AgentHandlerWithReadOnly interface {
 }
 
 // RegisterAgentTools registers agent tools with the given handler
-func RegisterAgentTools(s *server.MCPServer, handler AgentHandler) {
+func RegisterAgentTools(s tools.ToolRegistrar, handler AgentHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(AgentHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
@@ -31,9 +146,13 @@ func RegisterAgentTools(s *server.MCPServer, handler AgentHandler) {
 		mcp.WithString("filter",
 			mcp.Description("Optional filter string to match agent names"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(listAgentsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		filter := request.GetString("filter", "")
 
 		result, err := handler.ListAgents(ctx, filter)
@@ -51,9 +170,13 @@ func RegisterAgentTools(s *server.MCPServer, handler AgentHandler) {
 			mcp.Required(),
 			mcp.Description("Name of the agent to retrieve"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(getAgentTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		name := request.GetString("name", "")
 		if name == "" {
 			return mcp.NewToolResultError("agent name is required"), nil
@@ -67,6 +190,130 @@ func RegisterAgentTools(s *server.MCPServer, handler AgentHandler) {
 		return mcp.NewToolResultText(string(result)), nil
 	})
 
+	// List agent revisions tool
+	listAgentRevisionsTool := mcp.NewTool("list_agent_revisions",
+		mcp.WithDescription("List the revisions of an agent, newest first, with the one serving traffic marked active. The image is only known for the active revision."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the agent"),
+		),
+	)
+
+	s.AddTool(listAgentRevisionsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("agent name is required"), nil
+		}
+
+		result, err := handler.ListAgentRevisions(ctx, name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Create and update agent tools (only if not in readonly mode)
+	if !isReadOnly {
+		createAgentTool := mcp.NewTool("create_agent", append([]mcp.ToolOption{
+			mcp.WithDescription("Create an agent from a container image and deploy it"),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name for the agent"),
+			),
+		}, agentSettings()...)...)
+
+		s.AddTool(createAgentTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var args agentArgs
+			if err := request.BindArguments(&args); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid arguments: %v", err)), nil
+			}
+
+			if args.Name == "" {
+				return mcp.NewToolResultError("agent name is required"), nil
+			}
+			if args.Image == nil || *args.Image == "" {
+				return mcp.NewToolResultError("image is required"), nil
+			}
+
+			options, err := args.options()
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			result, err := handler.CreateAgent(ctx, args.Name, options, args.WaitForCompletion)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return mcp.NewToolResultText(string(result)), nil
+		})
+
+		updateAgentTool := mcp.NewTool("update_agent", append([]mcp.ToolOption{
+			mcp.WithDescription("Update the settings of an agent and redeploy it. Only the settings passed are changed. envs are merged into the current variables (remove one with unset_env), except that variables referencing a workspace secret can only be changed with set_env or unset_env; integrationConnections and labels replace the current ones."),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the agent to update"),
+			),
+		}, agentSettings()...)...)
+
+		s.AddTool(updateAgentTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var args agentArgs
+			if err := request.BindArguments(&args); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid arguments: %v", err)), nil
+			}
+
+			if args.Name == "" {
+				return mcp.NewToolResultError("agent name is required"), nil
+			}
+
+			options, err := args.options()
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			result, err := handler.UpdateAgent(ctx, args.Name, options, args.WaitForCompletion)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return mcp.NewToolResultText(string(result)), nil
+		})
+	}
+
+	// Rollback agent tool (only if not in readonly mode)
+	if !isReadOnly {
+		rollbackAgentTool := mcp.NewTool("rollback_agent",
+			mcp.WithDescription("Send all traffic of an agent back to a previous revision and wait for it to be deployed. Reports the previous and new active revision."),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the agent to roll back"),
+			),
+			mcp.WithString("revision",
+				mcp.Description("ID of the revision to roll back to, from list_agent_revisions (default: the previously active revision)"),
+			),
+			mcp.WithString("waitForCompletion",
+				mcp.Description("Whether to wait for the agent to reach a final status (true/false, default: true)"),
+			),
+		)
+
+		s.AddTool(rollbackAgentTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name := request.GetString("name", "")
+			if name == "" {
+				return mcp.NewToolResultError("agent name is required"), nil
+			}
+			revision := request.GetString("revision", "")
+			waitForCompletion := request.GetString("waitForCompletion", "")
+
+			result, err := handler.RollbackAgent(ctx, name, revision, waitForCompletion)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return mcp.NewToolResultText(string(result)), nil
+		})
+	}
+
 	// Delete agent tool (only if not in readonly mode)
 	if !isReadOnly {
 		deleteAgentTool := mcp.NewTool("delete_agent",
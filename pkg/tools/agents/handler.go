@@ -4,24 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	"sort"
 	"time"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/selector"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/utils"
 	"github.com/blaxel-ai/toolkit/sdk"
 )
 
 // SDKAgentHandler implements AgentHandler using the SDK client
 type SDKAgentHandler struct {
 	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
 	readOnly  bool
 }
 
 // NewSDKAgentHandler creates a new SDK-based agent handler
-func NewSDKAgentHandler(sdkClient *sdk.ClientWithResponses, readOnly bool) AgentHandler {
+func NewSDKAgentHandler(sdkClient *sdk.ClientWithResponses, cache *client.ResponseCache, readOnly bool) AgentHandler {
 	return &SDKAgentHandler{
 		sdkClient: sdkClient,
+		cache:     cache,
 		readOnly:  readOnly,
 	}
 }
@@ -32,7 +40,9 @@ func (h *SDKAgentHandler) ListAgents(ctx context.Context, filter string) ([]byte
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.ListAgentsWithResponse(ctx)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceAgents, "list", "", func() (*sdk.ListAgentsResponse, error) {
+		return h.sdkClient.ListAgentsWithResponse(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list agents: %w", err)
 	}
@@ -58,14 +68,31 @@ func (h *SDKAgentHandler) ListAgents(ctx context.Context, filter string) ([]byte
 		agents = filtered
 	}
 
+	// Keep the agents matching the label selector and status filters
+	agents = selector.Apply(ctx, agents, func(agent sdk.Agent) (*sdk.Metadata, *string) {
+		return agent.Metadata, agent.Status
+	})
+
+	// Sort by and project on SDK fields when the caller asked to
+	agents, shaped, done, err := formatter.Shape(ctx, agents)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return shaped, nil
+	}
+
 	// Convert SDK agents to simple models
 	agentModels := make([]formatter.AgentModel, len(agents))
 	for i, agent := range agents {
 		agentModels[i] = convertToAgentModel(agent)
 	}
 
-	// Format the agents using the formatter
-	formatted := formatter.FormatAgents(agentModels)
+	// Format the models in the requested output format
+	formatted, err := formatter.Render(formatter.FormatFromContext(ctx), agentModels, formatter.AgentColumns, formatter.FormatAgents)
+	if err != nil {
+		return nil, err
+	}
 	return []byte(formatted), nil
 }
 
@@ -75,7 +102,9 @@ func (h *SDKAgentHandler) GetAgent(ctx context.Context, name string) ([]byte, er
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.GetAgentWithResponse(ctx, name)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceAgents, "get", name, func() (*sdk.GetAgentResponse, error) {
+		return h.sdkClient.GetAgentWithResponse(ctx, name)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agent: %w", err)
 	}
@@ -97,12 +126,239 @@ func (h *SDKAgentHandler) GetAgent(ctx context.Context, name string) ([]byte, er
 	return jsonData, nil
 }
 
+// CreateAgent implements AgentHandler.CreateAgent
+func (h *SDKAgentHandler) CreateAgent(ctx context.Context, name string, options AgentOptions, waitForCompletion string) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	agentData := sdk.CreateAgentJSONRequestBody{
+		Metadata: &sdk.Metadata{
+			Name: &name,
+		},
+	}
+	if err := applyAgentOptions(&agentData, options); err != nil {
+		return nil, err
+	}
+
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckAvailable("agent", name, func() (int, error) {
+			resp, err := h.sdkClient.GetAgentWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Agent '%s' would be created", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodPost, Path: "/agents", Body: agentData})
+	}
+
+	// Create the agent
+	agent, err := h.sdkClient.CreateAgentWithResponse(ctx, agentData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent: %w", err)
+	}
+
+	if agent.JSON200 == nil {
+		if agent.StatusCode() == http.StatusConflict {
+			return nil, fmt.Errorf("agent with name '%s' already exists", name)
+		}
+		return nil, fmt.Errorf("failed to create agent with status %d", agent.StatusCode())
+	}
+
+	h.cache.Invalidate(client.ResourceAgents, name)
+
+	return h.waitForAgent(ctx, name, "created", agentData, waitForCompletion)
+}
+
+// UpdateAgent implements AgentHandler.UpdateAgent
+func (h *SDKAgentHandler) UpdateAgent(ctx context.Context, name string, options AgentOptions, waitForCompletion string) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	// Read the current agent so that the settings not passed are kept
+	current, err := h.sdkClient.GetAgentWithResponse(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+	if current.StatusCode() == http.StatusNotFound {
+		return nil, fmt.Errorf("agent '%s' not found", name)
+	}
+	if current.StatusCode() != http.StatusOK || current.JSON200 == nil {
+		return nil, fmt.Errorf("get agent failed with status %d", current.StatusCode())
+	}
+
+	agentData := *current.JSON200
+	if err := applyAgentOptions(&agentData, options); err != nil {
+		return nil, err
+	}
+
+	if tools.IsDryRun(ctx) {
+		return tools.DryRun(fmt.Sprintf("Agent '%s' would be updated and redeployed", name),
+			[]string{fmt.Sprintf("agent '%s' exists", name)},
+			tools.PlannedRequest{Method: http.MethodPut, Path: "/agents/" + name, Body: agentData})
+	}
+
+	// Update the agent
+	agent, err := h.sdkClient.UpdateAgentWithResponse(ctx, name, agentData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update agent: %w", err)
+	}
+
+	if agent.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("update agent failed with status %d", agent.StatusCode())
+	}
+
+	h.cache.Invalidate(client.ResourceAgents, name)
+
+	return h.waitForAgent(ctx, name, "updated", agentData, waitForCompletion)
+}
+
+// waitForAgent waits for a created or updated agent to deploy, unless asked
+// not to, and reports the outcome
+func (h *SDKAgentHandler) waitForAgent(ctx context.Context, name, action string, agent sdk.Agent, waitForCompletion string) ([]byte, error) {
+	// Check if we should wait for completion
+	waitForCompletionBool := true // default to true
+	if waitForCompletion != "" {
+		waitForCompletionBool = waitForCompletion == "true"
+	}
+
+	result := map[string]interface{}{
+		"success": true,
+		"agent":   convertToAgentModel(agent),
+	}
+
+	// Wait for the agent to reach a final status if requested
+	if waitForCompletionBool {
+		logger.Printf("Waiting for agent '%s' to deploy...", name)
+		checker := NewAgentStatusChecker(h.sdkClient)
+		if err := utils.WaitForResourceStatus(ctx, name, checker); err != nil {
+			// Even if status waiting fails, the agent was still created or updated
+			// Return a warning but don't fail the entire operation
+			logger.Printf("Warning: agent %s but status check failed: %v", action, err)
+			result["message"] = fmt.Sprintf("Agent '%s' %s successfully (status check failed: %v)", name, action, err)
+		} else {
+			result["message"] = fmt.Sprintf("Agent '%s' %s and deployed successfully", name, action)
+		}
+	} else {
+		logger.Printf("Skipping status wait for agent '%s'", name)
+		result["message"] = fmt.Sprintf("Agent '%s' %s successfully", name, action)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// applyAgentOptions sets the options that were passed on an agent
+func applyAgentOptions(agent *sdk.Agent, options AgentOptions) error {
+	if agent.Metadata == nil {
+		agent.Metadata = &sdk.Metadata{}
+	}
+	if agent.Spec == nil {
+		agent.Spec = &sdk.AgentSpec{}
+	}
+	if agent.Spec.Runtime == nil {
+		agent.Spec.Runtime = &sdk.Runtime{}
+	}
+
+	runtime := agent.Spec.Runtime
+	if options.Image != nil {
+		runtime.Image = options.Image
+	}
+	if options.Memory != nil {
+		runtime.Memory = options.Memory
+	}
+	if options.Generation != nil {
+		runtime.Generation = options.Generation
+	}
+	if options.MaxConcurrentTasks != nil {
+		runtime.MaxConcurrentTasks = options.MaxConcurrentTasks
+	}
+	if options.Envs != nil {
+		envs, err := mergeEnvs(runtime.Envs, options.Envs)
+		if err != nil {
+			return err
+		}
+		runtime.Envs = envs
+	}
+	if options.IntegrationConnections != nil {
+		connections := sdk.IntegrationConnectionsList(options.IntegrationConnections)
+		agent.Spec.IntegrationConnections = &connections
+	}
+	if options.Labels != nil {
+		labels := sdk.MetadataLabels(options.Labels)
+		agent.Metadata.Labels = &labels
+	}
+	return nil
+}
+
+// mergeEnvs sets envs on the runtime envs of an agent, keeping the variables
+// not passed. A variable referencing a workspace secret is left to set_env and
+// unset_env, which also delete the secret it replaces; changing it here would
+// leave that secret orphaned.
+func mergeEnvs(current *[]interface{}, envs map[string]string) (*[]interface{}, error) {
+	merged := []interface{}{}
+	seen := map[string]bool{}
+	if current != nil {
+		for _, entry := range *current {
+			env, ok := entry.(map[string]interface{})
+			name, _ := env["name"].(string)
+			value, set := envs[name]
+			if !ok || !set {
+				merged = append(merged, entry)
+				continue
+			}
+			seen[name] = true
+
+			old, _ := env["value"].(string)
+			if secret, ok := client.ReferencedSecret(old); ok && value != old {
+				return nil, fmt.Errorf("environment variable '%s' references secret '%s'; change it with set_env or unset_env", name, secret)
+			}
+			updated := maps.Clone(env)
+			updated["value"] = value
+			merged = append(merged, updated)
+		}
+	}
+
+	added := map[string]string{}
+	for name, value := range envs {
+		if !seen[name] {
+			added[name] = value
+		}
+	}
+	merged = append(merged, *tools.RuntimeEnvs(added)...)
+	return &merged, nil
+}
+
 // DeleteAgent implements AgentHandler.DeleteAgent
 func (h *SDKAgentHandler) DeleteAgent(ctx context.Context, name string) ([]byte, error) {
 	if h.sdkClient == nil {
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckExists("agent", name, func() (int, error) {
+			resp, err := h.sdkClient.GetAgentWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Agent '%s' would be deleted", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/agents/" + name})
+	}
+
 	resp, err := h.sdkClient.DeleteAgentWithResponse(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete agent: %w", err)
@@ -112,6 +368,8 @@ func (h *SDKAgentHandler) DeleteAgent(ctx context.Context, name string) ([]byte,
 		return nil, fmt.Errorf("delete agent failed with status %d", resp.StatusCode())
 	}
 
+	h.cache.Invalidate(client.ResourceAgents, name)
+
 	result := map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Agent '%s' deleted successfully", name),
@@ -125,11 +383,272 @@ func (h *SDKAgentHandler) DeleteAgent(ctx context.Context, name string) ([]byte,
 	return jsonData, nil
 }
 
+// agentRevision is a revision of an agent as listed by list_agent_revisions
+type agentRevision struct {
+	ID             string  `json:"id"`
+	Active         bool    `json:"active"`
+	Image          *string `json:"image,omitempty"`
+	Status         *string `json:"status,omitempty"`
+	CreatedAt      *string `json:"createdAt,omitempty"`
+	CreatedBy      *string `json:"createdBy,omitempty"`
+	TrafficPercent *int    `json:"trafficPercent,omitempty"`
+	PreviousActive bool    `json:"previousActive,omitempty"`
+}
+
+// ListAgentRevisions implements AgentHandler.ListAgentRevisions
+func (h *SDKAgentHandler) ListAgentRevisions(ctx context.Context, name string) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	agent, revisions, err := h.revisions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions do not record their image; the agent's image is the active one's
+	var image *string
+	if agent.Spec != nil && agent.Spec.Runtime != nil {
+		image = agent.Spec.Runtime.Image
+	}
+
+	items := make([]agentRevision, len(revisions))
+	for i, revision := range revisions {
+		items[i] = agentRevision{
+			ID:             *revision.Id,
+			Active:         revision.Active != nil && *revision.Active,
+			Status:         revision.Status,
+			CreatedAt:      revision.CreatedAt,
+			CreatedBy:      revision.CreatedBy,
+			TrafficPercent: revision.TrafficPercent,
+			PreviousActive: revision.PreviousActive != nil && *revision.PreviousActive,
+		}
+		if items[i].Active {
+			items[i].Image = image
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// RollbackAgent implements AgentHandler.RollbackAgent
+func (h *SDKAgentHandler) RollbackAgent(ctx context.Context, name, revision, waitForCompletion string) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	agent, revisions, err := h.revisions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := activeRevision(revisions)
+	target := revision
+	if target == "" {
+		target = rollbackTarget(revisions)
+		if target == "" {
+			return nil, fmt.Errorf("agent '%s' has no previous revision to roll back to", name)
+		}
+	}
+
+	found := false
+	for _, r := range revisions {
+		if *r.Id == target {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("revision '%s' not found for agent '%s' (see list_agent_revisions)", target, name)
+	}
+	if target == previous {
+		return nil, fmt.Errorf("revision '%s' is already active on agent '%s'", target, name)
+	}
+
+	// Point all traffic to the target revision, dropping any canary
+	if agent.Spec == nil {
+		agent.Spec = &sdk.AgentSpec{}
+	}
+	traffic := 100
+	agent.Spec.Revision = &sdk.RevisionConfiguration{
+		Active:  &target,
+		Traffic: &traffic,
+	}
+
+	if tools.IsDryRun(ctx) {
+		return tools.DryRun(fmt.Sprintf("Agent '%s' would be rolled back from revision '%s' to '%s'", name, previous, target),
+			[]string{fmt.Sprintf("agent '%s' exists", name), fmt.Sprintf("revision '%s' exists", target)},
+			tools.PlannedRequest{Method: http.MethodPut, Path: "/agents/" + name, Body: agent})
+	}
+
+	resp, err := h.sdkClient.UpdateAgentWithResponse(ctx, name, *agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back agent: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("roll back agent failed with status %d", resp.StatusCode())
+	}
+
+	h.cache.Invalidate(client.ResourceAgents, name)
+
+	result := map[string]interface{}{
+		"success":          true,
+		"previousRevision": previous,
+		"newRevision":      target,
+	}
+
+	// Check if we should wait for completion
+	waitForCompletionBool := true // default to true
+	if waitForCompletion != "" {
+		waitForCompletionBool = waitForCompletion == "true"
+	}
+
+	if waitForCompletionBool {
+		logger.Printf("Waiting for agent '%s' to deploy revision '%s'...", name, target)
+		checker := NewAgentStatusChecker(h.sdkClient)
+		if err := utils.WaitForResourceStatus(ctx, name, checker); err != nil {
+			// The rollback was still requested; report the status failure
+			logger.Printf("Warning: agent rolled back but status check failed: %v", err)
+			result["message"] = fmt.Sprintf("Agent '%s' rolled back to revision '%s' (status check failed: %v)", name, target, err)
+		} else {
+			result["message"] = fmt.Sprintf("Agent '%s' rolled back from revision '%s' to '%s' and deployed successfully", name, previous, target)
+		}
+
+		// Report the revision actually serving traffic now
+		if _, current, err := h.revisions(ctx, name); err == nil {
+			result["newRevision"] = activeRevision(current)
+		}
+	} else {
+		logger.Printf("Skipping status wait for agent '%s'", name)
+		result["message"] = fmt.Sprintf("Agent '%s' rolled back from revision '%s' to '%s' successfully", name, previous, target)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// revisions reads an agent, bypassing the cache, and its revisions newest first
+func (h *SDKAgentHandler) revisions(ctx context.Context, name string) (*sdk.Agent, []sdk.RevisionMetadata, error) {
+	agent, err := h.sdkClient.GetAgentWithResponse(ctx, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+	if agent.StatusCode() == http.StatusNotFound {
+		return nil, nil, fmt.Errorf("agent '%s' not found", name)
+	}
+	if agent.StatusCode() != http.StatusOK || agent.JSON200 == nil {
+		return nil, nil, fmt.Errorf("get agent failed with status %d", agent.StatusCode())
+	}
+
+	resp, err := h.sdkClient.ListAgentRevisionsWithResponse(ctx, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list agent revisions: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, nil, fmt.Errorf("list agent revisions failed with status %d", resp.StatusCode())
+	}
+
+	var revisions []sdk.RevisionMetadata
+	if resp.JSON200 != nil {
+		for _, revision := range *resp.JSON200 {
+			if revision.Id != nil {
+				revisions = append(revisions, revision)
+			}
+		}
+	}
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return stringValue(revisions[i].CreatedAt) > stringValue(revisions[j].CreatedAt)
+	})
+
+	return agent.JSON200, revisions, nil
+}
+
+// activeRevision returns the ID of the revision serving traffic
+func activeRevision(revisions []sdk.RevisionMetadata) string {
+	for _, revision := range revisions {
+		if revision.Active != nil && *revision.Active {
+			return *revision.Id
+		}
+	}
+	return ""
+}
+
+// rollbackTarget returns the revision to roll back to when none is given: the
+// previously active one, or else the newest revision older than the active one
+func rollbackTarget(revisions []sdk.RevisionMetadata) string {
+	for _, revision := range revisions {
+		if revision.PreviousActive != nil && *revision.PreviousActive {
+			return *revision.Id
+		}
+	}
+
+	pastActive := false
+	for _, revision := range revisions {
+		if revision.Active != nil && *revision.Active {
+			pastActive = true
+		} else if pastActive {
+			return *revision.Id
+		}
+	}
+	return ""
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // IsReadOnly implements AgentHandlerWithReadOnly.IsReadOnly
 func (h *SDKAgentHandler) IsReadOnly() bool {
 	return h.readOnly
 }
 
+// AgentStatusChecker implements StatusChecker for agents
+type AgentStatusChecker struct {
+	sdkClient *sdk.ClientWithResponses
+}
+
+// NewAgentStatusChecker creates a new agent status checker
+func NewAgentStatusChecker(sdkClient *sdk.ClientWithResponses) *AgentStatusChecker {
+	return &AgentStatusChecker{sdkClient: sdkClient}
+}
+
+// GetResource gets the agent resource
+func (a *AgentStatusChecker) GetResource(ctx context.Context, name string) (interface{}, error) {
+	return a.sdkClient.GetAgentWithResponse(ctx, name)
+}
+
+// ExtractStatus extracts status from agent response
+func (a *AgentStatusChecker) ExtractStatus(resource interface{}) string {
+	// Type assertion to get the agent response
+	if agentResp, ok := resource.(*sdk.GetAgentResponse); ok {
+		if agentResp.JSON200 != nil {
+			if agentResp.JSON200.Status == nil {
+				return "DEPLOYING"
+			}
+			return *agentResp.JSON200.Status
+		}
+	}
+	return "DEPLOYING" // Default assumption
+}
+
+// GetResourceType returns the resource type
+func (a *AgentStatusChecker) GetResourceType() utils.ResourceType {
+	return "agent"
+}
+
 // convertToAgentModel converts an SDK agent to a simple agent model
 func convertToAgentModel(agent sdk.Agent) formatter.AgentModel {
 	model := formatter.AgentModel{
@@ -0,0 +1,71 @@
+package agents
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func TestMergeEnvs(t *testing.T) {
+	secret := client.SecretReference("API_KEY_1234abcd_5678abcd")
+	current := func() *[]interface{} {
+		return &[]interface{}{
+			map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+			map[string]interface{}{"name": "API_KEY", "value": secret},
+			map[string]interface{}{"name": "REGION", "value": "eu", "secret": false},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		current *[]interface{}
+		envs    map[string]string
+		want    string
+		err     string
+	}{
+		{"new agent", nil, map[string]string{"B": "2", "A": "1"}, `[{"name":"A","value":"1"},{"name":"B","value":"2"}]`, ""},
+		{"keeps variables not passed", current(), map[string]string{"LOG_LEVEL": "debug", "PORT": "80"},
+			`[{"name":"LOG_LEVEL","value":"debug"},{"name":"API_KEY","value":"` + secret + `"},{"name":"REGION","secret":false,"value":"eu"},{"name":"PORT","value":"80"}]`, ""},
+		{"keeps the other fields of an entry", current(), map[string]string{"REGION": "us"},
+			`[{"name":"LOG_LEVEL","value":"info"},{"name":"API_KEY","value":"` + secret + `"},{"name":"REGION","secret":false,"value":"us"}]`, ""},
+		{"same secret reference", current(), map[string]string{"API_KEY": secret},
+			`[{"name":"LOG_LEVEL","value":"info"},{"name":"API_KEY","value":"` + secret + `"},{"name":"REGION","secret":false,"value":"eu"}]`, ""},
+		{"secret reference replaced", current(), map[string]string{"API_KEY": "sk-plain"}, "", "references secret 'API_KEY_1234abcd_5678abcd'; change it with set_env or unset_env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeEnvs(tt.current, tt.envs)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("mergeEnvs() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeEnvs() error = %v", err)
+			}
+			data, _ := json.Marshal(got)
+			if string(data) != tt.want {
+				t.Errorf("mergeEnvs() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyAgentOptionsKeepsSecrets(t *testing.T) {
+	secret := client.SecretReference("TOKEN_1234abcd_5678abcd")
+	envs := []interface{}{map[string]interface{}{"name": "TOKEN", "value": secret}}
+	agent := sdk.Agent{Spec: &sdk.AgentSpec{Runtime: &sdk.Runtime{Envs: &envs}}}
+
+	if err := applyAgentOptions(&agent, AgentOptions{Envs: map[string]string{"LOG_LEVEL": "debug"}}); err != nil {
+		t.Fatalf("applyAgentOptions() error = %v", err)
+	}
+	data, _ := json.Marshal(agent.Spec.Runtime.Envs)
+	if !strings.Contains(string(data), secret) || !strings.Contains(string(data), "LOG_LEVEL") {
+		t.Errorf("envs = %s", data)
+	}
+}
@@ -5,11 +5,11 @@ import (
 
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
-	"github.com/mark3labs/mcp-go/server"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 )
 
 // RegisterTools registers all agent-related tools using SDK client
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
+func RegisterTools(s tools.ToolRegistrar, cfg *config.Config) {
 	// Initialize SDK client
 	sdkClient, err := client.NewSDKClient(cfg)
 	if err != nil {
@@ -18,7 +18,7 @@ func RegisterTools(s *server.MCPServer, cfg *config.Config) {
 	}
 
 	// Create SDK-based handler
-	handler := NewSDKAgentHandler(sdkClient, cfg.ReadOnly)
+	handler := NewSDKAgentHandler(sdkClient, client.SharedCache(cfg), cfg.ReadOnly)
 
 	// Register tools using shared definitions
 	RegisterAgentTools(s, handler)
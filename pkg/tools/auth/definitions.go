@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AuthHandler defines the interface for authentication operations
+type AuthHandler interface {
+	LoginStatus(ctx context.Context) ([]byte, error)
+}
+
+// RegisterAuthTools registers authentication tools with the given handler
+func RegisterAuthTools(s tools.ToolRegistrar, handler AuthHandler) {
+	// Login status tool
+	loginStatusTool := mcp.NewTool("login_status",
+		mcp.WithDescription("Report the authentication method, identity and token expiry used by the server"),
+	)
+
+	s.AddTool(loginStatusTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler.LoginStatus(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/credentials"
+)
+
+// CredentialsHandler implements AuthHandler using the credentials manager
+type CredentialsHandler struct {
+	manager *credentials.Manager
+}
+
+// NewCredentialsHandler creates a new credentials-based auth handler
+func NewCredentialsHandler(manager *credentials.Manager) AuthHandler {
+	return &CredentialsHandler{
+		manager: manager,
+	}
+}
+
+// LoginStatus implements AuthHandler.LoginStatus
+func (h *CredentialsHandler) LoginStatus(ctx context.Context) ([]byte, error) {
+	if h.manager == nil {
+		return nil, fmt.Errorf("credentials manager not initialized")
+	}
+
+	status := h.manager.Status(ctx)
+
+	jsonData, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format login status: %w", err)
+	}
+
+	return jsonData, nil
+}
@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/credentials"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+)
+
+// RegisterTools registers all authentication-related tools
+func RegisterTools(s tools.ToolRegistrar, cfg *config.Config) {
+	// Use the manager that keeps the server's credentials fresh
+	handler := NewCredentialsHandler(credentials.Default())
+
+	// Register tools using shared definitions
+	RegisterAuthTools(s, handler)
+}
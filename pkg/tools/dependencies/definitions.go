@@ -0,0 +1,72 @@
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceTypes are the resource types in the dependency graph
+var ResourceTypes = []string{"agent", "function", "model", "job", "sandbox", "integration"}
+
+// DependencyHandler defines the interface for dependency operations
+type DependencyHandler interface {
+	GetDependencyGraph(ctx context.Context) ([]byte, error)
+	GetDependents(ctx context.Context, resourceType, name string, transitive bool) ([]byte, error)
+}
+
+// RegisterDependencyTools registers dependency tools with the given handler
+func RegisterDependencyTools(s tools.ToolRegistrar, handler DependencyHandler) {
+	// Get dependency graph tool
+	getDependencyGraphTool := mcp.NewTool("get_dependency_graph",
+		mcp.WithDescription("Build the dependency graph of the workspace: agents using model APIs and MCP servers (functions), and agents, models and MCP servers using integration connections. Returns the nodes and edges as JSON plus a Mermaid flowchart. References to resources that do not exist are marked as missing."),
+	)
+
+	s.AddTool(getDependencyGraphTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler.GetDependencyGraph(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Get dependents tool
+	getDependentsTool := mcp.NewTool("get_dependents",
+		mcp.WithDescription("List the resources that depend on a resource, with the path through which they use it. Use it before deleting a resource to see what would break."),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithBoolean("transitive",
+			mcp.Description("Include resources depending on it through others (default: true)"),
+		),
+	)
+
+	s.AddTool(getDependentsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType := request.GetString("resourceType", "")
+		if !slices.Contains(ResourceTypes, resourceType) {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown resource type '%s' (expected one of %s)", resourceType, strings.Join(ResourceTypes, ", "))), nil
+		}
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+
+		result, err := handler.GetDependents(ctx, resourceType, name, request.GetBool("transitive", true))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
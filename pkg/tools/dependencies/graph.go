@@ -0,0 +1,214 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Node is a resource of the workspace
+type Node struct {
+	ID      string `json:"id"` // type/name
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Status  string `json:"status,omitempty"`
+	Missing bool   `json:"missing,omitempty"` // referenced but not found in the workspace
+}
+
+// Edge is a reference from one resource to another
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"` // the spec field holding the reference
+}
+
+// Graph is the dependency graph of a workspace; edges point from a resource to
+// the resources it uses
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	index map[string]int
+}
+
+// Dependent is a resource that uses another one, directly or through others
+type Dependent struct {
+	ID    string   `json:"id"`
+	Type  string   `json:"type"`
+	Name  string   `json:"name"`
+	Depth int      `json:"depth"` // 1 for direct dependents
+	Path  []string `json:"path"`  // from the dependent to the resource
+}
+
+// references are the fields of a resource spec that name other resources,
+// decoded from the API response so that they do not depend on the SDK types
+type references struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		IntegrationConnections []string        `json:"integrationConnections"`
+		Functions              []string        `json:"functions"`
+		Model                  json.RawMessage `json:"model"` // a name on agents
+	} `json:"spec"`
+	Status string `json:"status"`
+}
+
+// NodeID returns the id of a resource in the graph
+func NodeID(resourceType, name string) string {
+	return resourceType + "/" + name
+}
+
+func newGraph() *Graph {
+	return &Graph{Nodes: []Node{}, Edges: []Edge{}, index: map[string]int{}}
+}
+
+// add adds the resources of one type from the body of a list response
+func (g *Graph) add(resourceType string, body []byte) error {
+	var items []references
+	if err := json.Unmarshal(body, &items); err != nil {
+		return fmt.Errorf("failed to decode %s list: %w", resourceType, err)
+	}
+
+	for _, item := range items {
+		if item.Metadata.Name == "" {
+			continue
+		}
+		from := g.node(resourceType, item.Metadata.Name)
+		g.Nodes[g.index[from]].Status = item.Status
+		g.Nodes[g.index[from]].Missing = false
+
+		for _, name := range item.Spec.IntegrationConnections {
+			g.edge(from, g.node("integration", name), "integrationConnections")
+		}
+		for _, name := range item.Spec.Functions {
+			g.edge(from, g.node("function", name), "functions")
+		}
+		var model string
+		if resourceType == "agent" && json.Unmarshal(item.Spec.Model, &model) == nil && model != "" {
+			g.edge(from, g.node("model", model), "model")
+		}
+	}
+	return nil
+}
+
+// node returns the id of a resource, adding it as missing until it is listed
+func (g *Graph) node(resourceType, name string) string {
+	id := NodeID(resourceType, name)
+	if _, ok := g.index[id]; !ok {
+		g.index[id] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, Node{ID: id, Type: resourceType, Name: name, Missing: true})
+	}
+	return id
+}
+
+func (g *Graph) edge(from, to, relation string) {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to && e.Relation == relation {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, Edge{From: from, To: to, Relation: relation})
+}
+
+// sort orders nodes and edges by id so that the output is stable
+func (g *Graph) sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	for i, n := range g.Nodes {
+		g.index[n.ID] = i
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+}
+
+// Lookup returns a node by id
+func (g *Graph) Lookup(id string) (Node, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return Node{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// Dependents returns the resources that use a resource, nearest first. With
+// transitive, resources using it through others are included.
+func (g *Graph) Dependents(id string, transitive bool) []Dependent {
+	users := map[string][]string{}
+	for _, e := range g.Edges {
+		users[e.To] = append(users[e.To], e.From)
+	}
+
+	dependents := []Dependent{}
+	seen := map[string]bool{id: true}
+	queue := []Dependent{{ID: id, Path: []string{id}}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.Depth > 0 && !transitive {
+			continue
+		}
+
+		for _, user := range users[current.ID] {
+			if seen[user] {
+				continue
+			}
+			seen[user] = true
+
+			node, _ := g.Lookup(user)
+			dependent := Dependent{
+				ID:    user,
+				Type:  node.Type,
+				Name:  node.Name,
+				Depth: current.Depth + 1,
+				Path:  append([]string{user}, current.Path...),
+			}
+			dependents = append(dependents, dependent)
+			queue = append(queue, dependent)
+		}
+	}
+
+	sort.SliceStable(dependents, func(i, j int) bool {
+		if dependents[i].Depth != dependents[j].Depth {
+			return dependents[i].Depth < dependents[j].Depth
+		}
+		return dependents[i].ID < dependents[j].ID
+	})
+	return dependents
+}
+
+// Mermaid renders the graph as a Mermaid flowchart
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		label := n.Type + ": " + n.Name
+		if n.Missing {
+			label += " (missing)"
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.ID], strings.ReplaceAll(label, `"`, "#quot;"))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], e.Relation, ids[e.To])
+	}
+
+	missing := []string{}
+	for _, n := range g.Nodes {
+		if n.Missing {
+			missing = append(missing, ids[n.ID])
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(&b, "  class %s missing\n", strings.Join(missing, ","))
+		b.WriteString("  classDef missing stroke-dasharray: 5 5\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package dependencies
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testGraph(t *testing.T) *Graph {
+	t.Helper()
+	graph := newGraph()
+	lists := map[string]string{
+		"agent": `[
+			{"metadata": {"name": "support"}, "spec": {"model": "gpt", "functions": ["search"]}, "status": "DEPLOYED"},
+			{"metadata": {"name": "triage"}, "spec": {"functions": ["search", "ticketing"]}},
+			{"metadata": {"name": "inline"}, "spec": {"model": {"provider": "openai"}}}
+		]`,
+		"function": `[
+			{"metadata": {"name": "search"}, "spec": {"integrationConnections": ["openai"]}},
+			{"metadata": {"name": "ticketing"}, "spec": {}}
+		]`,
+		"model":       `[{"metadata": {"name": "gpt"}, "spec": {"integrationConnections": ["openai"]}}]`,
+		"integration": `[{"metadata": {"name": "openai"}}]`,
+	}
+	for _, resourceType := range []string{"agent", "function", "model", "integration"} {
+		if err := graph.add(resourceType, []byte(lists[resourceType])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	graph.sort()
+	return graph
+}
+
+func TestDependents(t *testing.T) {
+	graph := testGraph(t)
+
+	tests := []struct {
+		name       string
+		id         string
+		transitive bool
+		want       []string // id@depth
+	}{
+		{"direct", "integration/openai", false, []string{"function/search@1", "model/gpt@1"}},
+		{"transitive", "integration/openai", true, []string{"function/search@1", "model/gpt@1", "agent/support@2", "agent/triage@2"}},
+		{"function", "function/search", true, []string{"agent/support@1", "agent/triage@1"}},
+		{"model", "model/gpt", true, []string{"agent/support@1"}},
+		{"leaf", "agent/support", true, []string{}},
+		{"unknown", "sandbox/none", true, []string{}},
+		{"missing node", "function/ticketing", true, []string{"agent/triage@1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, d := range graph.Dependents(tt.id, tt.transitive) {
+				got = append(got, d.ID+"@"+string(rune('0'+d.Depth)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dependents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependentsPath(t *testing.T) {
+	graph := testGraph(t)
+	for _, d := range graph.Dependents("integration/openai", true) {
+		if d.ID == "agent/support" {
+			// Reached through the first dependent by id, the function
+			if want := []string{"agent/support", "function/search", "integration/openai"}; !reflect.DeepEqual(d.Path, want) {
+				t.Errorf("path = %v, want %v", d.Path, want)
+			}
+			if d.Type != "agent" || d.Name != "support" {
+				t.Errorf("dependent = %+v", d)
+			}
+		}
+	}
+}
+
+func TestGraphAdd(t *testing.T) {
+	graph := testGraph(t)
+
+	if node, ok := graph.Lookup("agent/support"); !ok || node.Status != "DEPLOYED" || node.Missing {
+		t.Errorf("agent/support = %+v, %v", node, ok)
+	}
+	if _, ok := graph.Lookup("model/inline"); ok {
+		t.Error("an inline model object is not a reference")
+	}
+
+	missing := newGraph()
+	_ = missing.add("agent", []byte(`[{"metadata": {"name": "a"}, "spec": {"functions": ["gone"]}}]`))
+	if node, ok := missing.Lookup("function/gone"); !ok || !node.Missing {
+		t.Errorf("function/gone = %+v, want a missing node", node)
+	}
+	if err := missing.add("agent", []byte(`{}`)); err == nil {
+		t.Error("add() should reject a body that is not a list")
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	graph := newGraph()
+	_ = graph.add("agent", []byte(`[{"metadata": {"name": "a"}, "spec": {"functions": ["gone"]}}]`))
+	graph.sort()
+
+	got := graph.Mermaid()
+	for _, want := range []string{"graph LR", `n0["agent: a"]`, `n1["function: gone (missing)"]`, "n0 -->|functions| n1", "class n1 missing"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Mermaid() = %q, want it to contain %q", got, want)
+		}
+	}
+}
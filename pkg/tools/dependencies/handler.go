@@ -0,0 +1,184 @@
+package dependencies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// SDKDependencyHandler implements DependencyHandler using the SDK client
+type SDKDependencyHandler struct {
+	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
+}
+
+// NewSDKDependencyHandler creates a new SDK-based dependency handler
+func NewSDKDependencyHandler(sdkClient *sdk.ClientWithResponses, cache *client.ResponseCache) DependencyHandler {
+	return &SDKDependencyHandler{
+		sdkClient: sdkClient,
+		cache:     cache,
+	}
+}
+
+// GetDependencyGraph implements DependencyHandler.GetDependencyGraph
+func (h *SDKDependencyHandler) GetDependencyGraph(ctx context.Context) ([]byte, error) {
+	graph, err := h.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := 0
+	for _, node := range graph.Nodes {
+		if node.Missing {
+			missing++
+		}
+	}
+
+	result := map[string]interface{}{
+		"success": true,
+		"nodes":   graph.Nodes,
+		"edges":   graph.Edges,
+		"mermaid": graph.Mermaid(),
+		"message": fmt.Sprintf("%d resources, %d references, %d missing", len(graph.Nodes), len(graph.Edges), missing),
+	}
+	return tools.MarshalResult(result)
+}
+
+// GetDependents implements DependencyHandler.GetDependents
+func (h *SDKDependencyHandler) GetDependents(ctx context.Context, resourceType, name string, transitive bool) ([]byte, error) {
+	graph, err := h.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := NodeID(resourceType, name)
+	node, ok := graph.Lookup(id)
+	dependents := graph.Dependents(id, transitive)
+
+	result := map[string]interface{}{
+		"success":    true,
+		"resource":   id,
+		"exists":     ok && !node.Missing,
+		"dependents": dependents,
+	}
+
+	direct := 0
+	for _, dependent := range dependents {
+		if dependent.Depth == 1 {
+			direct++
+		}
+	}
+	switch {
+	case !ok:
+		result["message"] = fmt.Sprintf("%s '%s' not found and not referenced by any resource", resourceType, name)
+	case len(dependents) == 0:
+		result["message"] = fmt.Sprintf("No resource depends on %s '%s'", resourceType, name)
+	default:
+		result["message"] = fmt.Sprintf("%d resources depend on %s '%s' (%d directly); they may break if it is deleted", len(dependents), resourceType, name, direct)
+	}
+	if ok && node.Missing {
+		result["message"] = fmt.Sprintf("%s; %s '%s' does not exist", result["message"], resourceType, name)
+	}
+
+	return tools.MarshalResult(result)
+}
+
+// build lists every resource type and builds the graph. A failed list fails
+// the whole build, since a partial graph would hide dependents.
+func (h *SDKDependencyHandler) build(ctx context.Context) (*Graph, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	lists := []struct {
+		resourceType string
+		list         func() (int, []byte, interface{}, error)
+	}{
+		{"agent", func() (int, []byte, interface{}, error) {
+			resp, err := client.Fetch(ctx, h.cache, client.ResourceAgents, "list", "", func() (*sdk.ListAgentsResponse, error) {
+				return h.sdkClient.ListAgentsWithResponse(ctx)
+			})
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"function", func() (int, []byte, interface{}, error) {
+			resp, err := client.Fetch(ctx, h.cache, client.ResourceFunctions, "list", "", func() (*sdk.ListFunctionsResponse, error) {
+				return h.sdkClient.ListFunctionsWithResponse(ctx)
+			})
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"model", func() (int, []byte, interface{}, error) {
+			resp, err := client.Fetch(ctx, h.cache, client.ResourceModels, "list", "", func() (*sdk.ListModelsResponse, error) {
+				return h.sdkClient.ListModelsWithResponse(ctx)
+			})
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"job", func() (int, []byte, interface{}, error) {
+			resp, err := client.Fetch(ctx, h.cache, client.ResourceJobs, "list", "", func() (*sdk.ListJobsResponse, error) {
+				return h.sdkClient.ListJobsWithResponse(ctx)
+			})
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"sandbox", func() (int, []byte, interface{}, error) {
+			resp, err := client.Fetch(ctx, h.cache, client.ResourceSandboxes, "list", "", func() (*sdk.ListSandboxesResponse, error) {
+				return h.sdkClient.ListSandboxesWithResponse(ctx)
+			})
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"integration", func() (int, []byte, interface{}, error) {
+			resp, err := client.Fetch(ctx, h.cache, client.ResourceIntegrations, "list", "", func() (*sdk.ListIntegrationConnectionsResponse, error) {
+				return h.sdkClient.ListIntegrationConnectionsWithResponse(ctx)
+			})
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+	}
+
+	graph := newGraph()
+	for _, l := range lists {
+		status, body, typed, err := l.list()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s resources: %w", l.resourceType, err)
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("list %s resources failed with status %d", l.resourceType, status)
+		}
+
+		// Decode the raw body; fall back to the typed result when it is absent
+		if len(body) == 0 {
+			if body, err = json.Marshal(typed); err != nil {
+				return nil, fmt.Errorf("failed to encode %s list: %w", l.resourceType, err)
+			}
+		}
+		if string(body) == "null" {
+			continue
+		}
+		if err := graph.add(l.resourceType, body); err != nil {
+			return nil, err
+		}
+	}
+	graph.sort()
+
+	return graph, nil
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+)
+
+// deployTargets maps the type of blaxel.json to the resource `bl deploy`
+// creates or updates
+var deployTargets = map[string]Target{
+	"agent":    {"agent", client.ResourceAgents, "directory"},
+	"function": {"MCP server", client.ResourceFunctions, "directory"},
+	"job":      {"job", client.ResourceJobs, "directory"},
+	"sandbox":  {"sandbox", client.ResourceSandboxes, "directory"},
+}
+
+// DeployTarget returns the resource and name that `bl deploy` deploys a
+// directory to, from its blaxel.json: an agent named after the directory
+// unless type and name say otherwise. An empty directory is the current one.
+func DeployTarget(directory string) (target Target, name string) {
+	var project struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if data, err := os.ReadFile(filepath.Join(directory, "blaxel.json")); err == nil {
+		_ = json.Unmarshal(data, &project)
+	}
+
+	target, ok := deployTargets[project.Type]
+	if !ok {
+		target = deployTargets["agent"]
+	}
+	name = project.Name
+	if name == "" {
+		if abs, err := filepath.Abs(directory); err == nil {
+			name = filepath.Base(abs)
+		}
+	}
+	return target, name
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+)
+
+// projectDirectory creates a directory named my-project, with a blaxel.json
+// unless project is empty
+func projectDirectory(t *testing.T, project string) string {
+	t.Helper()
+	directory := filepath.Join(t.TempDir(), "my-project")
+	if err := os.Mkdir(directory, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if project != "" {
+		if err := os.WriteFile(filepath.Join(directory, "blaxel.json"), []byte(project), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return directory
+}
+
+func TestDeployTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  string // blaxel.json, none when empty
+		resource string
+		target   string
+	}{
+		{"agent by default", `{}`, client.ResourceAgents, "my-project"},
+		{"named function", `{"type": "function", "name": "search"}`, client.ResourceFunctions, "search"},
+		{"job", `{"type": "job"}`, client.ResourceJobs, "my-project"},
+		{"unknown type", `{"type": "volume", "name": "v"}`, client.ResourceAgents, "v"},
+		{"no blaxel.json", "", client.ResourceAgents, "my-project"},
+		{"invalid blaxel.json", `{`, client.ResourceAgents, "my-project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, name := DeployTarget(projectDirectory(t, tt.project))
+			if target.Resource != tt.resource || name != tt.target {
+				t.Errorf("DeployTarget() = %s, %s, want %s, %s", target.Resource, name, tt.resource, tt.target)
+			}
+		})
+	}
+}
+
+func TestGuardDeployDirectory(t *testing.T) {
+	lookup := func(ctx context.Context, resource, name string) (map[string]string, bool, error) {
+		if resource == client.ResourceFunctions && name == "search" {
+			return map[string]string{"protected": "true"}, true, nil
+		}
+		return nil, false, nil
+	}
+	guard, err := NewGuard(config.Protection{Names: []string{"prod-*"}, Labels: map[string]string{"protected": "true"}}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		project string
+		err     string
+	}{
+		{"unprotected", `{"type": "function", "name": "staging-search"}`, ""},
+		{"protected name", `{"type": "job", "name": "prod-sync"}`, "job 'prod-sync' is protected (name matches 'prod-*')"},
+		{"protected label", `{"type": "function", "name": "search"}`, "MCP server 'search' is protected"},
+		{"agent named after the directory", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := guard.Check(context.Background(), "local_deploy_directory", map[string]interface{}{
+				"directory": projectDirectory(t, tt.project),
+			})
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Check() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
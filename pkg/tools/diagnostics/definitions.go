@@ -0,0 +1,99 @@
+package diagnostics
+
+import (
+	"context"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DiagnosticsHandler defines the interface for server diagnostics operations
+type DiagnosticsHandler interface {
+	GetCacheStats(ctx context.Context) ([]byte, error)
+	ListAuditEvents(ctx context.Context, since, until, tool, outcome string, limit int) ([]byte, error)
+	ReadOutput(ctx context.Context, id string, offset, length int) ([]byte, error)
+}
+
+// RegisterDiagnosticsTools registers diagnostics tools with the given handler
+func RegisterDiagnosticsTools(s tools.ToolRegistrar, handler DiagnosticsHandler) {
+	// Cache stats tool
+	cacheStatsTool := mcp.NewTool("get_cache_stats",
+		mcp.WithDescription("Report the response cache TTL, size and hit/miss counters per operation"),
+	)
+
+	s.AddTool(cacheStatsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler.GetCacheStats(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// List audit events tool
+	listAuditEventsTool := mcp.NewTool("list_audit_events",
+		mcp.WithDescription("List recorded write tool calls from the audit log, most recent last"),
+		mcp.WithString("since",
+			mcp.Description("Only events at or after this time (RFC3339, or a duration such as 24h meaning that long ago)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("Only events at or before this time (RFC3339, or a duration such as 1h meaning that long ago)"),
+		),
+		mcp.WithString("tool",
+			mcp.Description("Only events for tools matching this glob pattern (e.g. delete_*)"),
+		),
+		mcp.WithString("outcome",
+			mcp.Description("Only events with this outcome"),
+			mcp.Enum("success", "error"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of most recent events to return (default: 100)"),
+		),
+	)
+
+	s.AddTool(listAuditEventsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		since := request.GetString("since", "")
+		until := request.GetString("until", "")
+		tool := request.GetString("tool", "")
+		outcome := request.GetString("outcome", "")
+		limit := request.GetInt("limit", 100)
+
+		result, err := handler.ListAuditEvents(ctx, since, until, tool, outcome, limit)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Read output tool
+	readOutputTool := mcp.NewTool(tools.ReadOutputTool,
+		mcp.WithDescription("Read a range of a tool result that was shortened to fit maxOutputChars. The response gives the next offset until the end of the output."),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("Id of the shortened output, given in the note of the shortened result"),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Character offset to start reading at (default: 0)"),
+		),
+		mcp.WithNumber("length",
+			mcp.Description("Maximum number of characters to read (default: 10000)"),
+		),
+	)
+
+	s.AddTool(readOutputTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id := request.GetString("id", "")
+		if id == "" {
+			return mcp.NewToolResultError("output id is required"), nil
+		}
+		offset := request.GetInt("offset", 0)
+		length := request.GetInt("length", 10000)
+
+		result, err := handler.ReadOutput(ctx, id, offset, length)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
@@ -0,0 +1,136 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/audit"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/truncate"
+)
+
+// ServerDiagnosticsHandler implements DiagnosticsHandler for the running server
+type ServerDiagnosticsHandler struct {
+	workspace string
+	cache     *client.ResponseCache
+	auditLog  *audit.Log
+	outputs   *truncate.Store
+}
+
+// NewServerDiagnosticsHandler creates a new diagnostics handler
+func NewServerDiagnosticsHandler(workspace string, cache *client.ResponseCache, auditLog *audit.Log, outputs *truncate.Store) DiagnosticsHandler {
+	return &ServerDiagnosticsHandler{
+		workspace: workspace,
+		cache:     cache,
+		auditLog:  auditLog,
+		outputs:   outputs,
+	}
+}
+
+// GetCacheStats implements DiagnosticsHandler.GetCacheStats
+func (h *ServerDiagnosticsHandler) GetCacheStats(ctx context.Context) ([]byte, error) {
+	operations := h.cache.Stats()
+
+	var totals client.CacheStats
+	for _, stats := range operations {
+		totals.Hits += stats.Hits
+		totals.Misses += stats.Misses
+		totals.Bypassed += stats.Bypassed
+		totals.Invalidations += stats.Invalidations
+	}
+
+	hitRate := 0.0
+	if lookups := totals.Hits + totals.Misses; lookups > 0 {
+		hitRate = float64(totals.Hits) / float64(lookups)
+	}
+
+	report := map[string]interface{}{
+		"workspace":  h.workspace,
+		"enabled":    h.cache.TTL() > 0,
+		"ttl":        h.cache.TTL().String(),
+		"entries":    h.cache.Size(),
+		"hitRate":    hitRate,
+		"totals":     totals,
+		"operations": operations,
+	}
+
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format cache stats: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// ListAuditEvents implements DiagnosticsHandler.ListAuditEvents
+func (h *ServerDiagnosticsHandler) ListAuditEvents(ctx context.Context, since, until, tool, outcome string, limit int) ([]byte, error) {
+	if h.auditLog == nil {
+		return nil, fmt.Errorf("audit log is disabled")
+	}
+
+	filter := audit.Filter{
+		Tool:    tool,
+		Outcome: outcome,
+		Limit:   limit,
+	}
+
+	var err error
+	if filter.Since, err = parseTime(since); err != nil {
+		return nil, fmt.Errorf("invalid since: %w", err)
+	}
+	if filter.Until, err = parseTime(until); err != nil {
+		return nil, fmt.Errorf("invalid until: %w", err)
+	}
+	if tool != "" {
+		if _, err := path.Match(tool, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool pattern %q: %w", tool, err)
+		}
+	}
+
+	events, err := h.auditLog.Read(filter)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []audit.Event{}
+	}
+
+	jsonData, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format audit events: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// ReadOutput implements DiagnosticsHandler.ReadOutput
+func (h *ServerDiagnosticsHandler) ReadOutput(ctx context.Context, id string, offset, length int) ([]byte, error) {
+	chunk, err := h.outputs.Read(id, offset, length)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(chunk, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format output: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// parseTime accepts an RFC3339 timestamp or a duration counted back from now
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither an RFC3339 time nor a duration", value)
+}
@@ -0,0 +1,19 @@
+package diagnostics
+
+import (
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/audit"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/truncate"
+)
+
+// RegisterTools registers all diagnostics tools
+func RegisterTools(s tools.ToolRegistrar, cfg *config.Config) {
+	// Report on the cache shared by the resource toolsets and the audit log,
+	// and read back the outputs shortened by the truncation middleware
+	handler := NewServerDiagnosticsHandler(cfg.Workspace, client.SharedCache(cfg), audit.Default(), truncate.Default())
+
+	// Register tools using shared definitions
+	RegisterDiagnosticsTools(s, handler)
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DryRunArg is the per-call argument that previews a mutation instead of running it
+const DryRunArg = "dryRun"
+
+// dryRunPrefixes are the write tools whose handlers can preview their requests.
+// Other write tools (run_*, local_*) are refused outright in dry-run mode.
+var dryRunPrefixes = []string{"create_", "update_", "delete_", "invite_", "remove_", "set_", "unset_", "rollback_", "scale_"}
+
+type dryRunKey struct{}
+
+// SupportsDryRun reports whether a tool can preview its request
+func SupportsDryRun(name string) bool {
+	for _, prefix := range dryRunPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDryRun returns a context that tells handlers not to call mutating endpoints
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether the current call is a dry run
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// PlannedRequest is a request a handler would have sent to the API
+type PlannedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Body   interface{} `json:"body,omitempty"`
+}
+
+// DryRunResult is returned by handlers instead of calling the mutating endpoint
+type DryRunResult struct {
+	DryRun   bool             `json:"dryRun"`
+	Message  string           `json:"message"`
+	Checks   []string         `json:"checks,omitempty"`
+	Requests []PlannedRequest `json:"requests"`
+}
+
+// DryRun formats the requests a handler would send, along with the checks it
+// ran to validate the inputs and resolve dependencies
+func DryRun(message string, checks []string, requests ...PlannedRequest) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(DryRunResult{
+		DryRun:   true,
+		Message:  message,
+		Checks:   checks,
+		Requests: requests,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format dry run: %w", err)
+	}
+	return jsonData, nil
+}
+
+// CheckExists describes the result of a get call made during a dry run.
+// A 404 is an error, since the real call would fail the same way.
+func CheckExists(kind, name string, get func() (int, error)) (string, error) {
+	status, err := get()
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+
+	switch {
+	case status == http.StatusNotFound:
+		return "", fmt.Errorf("%s '%s' not found", kind, name)
+	case status >= 400:
+		return "", fmt.Errorf("failed to get %s '%s' with status %d", kind, name, status)
+	}
+	return fmt.Sprintf("%s '%s' exists", kind, name), nil
+}
+
+// CheckAvailable describes whether a name is still free for a create call made
+// during a dry run. An existing resource is an error, since the real call
+// would conflict.
+func CheckAvailable(kind, name string, get func() (int, error)) (string, error) {
+	status, err := get()
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+
+	switch {
+	case status == http.StatusOK:
+		return "", fmt.Errorf("%s with name '%s' already exists", kind, name)
+	case status != http.StatusNotFound:
+		return "", fmt.Errorf("failed to get %s '%s' with status %d", kind, name, status)
+	}
+	return fmt.Sprintf("no %s named '%s' exists yet", kind, name), nil
+}
+
+// withDryRun adds the dryRun argument to a tool's input schema
+func withDryRun(tool mcp.Tool) mcp.Tool {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[DryRunArg] = map[string]any{
+		"type":        "boolean",
+		"description": "Validate the inputs and return the request that would be sent, without changing anything",
+	}
+	return tool
+}
+
+// DryRunMiddleware marks write calls as dry runs when the server runs with
+// --dry-run or the caller passes dryRun. Write tools that cannot preview their
+// requests are refused rather than executed.
+func (p *Policy) DryRunMiddleware(global bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if p.Access(request.Params.Name) != AccessWrite {
+				return next(ctx, request)
+			}
+			if !global && !request.GetBool(DryRunArg, false) {
+				return next(ctx, request)
+			}
+
+			if !SupportsDryRun(request.Params.Name) {
+				return mcp.NewToolResultError(fmt.Sprintf("tool %s has no dry-run preview; it was not executed", request.Params.Name)), nil
+			}
+
+			return next(WithDryRun(ctx), request)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package dynamic
+
+import (
+	"context"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolsetHandler defines the interface for dynamic toolset operations
+type ToolsetHandler interface {
+	ListAvailableToolsets(ctx context.Context) ([]byte, error)
+	GetToolsetTools(ctx context.Context, name string) ([]byte, error)
+	EnableToolset(ctx context.Context, name string) ([]byte, error)
+}
+
+// RegisterToolsetTools registers the toolset discovery tools with the given handler
+func RegisterToolsetTools(s tools.ToolRegistrar, handler ToolsetHandler) {
+	// List available toolsets tool
+	listToolsetsTool := mcp.NewTool("list_available_toolsets",
+		mcp.WithDescription("List the toolsets that can be enabled, with their description and whether they are enabled"),
+	)
+
+	s.AddTool(listToolsetsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler.ListAvailableToolsets(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Get toolset tools tool
+	getToolsetToolsTool := mcp.NewTool("get_toolset_tools",
+		mcp.WithDescription("List the tools of a toolset without enabling it"),
+		mcp.WithString("toolset",
+			mcp.Required(),
+			mcp.Description("Name of the toolset (see list_available_toolsets)"),
+		),
+	)
+
+	s.AddTool(getToolsetToolsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.GetString("toolset", "")
+		if name == "" {
+			return mcp.NewToolResultError("toolset is required"), nil
+		}
+
+		result, err := handler.GetToolsetTools(ctx, name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Enable toolset tool
+	enableToolsetTool := mcp.NewTool("enable_toolset",
+		mcp.WithDescription("Enable a toolset so that its tools become available"),
+		mcp.WithString("toolset",
+			mcp.Required(),
+			mcp.Description("Name of the toolset to enable (see list_available_toolsets)"),
+		),
+	)
+
+	s.AddTool(enableToolsetTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.GetString("toolset", "")
+		if name == "" {
+			return mcp.NewToolResultError("toolset is required"), nil
+		}
+
+		result, err := handler.EnableToolset(ctx, name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
@@ -0,0 +1,84 @@
+package dynamic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+)
+
+// RegistryToolsetHandler implements ToolsetHandler on top of the tool registry
+type RegistryToolsetHandler struct {
+	registry *tools.Registry
+}
+
+// NewRegistryToolsetHandler creates a new registry-based toolset handler
+func NewRegistryToolsetHandler(registry *tools.Registry) ToolsetHandler {
+	return &RegistryToolsetHandler{
+		registry: registry,
+	}
+}
+
+// ListAvailableToolsets implements ToolsetHandler.ListAvailableToolsets
+func (h *RegistryToolsetHandler) ListAvailableToolsets(ctx context.Context) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(h.registry.Toolsets(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format toolsets: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// GetToolsetTools implements ToolsetHandler.GetToolsetTools
+func (h *RegistryToolsetHandler) GetToolsetTools(ctx context.Context, name string) ([]byte, error) {
+	toolsetTools, err := h.registry.ToolsetTools(name)
+	if err != nil {
+		return nil, err
+	}
+
+	type toolSummary struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	summaries := make([]toolSummary, 0, len(toolsetTools))
+	for _, tool := range toolsetTools {
+		summaries = append(summaries, toolSummary{
+			Name:        tool.Name,
+			Description: tool.Description,
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format toolset tools: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// EnableToolset implements ToolsetHandler.EnableToolset
+func (h *RegistryToolsetHandler) EnableToolset(ctx context.Context, name string) ([]byte, error) {
+	names, err := h.registry.EnableToolset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Printf("Enabled toolset '%s': %s", name, strings.Join(names, ", "))
+
+	result := map[string]interface{}{
+		"toolset": name,
+		"enabled": true,
+		"tools":   names,
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format result: %w", err)
+	}
+
+	return jsonData, nil
+}
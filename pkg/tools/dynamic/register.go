@@ -0,0 +1,13 @@
+package dynamic
+
+import (
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+)
+
+// RegisterTools registers the toolset discovery tools used by --dynamic-toolsets
+func RegisterTools(registry *tools.Registry) {
+	handler := NewRegistryToolsetHandler(registry)
+
+	// Discovery tools are added directly so they are exposed from the start
+	RegisterToolsetTools(registry, handler)
+}
@@ -0,0 +1,214 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceTypes are the resource types whose environment can be changed
+var ResourceTypes = []string{"agent", "function", "job", "sandbox"}
+
+// envNamePattern matches valid environment variable names
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvUpdate describes the variables set by set_env
+type EnvUpdate struct {
+	Values    map[string]string
+	Secrets   []string // names stored as workspace secrets even if they do not look secret
+	Plaintext []string // names kept in plaintext even if they look secret
+}
+
+// EnvHandler defines the interface for environment variable operations
+type EnvHandler interface {
+	ListEnv(ctx context.Context, resourceType, name string) ([]byte, error)
+	SetEnv(ctx context.Context, resourceType, name string, update EnvUpdate, waitForCompletion string) ([]byte, error)
+	UnsetEnv(ctx context.Context, resourceType, name string, names []string, waitForCompletion string) ([]byte, error)
+}
+
+// EnvHandlerWithReadOnly extends EnvHandler with readonly capability
+type EnvHandlerWithReadOnly interface {
+	EnvHandler
+	IsReadOnly() bool
+}
+
+// RegisterEnvTools registers environment variable tools with the given handler
+func RegisterEnvTools(s tools.ToolRegistrar, handler EnvHandler) {
+	// Check if handler supports readonly mode
+	readOnlyHandler, hasReadOnly := handler.(EnvHandlerWithReadOnly)
+	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
+
+	// List environment tool
+	listEnvTool := mcp.NewTool("list_env",
+		mcp.WithDescription("List the environment variables of an agent, MCP server (function), job or sandbox. Secret values are redacted; variables stored as workspace secrets show the secret they reference."),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+	)
+
+	s.AddTool(listEnvTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType := request.GetString("resourceType", "")
+		if resourceType == "" {
+			return mcp.NewToolResultError("resourceType is required"), nil
+		}
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+
+		result, err := handler.ListEnv(ctx, resourceType, name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Setting and unsetting variables modify the workspace
+	if isReadOnly {
+		return
+	}
+
+	// Set environment tool
+	setEnvTool := mcp.NewTool("set_env",
+		mcp.WithDescription("Add or change environment variables of an agent, MCP server (function), job or sandbox, and redeploy it. Other variables are kept. Values that look secret (names containing KEY, TOKEN, SECRET or PASSWORD, or values shaped like API keys) are stored as new workspace secrets and referenced as ${secrets.NAME} instead of being written in plaintext. Secrets this tool created for the replaced values are deleted once the resource is updated, unless another resource references them."),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithObject("envs",
+			mcp.Required(),
+			mcp.Description("Variables to set, as name/value pairs (e.g. {\"LOG_LEVEL\": \"debug\"})"),
+		),
+		mcp.WithArray("secrets",
+			mcp.Description("Names of variables to store as workspace secrets even if they do not look secret"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("plaintext",
+			mcp.Description("Names of variables to write in plaintext even if they look secret"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("waitForCompletion",
+			mcp.Description("Wait for the redeployment to complete (default: true)"),
+		),
+	)
+
+	s.AddTool(setEnvTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		type SetEnvArgs struct {
+			ResourceType      string                 `json:"resourceType"`
+			Name              string                 `json:"name"`
+			Envs              map[string]interface{} `json:"envs"`
+			Secrets           []string               `json:"secrets"`
+			Plaintext         []string               `json:"plaintext"`
+			WaitForCompletion string                 `json:"waitForCompletion"`
+		}
+
+		var args SetEnvArgs
+		if err := request.BindArguments(&args); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid arguments: %v", err)), nil
+		}
+
+		if args.ResourceType == "" {
+			return mcp.NewToolResultError("resourceType is required"), nil
+		}
+		if args.Name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+		if len(args.Envs) == 0 {
+			return mcp.NewToolResultError("at least one variable is required"), nil
+		}
+
+		// Values are strings; numbers and booleans are written as text
+		update := EnvUpdate{Values: make(map[string]string, len(args.Envs)), Secrets: args.Secrets, Plaintext: args.Plaintext}
+		for key, value := range args.Envs {
+			if !envNamePattern.MatchString(key) {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid variable name %q", key)), nil
+			}
+			switch v := value.(type) {
+			case string:
+				update.Values[key] = v
+			case float64, bool:
+				update.Values[key] = fmt.Sprint(v)
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("variable '%s' must be a string", key)), nil
+			}
+		}
+		for _, key := range append(append([]string{}, args.Secrets...), args.Plaintext...) {
+			if _, ok := update.Values[key]; !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("'%s' is not one of the variables set", key)), nil
+			}
+		}
+		for _, key := range args.Secrets {
+			for _, other := range args.Plaintext {
+				if key == other {
+					return mcp.NewToolResultError(fmt.Sprintf("'%s' cannot be both a secret and plaintext", key)), nil
+				}
+			}
+		}
+
+		result, err := handler.SetEnv(ctx, args.ResourceType, args.Name, update, args.WaitForCompletion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Unset environment tool
+	unsetEnvTool := mcp.NewTool("unset_env",
+		mcp.WithDescription("Remove environment variables from an agent, MCP server (function), job or sandbox, and redeploy it. Names that are not set are ignored. Workspace secrets that set_env created for the variables are deleted unless another resource references them; other secrets are kept."),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithArray("names",
+			mcp.Required(),
+			mcp.Description("Names of the variables to remove"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("waitForCompletion",
+			mcp.Description("Wait for the redeployment to complete (default: true)"),
+		),
+	)
+
+	s.AddTool(unsetEnvTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType := request.GetString("resourceType", "")
+		if resourceType == "" {
+			return mcp.NewToolResultError("resourceType is required"), nil
+		}
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+		names := request.GetStringSlice("names", nil)
+		if len(names) == 0 {
+			return mcp.NewToolResultError("at least one variable name is required"), nil
+		}
+
+		result, err := handler.UnsetEnv(ctx, resourceType, name, names, request.GetString("waitForCompletion", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
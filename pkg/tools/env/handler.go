@@ -0,0 +1,587 @@
+package env
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// SDKEnvHandler implements EnvHandler using the SDK client
+type SDKEnvHandler struct {
+	sdkClient *sdk.ClientWithResponses
+	secrets   *client.SecretsClient
+	cache     *client.ResponseCache
+	readOnly  bool
+
+	// references returns the resources referencing each workspace secret
+	references func(ctx context.Context) (map[string][]string, error)
+}
+
+// NewSDKEnvHandler creates a new SDK-based environment handler
+func NewSDKEnvHandler(sdkClient *sdk.ClientWithResponses, secrets *client.SecretsClient, cache *client.ResponseCache, readOnly bool) EnvHandler {
+	h := &SDKEnvHandler{
+		sdkClient: sdkClient,
+		secrets:   secrets,
+		cache:     cache,
+		readOnly:  readOnly,
+	}
+	h.references = h.secretReferences
+	return h
+}
+
+// envVar is one entry of Spec.Runtime.Envs
+type envVar struct {
+	Name  string
+	Value string
+	raw   map[string]interface{} // the entry as read, to keep fields other than name and value
+}
+
+// envView is an environment variable as shown to the client
+type envView struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	SecretRef string `json:"secretRef,omitempty"` // workspace secret referenced by the value
+	Redacted  bool   `json:"redacted,omitempty"`
+}
+
+// envChange describes a modification of the environment of a resource
+type envChange struct {
+	apply             func(current []envVar) []envVar
+	secrets           map[string]string // workspace secrets to store before the update
+	resourceType      string            // owner of the secrets, with the resource name
+	waitForCompletion string
+}
+
+// ListEnv implements EnvHandler.ListEnv
+func (h *SDKEnvHandler) ListEnv(ctx context.Context, resourceType, name string) ([]byte, error) {
+	return h.run(ctx, resourceType, name, nil)
+}
+
+// SetEnv implements EnvHandler.SetEnv
+func (h *SDKEnvHandler) SetEnv(ctx context.Context, resourceType, name string, update EnvUpdate, waitForCompletion string) ([]byte, error) {
+	change, err := setChange(resourceType, name, update, waitForCompletion)
+	if err != nil {
+		return nil, err
+	}
+	return h.run(ctx, resourceType, name, change)
+}
+
+// setChange builds the change of set_env, storing the values that look secret
+// as new workspace secrets referenced by the variables
+func setChange(resourceType, name string, update EnvUpdate, waitForCompletion string) (*envChange, error) {
+	values := make(map[string]string, len(update.Values))
+	secrets := map[string]string{}
+	for key, value := range update.Values {
+		if _, ok := client.ReferencedSecret(value); ok || !isSecret(key, value, update) {
+			values[key] = value
+			continue
+		}
+
+		secret := secretName(resourceType, name, key)
+		if err := client.ValidateSecretName(secret); err != nil {
+			return nil, err
+		}
+		secrets[secret] = value
+		values[key] = client.SecretReference(secret)
+	}
+
+	return &envChange{
+		apply: func(current []envVar) []envVar {
+			after := slices.Clone(current)
+			for i := range after {
+				if value, ok := values[after[i].Name]; ok {
+					after[i].Value = value
+				}
+			}
+
+			// New variables go last, by name
+			added := []string{}
+			for key := range values {
+				if !slices.ContainsFunc(current, func(v envVar) bool { return v.Name == key }) {
+					added = append(added, key)
+				}
+			}
+			sort.Strings(added)
+			for _, key := range added {
+				after = append(after, envVar{Name: key, Value: values[key]})
+			}
+			return after
+		},
+		secrets:           secrets,
+		resourceType:      resourceType,
+		waitForCompletion: waitForCompletion,
+	}, nil
+}
+
+// UnsetEnv implements EnvHandler.UnsetEnv
+func (h *SDKEnvHandler) UnsetEnv(ctx context.Context, resourceType, name string, names []string, waitForCompletion string) ([]byte, error) {
+	return h.run(ctx, resourceType, name, &envChange{
+		apply: func(current []envVar) []envVar {
+			return slices.DeleteFunc(slices.Clone(current), func(v envVar) bool {
+				return slices.Contains(names, v.Name)
+			})
+		},
+		resourceType:      resourceType,
+		waitForCompletion: waitForCompletion,
+	})
+}
+
+// IsReadOnly implements EnvHandlerWithReadOnly.IsReadOnly
+func (h *SDKEnvHandler) IsReadOnly() bool {
+	return h.readOnly
+}
+
+// run lists the environment of a resource, or applies change to it with the
+// update call of its type
+func (h *SDKEnvHandler) run(ctx context.Context, resourceType, name string, change *envChange) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	switch resourceType {
+	case "agent":
+		return applyEnv(ctx, h, client.AgentOps(ctx, h.sdkClient, name), name, change)
+	case "function":
+		return applyEnv(ctx, h, client.FunctionOps(ctx, h.sdkClient, name), name, change)
+	case "job":
+		return applyEnv(ctx, h, client.JobOps(ctx, h.sdkClient, name), name, change)
+	case "sandbox":
+		return applyEnv(ctx, h, client.SandboxOps(ctx, h.sdkClient, name), name, change)
+	}
+
+	return nil, fmt.Errorf("unknown resource type '%s' (expected one of %s)", resourceType, strings.Join(ResourceTypes, ", "))
+}
+
+// readEnv fetches the resource, bypassing the cache, and returns it with its environment
+func readEnv[T any](ops client.ResourceOps[T], name string) (*T, []envVar, error) {
+	item, err := ops.Read(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var envs []envVar
+	if runtime := *ops.Runtime(item); runtime != nil && runtime.Envs != nil {
+		envs = decodeEnvs(*runtime.Envs)
+	}
+	return item, envs, nil
+}
+
+// applyEnv lists the environment of a resource when change is nil. Otherwise
+// it stores the secrets of the change, writes the new environment, deletes the
+// secrets the resource no longer references and waits for the resource to
+// redeploy. Like labels, concurrent changes are detected by reading the
+// resource again right before the update, and the new environment is written
+// onto that second read so that other changes to the resource are kept.
+//
+// Secrets are never overwritten: set_env stores each value under a new name,
+// so the deployed resource keeps resolving the previous one until the update
+// succeeds. A failed update deletes the new secrets, and the secrets replaced
+// or unset are only deleted when this variable of this resource created them
+// and no other resource references them.
+func applyEnv[T any](ctx context.Context, h *SDKEnvHandler, ops client.ResourceOps[T], name string, change *envChange) ([]byte, error) {
+	item, before, err := readEnv(ops, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if change == nil {
+		return tools.MarshalResult(map[string]interface{}{
+			"resource": ops.Kind,
+			"name":     name,
+			"envs":     views(before),
+		})
+	}
+
+	after := change.apply(before)
+	secrets := redact.Keys(change.secrets)
+	obsolete := obsoleteSecrets(change.resourceType, name, before, after)
+	result := map[string]interface{}{
+		"success":  true,
+		"resource": ops.Kind,
+		"name":     name,
+		"changes":  diff(before, after),
+		"envs":     views(after),
+	}
+	if len(secrets) > 0 {
+		result["secrets"] = secrets
+	}
+
+	if equal(before, after) {
+		result["message"] = fmt.Sprintf("Environment of %s '%s' is already up to date", ops.Kind, name)
+		return tools.MarshalResult(result)
+	}
+
+	if tools.IsDryRun(ctx) {
+		ops.RuntimeOf(item).Envs = encodeEnvs(after)
+		requests := make([]tools.PlannedRequest, 0, len(secrets)+len(obsolete)+1)
+		for _, secret := range secrets {
+			requests = append(requests, tools.PlannedRequest{
+				Method: http.MethodPut,
+				Path:   "/secrets/" + secret,
+				Body:   map[string]string{"name": secret, "value": redact.Mask(change.secrets[secret])},
+			})
+		}
+		requests = append(requests, tools.PlannedRequest{Method: http.MethodPut, Path: ops.Path, Body: item})
+		for _, secret := range obsolete {
+			requests = append(requests, tools.PlannedRequest{Method: http.MethodDelete, Path: "/secrets/" + secret})
+		}
+		return tools.DryRun(fmt.Sprintf("Environment of %s '%s' would be changed and the %s redeployed: %s", ops.Kind, name, ops.Kind, strings.Join(diff(before, after), ", ")),
+			[]string{fmt.Sprintf("%s '%s' exists", ops.Kind, name), "secrets replaced or unset are deleted only if no other resource references them"},
+			requests...)
+	}
+
+	// Refuse to overwrite variables changed since they were read
+	latest, current, err := readEnv(ops, name)
+	if err != nil {
+		return nil, err
+	}
+	if !equal(before, current) {
+		return nil, fmt.Errorf("conflict: the environment of %s '%s' was changed concurrently (%s); read it again and retry",
+			ops.Kind, name, strings.Join(diff(before, current), ", "))
+	}
+
+	// Secrets shared with other resources are neither overwritten nor deleted
+	var references map[string][]string
+	if len(secrets) > 0 || len(obsolete) > 0 {
+		if references, err = h.references(ctx); err != nil {
+			if len(secrets) > 0 {
+				return nil, fmt.Errorf("failed to check which resources reference the secrets: %w", err)
+			}
+			logger.Printf("Warning: cannot check secret references, keeping %s: %v", strings.Join(obsolete, ", "), err)
+			obsolete = nil
+		}
+	}
+	self := change.resourceType + "/" + name
+	for _, secret := range secrets {
+		if users := others(references[secret], self); len(users) > 0 {
+			return nil, fmt.Errorf("secret '%s' is referenced by %s; refusing to overwrite it", secret, strings.Join(users, ", "))
+		}
+	}
+
+	// Secrets must exist before the resource referencing them is deployed
+	stored := []string{}
+	for _, secret := range secrets {
+		if err := h.secrets.Put(ctx, secret, change.secrets[secret]); err != nil {
+			h.deleteSecrets(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, secret)
+	}
+
+	ops.RuntimeOf(latest).Envs = encodeEnvs(after)
+	if err := ops.Write(h.cache, name, *latest); err != nil {
+		h.deleteSecrets(ctx, stored)
+		return nil, err
+	}
+
+	deleted := []string{}
+	for _, secret := range obsolete {
+		if users := others(references[secret], self); len(users) > 0 {
+			logger.Printf("Keeping secret '%s', still referenced by %s", secret, strings.Join(users, ", "))
+			continue
+		}
+		deleted = append(deleted, secret)
+	}
+	if deleted = h.deleteSecrets(ctx, deleted); len(deleted) > 0 {
+		result["deletedSecrets"] = deleted
+	}
+
+	// The update redeploys the resource with the new environment
+	waited, err := ops.Wait(ctx, name, change.waitForCompletion)
+	switch {
+	case err != nil:
+		logger.Printf("Warning: environment updated but status check failed: %v", err)
+		result["message"] = fmt.Sprintf("Environment of %s '%s' updated (status check failed: %v)", ops.Kind, name, err)
+	case waited:
+		result["message"] = fmt.Sprintf("Environment of %s '%s' updated and redeployed successfully", ops.Kind, name)
+	default:
+		result["message"] = fmt.Sprintf("Environment of %s '%s' updated successfully; the %s is redeploying", ops.Kind, name, ops.Kind)
+	}
+
+	return tools.MarshalResult(result)
+}
+
+// deleteSecrets deletes workspace secrets, logging failures, and returns the
+// secrets deleted
+func (h *SDKEnvHandler) deleteSecrets(ctx context.Context, secrets []string) []string {
+	deleted := []string{}
+	for _, secret := range secrets {
+		if err := h.secrets.Delete(ctx, secret); err != nil {
+			logger.Printf("Warning: %v", err)
+			continue
+		}
+		deleted = append(deleted, secret)
+	}
+	return deleted
+}
+
+// secretReferences lists the resources with an environment and returns the
+// resources referencing each workspace secret, as type/name. The lists bypass
+// the cache since a stale list could delete a secret still in use.
+func (h *SDKEnvHandler) secretReferences(ctx context.Context) (map[string][]string, error) {
+	lists := []struct {
+		resourceType string
+		list         func() (int, []byte, interface{}, error)
+	}{
+		{"agent", func() (int, []byte, interface{}, error) {
+			resp, err := h.sdkClient.ListAgentsWithResponse(ctx)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"function", func() (int, []byte, interface{}, error) {
+			resp, err := h.sdkClient.ListFunctionsWithResponse(ctx)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"job", func() (int, []byte, interface{}, error) {
+			resp, err := h.sdkClient.ListJobsWithResponse(ctx)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+		{"sandbox", func() (int, []byte, interface{}, error) {
+			resp, err := h.sdkClient.ListSandboxesWithResponse(ctx)
+			if err != nil {
+				return 0, nil, nil, err
+			}
+			return resp.StatusCode(), resp.Body, resp.JSON200, nil
+		}},
+	}
+
+	references := map[string][]string{}
+	for _, l := range lists {
+		status, body, typed, err := l.list()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s resources: %w", l.resourceType, err)
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("list %s resources failed with status %d", l.resourceType, status)
+		}
+
+		// Decode the raw body; fall back to the typed result when it is absent
+		if len(body) == 0 {
+			if body, err = json.Marshal(typed); err != nil {
+				return nil, fmt.Errorf("failed to encode %s list: %w", l.resourceType, err)
+			}
+		}
+		if err := addReferences(references, l.resourceType, body); err != nil {
+			return nil, err
+		}
+	}
+	return references, nil
+}
+
+// addReferences adds the secrets referenced by the resources of a list body
+func addReferences(references map[string][]string, resourceType string, body []byte) error {
+	var items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Runtime struct {
+				Envs []struct {
+					Value string `json:"value"`
+				} `json:"envs"`
+			} `json:"runtime"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return fmt.Errorf("failed to decode %s list: %w", resourceType, err)
+	}
+
+	for _, item := range items {
+		for _, env := range item.Spec.Runtime.Envs {
+			if secret, ok := client.ReferencedSecret(env.Value); ok {
+				id := resourceType + "/" + item.Metadata.Name
+				if !slices.Contains(references[secret], id) {
+					references[secret] = append(references[secret], id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// others returns the resources other than self
+func others(resources []string, self string) []string {
+	return slices.DeleteFunc(slices.Clone(resources), func(id string) bool { return id == self })
+}
+
+// decodeEnvs reads the runtime envs of the SDK, entries of name and value
+func decodeEnvs(entries []interface{}) []envVar {
+	envs := make([]envVar, 0, len(entries))
+	for _, entry := range entries {
+		var raw map[string]interface{}
+		switch e := entry.(type) {
+		case map[string]interface{}:
+			raw = e
+		case map[string]string:
+			raw = make(map[string]interface{}, len(e))
+			for key, value := range e {
+				raw[key] = value
+			}
+		default:
+			continue
+		}
+
+		name, _ := raw["name"].(string)
+		if name == "" {
+			continue
+		}
+		value, _ := raw["value"].(string)
+		envs = append(envs, envVar{Name: name, Value: value, raw: raw})
+	}
+	return envs
+}
+
+// encodeEnvs writes envs as the runtime envs of the SDK
+func encodeEnvs(envs []envVar) *[]interface{} {
+	entries := make([]interface{}, 0, len(envs))
+	for _, env := range envs {
+		entry := make(map[string]interface{}, len(env.raw)+2)
+		for key, value := range env.raw {
+			entry[key] = value
+		}
+		entry["name"] = env.Name
+		entry["value"] = env.Value
+		entries = append(entries, entry)
+	}
+	return &entries
+}
+
+// view redacts the value of a variable unless it only references a secret
+func view(env envVar) envView {
+	v := envView{Name: env.Name, Value: env.Value}
+	if secret, ok := client.ReferencedSecret(env.Value); ok {
+		v.SecretRef = secret
+		return v
+	}
+	if redact.IsSecretEnv(env.Name) || redact.IsCredential(env.Value) {
+		v.Value = redact.Mask(env.Value)
+		v.Redacted = v.Value != env.Value
+	}
+	return v
+}
+
+func views(envs []envVar) []envView {
+	result := make([]envView, 0, len(envs))
+	for _, env := range envs {
+		result = append(result, view(env))
+	}
+	return result
+}
+
+// isSecret reports whether a value set by set_env is stored as a workspace secret
+func isSecret(name, value string, update EnvUpdate) bool {
+	if slices.Contains(update.Plaintext, name) {
+		return false
+	}
+	return slices.Contains(update.Secrets, name) || redact.IsSecretEnv(name) || redact.IsCredential(value)
+}
+
+// secretNameSeparators are the characters of resource names not allowed in secret names
+var secretNameSeparators = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// secretOwner returns the prefix of the secrets holding a variable of a
+// resource, such as AGENT_MY_AGENT_OPENAI_API_KEY_1A2B3C4D. The readable part
+// is truncated to fit the 128 characters of secret names, and the hash of the
+// exact type, resource and variable keeps names that read the same apart
+// (my-agent and my_agent).
+func secretOwner(resourceType, resource, variable string) string {
+	readable := strings.ToUpper(secretNameSeparators.ReplaceAllString(resourceType+"_"+resource+"_"+variable, "_"))
+	if len(readable) > 110 {
+		readable = readable[:110]
+	}
+	sum := sha256.Sum256([]byte(resourceType + "/" + resource + "/" + variable))
+	return readable + "_" + strings.ToUpper(hex.EncodeToString(sum[:4]))
+}
+
+// secretName returns a new workspace secret for a variable of a resource: its
+// owner prefix followed by a random version, so that a value is never
+// overwritten while a deployment still references it
+func secretName(resourceType, resource, variable string) string {
+	version := make([]byte, 4)
+	_, _ = rand.Read(version)
+	return secretOwner(resourceType, resource, variable) + "_" + strings.ToUpper(hex.EncodeToString(version))
+}
+
+// ownsSecret reports whether secret is a version created for a variable of a resource
+func ownsSecret(resourceType, resource, variable, secret string) bool {
+	version, ok := strings.CutPrefix(secret, secretOwner(resourceType, resource, variable)+"_")
+	return ok && len(version) == 8
+}
+
+// obsoleteSecrets returns the secrets created for variables of the resource
+// that the variables referenced before the change and no longer do
+func obsoleteSecrets(resourceType, resource string, before, after []envVar) []string {
+	kept := map[string]bool{}
+	for _, env := range after {
+		if secret, ok := client.ReferencedSecret(env.Value); ok {
+			kept[secret] = true
+		}
+	}
+
+	obsolete := []string{}
+	for _, env := range before {
+		secret, ok := client.ReferencedSecret(env.Value)
+		if ok && !kept[secret] && ownsSecret(resourceType, resource, env.Name, secret) {
+			obsolete = append(obsolete, secret)
+		}
+	}
+	sort.Strings(obsolete)
+	return obsolete
+}
+
+// diff describes the changes from before to after, with redacted values
+func diff(before, after []envVar) []string {
+	previous := make(map[string]envVar, len(before))
+	for _, env := range before {
+		previous[env.Name] = env
+	}
+	next := make(map[string]bool, len(after))
+
+	changes := []string{}
+	for _, env := range after {
+		next[env.Name] = true
+		old, ok := previous[env.Name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("+%s=%s", env.Name, view(env).Value))
+		case old.Value != env.Value:
+			changes = append(changes, fmt.Sprintf("~%s=%s (was %s)", env.Name, view(env).Value, view(old).Value))
+		}
+	}
+	for _, env := range before {
+		if !next[env.Name] {
+			changes = append(changes, fmt.Sprintf("-%s", env.Name))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i][1:] < changes[j][1:] })
+	return changes
+}
+
+// equal reports whether two environments have the same variables and values
+func equal(a, b []envVar) bool {
+	return slices.EqualFunc(a, b, func(x, y envVar) bool {
+		return x.Name == y.Name && x.Value == y.Value
+	})
+}
@@ -0,0 +1,345 @@
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client/clienttest"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// fakeSecrets is a secrets API storing secrets in memory
+type fakeSecrets struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (f *fakeSecrets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/secrets/")
+	switch r.Method {
+	case http.MethodPut:
+		var secret struct{ Value string }
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &secret)
+		f.values[name] = secret.Value
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		if _, ok := f.values[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.values, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeSecrets) names() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := []string{}
+	for name := range f.values {
+		names = append(names, name)
+	}
+	return names
+}
+
+func newFakeAgent(envs map[string]string) *clienttest.Resource[sdk.Agent] {
+	deployed := "DEPLOYED"
+	return clienttest.New(sdk.Agent{Spec: &sdk.AgentSpec{Runtime: &sdk.Runtime{Envs: envEntries(envs)}}, Status: &deployed})
+}
+
+func envEntries(envs map[string]string) *[]interface{} {
+	entries := []interface{}{}
+	for _, name := range sortedKeys(envs) {
+		entries = append(entries, map[string]interface{}{"name": name, "value": envs[name]})
+	}
+	return &entries
+}
+
+func agentOps(agent *clienttest.Resource[sdk.Agent]) client.ResourceOps[sdk.Agent] {
+	return agent.Ops(client.AgentOps(context.Background(), nil, "my-agent"))
+}
+
+func envsOf(agent *clienttest.Resource[sdk.Agent]) map[string]string {
+	envs := map[string]string{}
+	for _, env := range decodeEnvs(*agent.Item.Spec.Runtime.Envs) {
+		envs[env.Name] = env.Value
+	}
+	return envs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newTestHandler(t *testing.T, references map[string][]string) (*SDKEnvHandler, *fakeSecrets) {
+	secrets := &fakeSecrets{values: map[string]string{}}
+	server := httptest.NewServer(secrets)
+	t.Cleanup(server.Close)
+
+	h := &SDKEnvHandler{
+		secrets: client.NewSecretsClient(&config.Config{APIEndpoint: server.URL, Workspace: "test", Credentials: sdk.Credentials{APIKey: "test-key"}}),
+	}
+	h.references = func(ctx context.Context) (map[string][]string, error) { return references, nil }
+	return h, secrets
+}
+
+func TestSecretName(t *testing.T) {
+	name := secretName("agent", "my-agent", "OPENAI_API_KEY")
+	if err := client.ValidateSecretName(name); err != nil {
+		t.Fatalf("invalid name: %v", err)
+	}
+	if !strings.HasPrefix(name, "AGENT_MY_AGENT_OPENAI_API_KEY_") {
+		t.Errorf("secretName() = %s", name)
+	}
+	if !ownsSecret("agent", "my-agent", "OPENAI_API_KEY", name) {
+		t.Error("the variable should own its secret")
+	}
+	if secretName("agent", "my-agent", "OPENAI_API_KEY") == name {
+		t.Error("each secret should be a new version")
+	}
+
+	// Names that read the same belong to different owners
+	for _, pair := range [][2][3]string{
+		{{"agent", "my-agent", "KEY"}, {"agent", "my_agent", "KEY"}},
+		{{"agent", "a+B", "KEY"}, {"agent", "a-b+", "KEY"}},
+		{{"agent", "a", "B_KEY"}, {"agent", "a_b", "KEY"}},
+		{{"agent", "a", "KEY"}, {"function", "a", "KEY"}},
+	} {
+		a, b := pair[0], pair[1]
+		if secretOwner(a[0], a[1], a[2]) == secretOwner(b[0], b[1], b[2]) {
+			t.Errorf("%v and %v share the owner %s", a, b, secretOwner(a[0], a[1], a[2]))
+		}
+		if ownsSecret(b[0], b[1], b[2], secretName(a[0], a[1], a[2])) {
+			t.Errorf("%v owns a secret of %v", b, a)
+		}
+	}
+
+	long := secretName("sandbox", strings.Repeat("x", 200), "KEY")
+	if err := client.ValidateSecretName(long); err != nil {
+		t.Errorf("long name: %v", err)
+	}
+	if ownsSecret("agent", "a", "KEY", "AGENT_A_KEY") {
+		t.Error("secrets not created by set_env are not owned")
+	}
+}
+
+func TestSetEnvStoresSecrets(t *testing.T) {
+	h, secrets := newTestHandler(t, nil)
+	agent := newFakeAgent(map[string]string{"LOG_LEVEL": "info"})
+
+	change, err := setChange("agent", "my-agent", EnvUpdate{Values: map[string]string{
+		"LOG_LEVEL":      "debug",
+		"OPENAI_API_KEY": "sk-proj-abcdefghijklmnopqrstuvwxyz",
+		"SHARED":         "${secrets.SHARED}",
+	}}, "")
+	if err != nil {
+		t.Fatalf("setChange() error = %v", err)
+	}
+
+	data, err := applyEnv(context.Background(), h, agentOps(agent), "my-agent", change)
+	if err != nil {
+		t.Fatalf("applyEnv() error = %v", err)
+	}
+	if strings.Contains(string(data), "sk-proj") {
+		t.Errorf("result leaks the secret: %s", data)
+	}
+	var result map[string]interface{}
+	_ = json.Unmarshal(data, &result)
+	if !strings.Contains(result["message"].(string), "updated and redeployed successfully") {
+		t.Errorf("message = %v", result["message"])
+	}
+
+	envs := envsOf(agent)
+	if envs["LOG_LEVEL"] != "debug" || envs["SHARED"] != "${secrets.SHARED}" {
+		t.Errorf("envs = %v", envs)
+	}
+	secret, ok := client.ReferencedSecret(envs["OPENAI_API_KEY"])
+	if !ok || !ownsSecret("agent", "my-agent", "OPENAI_API_KEY", secret) {
+		t.Fatalf("OPENAI_API_KEY = %q, want a reference to its own secret", envs["OPENAI_API_KEY"])
+	}
+	if names := secrets.names(); len(names) != 1 || secrets.values[secret] != "sk-proj-abcdefghijklmnopqrstuvwxyz" {
+		t.Errorf("stored secrets = %v", secrets.values)
+	}
+}
+
+func TestSetEnvReplacesOwnedSecrets(t *testing.T) {
+	old := secretName("agent", "my-agent", "API_KEY")
+	shared := secretName("agent", "my-agent", "TOKEN")
+	h, secrets := newTestHandler(t, map[string][]string{
+		old:    {"agent/my-agent"},
+		shared: {"agent/my-agent", "job/nightly"},
+	})
+	secrets.values[old] = "old"
+	secrets.values[shared] = "shared"
+	agent := newFakeAgent(map[string]string{"API_KEY": client.SecretReference(old), "TOKEN": client.SecretReference(shared)})
+
+	change, err := setChange("agent", "my-agent", EnvUpdate{Values: map[string]string{"API_KEY": "new", "TOKEN": "new"}, Secrets: []string{"API_KEY", "TOKEN"}}, "false")
+	if err != nil {
+		t.Fatalf("setChange() error = %v", err)
+	}
+	if _, err := applyEnv(context.Background(), h, agentOps(agent), "my-agent", change); err != nil {
+		t.Fatalf("applyEnv() error = %v", err)
+	}
+
+	if _, ok := secrets.values[old]; ok {
+		t.Error("the replaced secret should be deleted")
+	}
+	if _, ok := secrets.values[shared]; !ok {
+		t.Error("a secret referenced by another resource should be kept")
+	}
+	if len(secrets.values) != 3 {
+		t.Errorf("stored secrets = %v, want the shared one and two new ones", secrets.names())
+	}
+}
+
+func TestSetEnvFailedUpdateKeepsLiveSecrets(t *testing.T) {
+	old := secretName("agent", "my-agent", "API_KEY")
+	h, secrets := newTestHandler(t, map[string][]string{old: {"agent/my-agent"}})
+	secrets.values[old] = "old"
+	agent := newFakeAgent(map[string]string{"API_KEY": client.SecretReference(old)})
+	agent.UpdateStatus = http.StatusInternalServerError
+
+	change, _ := setChange("agent", "my-agent", EnvUpdate{Values: map[string]string{"API_KEY": "new"}}, "false")
+	if _, err := applyEnv(context.Background(), h, agentOps(agent), "my-agent", change); err == nil {
+		t.Fatal("applyEnv() should fail when the update fails")
+	}
+
+	if len(secrets.values) != 1 || secrets.values[old] != "old" {
+		t.Errorf("stored secrets = %v, want only the live secret, unchanged", secrets.values)
+	}
+	if envsOf(agent)["API_KEY"] != client.SecretReference(old) {
+		t.Errorf("envs = %v", envsOf(agent))
+	}
+}
+
+func TestSetEnvRefusesSharedSecret(t *testing.T) {
+	h, secrets := newTestHandler(t, map[string][]string{"SHARED": {"function/other"}})
+	agent := newFakeAgent(map[string]string{})
+
+	change := &envChange{
+		apply: func(current []envVar) []envVar {
+			return append(current, envVar{Name: "KEY", Value: client.SecretReference("SHARED")})
+		},
+		secrets:           map[string]string{"SHARED": "value"},
+		resourceType:      "agent",
+		waitForCompletion: "false",
+	}
+	_, err := applyEnv(context.Background(), h, agentOps(agent), "my-agent", change)
+	if err == nil || !strings.Contains(err.Error(), "referenced by function/other") {
+		t.Errorf("applyEnv() error = %v", err)
+	}
+	if len(secrets.values) != 0 || len(agent.Updates) != 0 {
+		t.Errorf("nothing should be written: secrets = %v, updates = %d", secrets.values, len(agent.Updates))
+	}
+}
+
+func TestUnsetEnvDeletesOwnedSecrets(t *testing.T) {
+	owned := secretName("agent", "my-agent", "API_KEY")
+	h, secrets := newTestHandler(t, map[string][]string{owned: {"agent/my-agent"}, "LEGACY": {"agent/my-agent"}})
+	secrets.values[owned] = "v"
+	secrets.values["LEGACY"] = "v"
+	agent := newFakeAgent(map[string]string{
+		"API_KEY":   client.SecretReference(owned),
+		"OTHER_KEY": client.SecretReference("LEGACY"),
+		"LOG_LEVEL": "info",
+	})
+
+	change := &envChange{
+		apply: func(current []envVar) []envVar {
+			return slices.DeleteFunc(slices.Clone(current), func(v envVar) bool { return v.Name != "LOG_LEVEL" })
+		},
+		resourceType:      "agent",
+		waitForCompletion: "false",
+	}
+	data, err := applyEnv(context.Background(), h, agentOps(agent), "my-agent", change)
+	if err != nil {
+		t.Fatalf("applyEnv() error = %v", err)
+	}
+	if !strings.Contains(string(data), owned) {
+		t.Errorf("result should list the deleted secret: %s", data)
+	}
+	if _, ok := secrets.values[owned]; ok {
+		t.Error("the secret created for the variable should be deleted")
+	}
+	if _, ok := secrets.values["LEGACY"]; !ok {
+		t.Error("a secret not created by set_env should be kept")
+	}
+}
+
+func TestApplyEnvKeepsOtherChanges(t *testing.T) {
+	h, _ := newTestHandler(t, nil)
+	agent := newFakeAgent(map[string]string{"LOG_LEVEL": "info"})
+	agent.OnRead = map[int]func(*sdk.Agent){2: func(agent *sdk.Agent) {
+		memory := 4096
+		agent.Spec.Runtime.Memory = &memory
+		labels := sdk.MetadataLabels{"team": "search"}
+		agent.Metadata = &sdk.Metadata{Labels: &labels}
+	}}
+
+	change, _ := setChange("agent", "my-agent", EnvUpdate{Values: map[string]string{"LOG_LEVEL": "debug"}}, "false")
+	if _, err := applyEnv(context.Background(), h, agentOps(agent), "my-agent", change); err != nil {
+		t.Fatalf("applyEnv() error = %v", err)
+	}
+	if envsOf(agent)["LOG_LEVEL"] != "debug" {
+		t.Errorf("envs = %v", envsOf(agent))
+	}
+	if memory := agent.Item.Spec.Runtime.Memory; memory == nil || *memory != 4096 {
+		t.Error("a scaling change made before the update should be kept")
+	}
+	if agent.Item.Metadata == nil || (*agent.Item.Metadata.Labels)["team"] != "search" {
+		t.Error("a label change made before the update should be kept")
+	}
+}
+
+func TestApplyEnvConflict(t *testing.T) {
+	h, secrets := newTestHandler(t, nil)
+	agent := newFakeAgent(map[string]string{"LOG_LEVEL": "info"})
+	agent.OnRead = map[int]func(*sdk.Agent){2: func(agent *sdk.Agent) {
+		agent.Spec.Runtime.Envs = envEntries(map[string]string{"LOG_LEVEL": "warn"})
+	}}
+
+	change, _ := setChange("agent", "my-agent", EnvUpdate{Values: map[string]string{"LOG_LEVEL": "debug", "API_KEY": "v"}}, "false")
+	_, err := applyEnv(context.Background(), h, agentOps(agent), "my-agent", change)
+	if err == nil || !strings.Contains(err.Error(), "conflict") {
+		t.Errorf("applyEnv() error = %v", err)
+	}
+	if len(secrets.values) != 0 || len(agent.Updates) != 0 {
+		t.Errorf("nothing should be written: secrets = %v, updates = %d", secrets.values, len(agent.Updates))
+	}
+}
+
+func TestAddReferences(t *testing.T) {
+	references := map[string][]string{}
+	body := []byte(`[
+		{"metadata": {"name": "a"}, "spec": {"runtime": {"envs": [{"name": "K", "value": "${secrets.S}"}, {"name": "L", "value": "plain"}]}}},
+		{"metadata": {"name": "b"}, "spec": {}}
+	]`)
+	if err := addReferences(references, "agent", body); err != nil {
+		t.Fatalf("addReferences() error = %v", err)
+	}
+	if err := addReferences(references, "job", []byte(`[{"metadata": {"name": "a"}, "spec": {"runtime": {"envs": [{"name": "K", "value": "${secrets.S}"}]}}}]`)); err != nil {
+		t.Fatalf("addReferences() error = %v", err)
+	}
+	if got := references["S"]; len(got) != 2 || got[0] != "agent/a" || got[1] != "job/a" {
+		t.Errorf("references = %v", references)
+	}
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// FormatArg is the per-call argument selecting the output format of list and get tools
+const FormatArg = "format"
+
+// SupportsFormat reports whether a tool's output can be rendered in another format
+func SupportsFormat(name string) bool {
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// withFormat adds the format argument to a tool's input schema
+func withFormat(tool mcp.Tool) mcp.Tool {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[FormatArg] = map[string]any{
+		"type":        "string",
+		"description": "Output format: text (default), json, yaml or markdown (table)",
+		"enum":        formatter.Formats,
+	}
+	return tool
+}
+
+// FormatMiddleware selects the output format of list and get tools, from the
+// format argument or the server default. Handlers render their models in that
+// format; JSON results they leave as is are converted here.
+func FormatMiddleware(defaultFormat formatter.Format) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !SupportsFormat(request.Params.Name) {
+				return next(ctx, request)
+			}
+
+			format := defaultFormat
+			if name := request.GetString(FormatArg, ""); name != "" {
+				parsed, err := formatter.ParseFormat(name)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				format = parsed
+			}
+
+			result, err := next(formatter.WithFormat(ctx, format), request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+
+			for i, content := range result.Content {
+				if text, ok := content.(mcp.TextContent); ok {
+					if converted, ok := formatter.Convert(format, text.Text); ok {
+						text.Text = converted
+						result.Content[i] = text
+					}
+				}
+			}
+
+			return result, nil
+		}
+	}
+}
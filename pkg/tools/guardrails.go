@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Target describes the resource a guarded tool acts on
+type Target struct {
+	Kind     string // human-readable kind used in policy errors
+	Resource string // client.Resource* name, used to look up labels; empty if the resource has none
+	Arg      string // argument holding the resource name
+}
+
+// guardedTools are the delete, update and run tools, with the resource they target
+var guardedTools = map[string]Target{
+	"delete_agent":               {"agent", client.ResourceAgents, "name"},
+	"update_agent":               {"agent", client.ResourceAgents, "name"},
+	"rollback_agent":             {"agent", client.ResourceAgents, "name"},
+	"run_agent":                  {"agent", client.ResourceAgents, "name"},
+	"evaluate_agent":             {"agent", client.ResourceAgents, "name"},
+	"delete_model_api":           {"model API", client.ResourceModels, "name"},
+	"run_model":                  {"model API", client.ResourceModels, "name"},
+	"delete_mcp_server":          {"MCP server", client.ResourceFunctions, "name"},
+	"delete_sandbox":             {"sandbox", client.ResourceSandboxes, "name"},
+	"run_sandbox":                {"sandbox", client.ResourceSandboxes, "name"},
+	"delete_job":                 {"job", client.ResourceJobs, "id"},
+	"run_job":                    {"job", client.ResourceJobs, "name"},
+	"delete_integration":         {"integration", client.ResourceIntegrations, "name"},
+	"delete_service_account":     {"service account", "", "name"},
+	"update_service_account":     {"service account", "", "name"},
+	"update_workspace_user_role": {"user", "", "name"},
+	"remove_workspace_user":      {"user", "", "name"},
+}
+
+// deployedResourceTargets maps the resourceType of local_run_deployed_resource
+// to the resource it runs
+var deployedResourceTargets = map[string]Target{
+	"agent":    {"agent", client.ResourceAgents, "resourceName"},
+	"model":    {"model API", client.ResourceModels, "resourceName"},
+	"job":      {"job", client.ResourceJobs, "resourceName"},
+	"function": {"MCP server", client.ResourceFunctions, "resourceName"},
+}
+
+// benchmarkTargets maps the resourceType of benchmark_resource to the
+// resource it sends requests to
+var benchmarkTargets = map[string]Target{
+	"agent":    {"agent", client.ResourceAgents, "name"},
+	"model":    {"model API", client.ResourceModels, "name"},
+	"function": {"MCP server", client.ResourceFunctions, "name"},
+}
+
+// envTargets maps the resourceType of set_env and unset_env to the resource
+// whose environment they change
+var envTargets = map[string]Target{
+	"agent":    {"agent", client.ResourceAgents, "name"},
+	"function": {"MCP server", client.ResourceFunctions, "name"},
+	"job":      {"job", client.ResourceJobs, "name"},
+	"sandbox":  {"sandbox", client.ResourceSandboxes, "name"},
+}
+
+// scaleTargets maps the resourceType of scale_resource to the resource it scales
+var scaleTargets = map[string]Target{
+	"agent":    {"agent", client.ResourceAgents, "name"},
+	"function": {"MCP server", client.ResourceFunctions, "name"},
+	"job":      {"job", client.ResourceJobs, "name"},
+}
+
+// labelTargets maps the resourceType of set_labels and remove_labels to the
+// resource whose labels they change, protection labels included
+var labelTargets = map[string]Target{
+	"agent":       {"agent", client.ResourceAgents, "name"},
+	"model":       {"model API", client.ResourceModels, "name"},
+	"function":    {"MCP server", client.ResourceFunctions, "name"},
+	"sandbox":     {"sandbox", client.ResourceSandboxes, "name"},
+	"job":         {"job", client.ResourceJobs, "name"},
+	"integration": {"integration", client.ResourceIntegrations, "name"},
+}
+
+// guardedPrefixes catch delete, update and run tools missing from guardedTools;
+// only their name argument is checked against the protected name patterns
+var guardedPrefixes = []string{"delete_", "update_", "remove_", "run_", "set_", "unset_", "rollback_", "scale_"}
+
+// LabelLookup returns the labels of a resource; found is false if it does not exist
+type LabelLookup func(ctx context.Context, resource, name string) (labels map[string]string, found bool, err error)
+
+// Guard rejects delete, update, run and label calls that target protected resources
+type Guard struct {
+	names  []string
+	labels map[string]string
+	lookup LabelLookup
+}
+
+// NewGuard creates a guard for the protected name patterns and labels.
+// lookup is only called when labels are protected.
+func NewGuard(protection config.Protection, lookup LabelLookup) (*Guard, error) {
+	for _, pattern := range protection.Names {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid protected name pattern %q: %w", pattern, err)
+		}
+	}
+
+	return &Guard{
+		names:  protection.Names,
+		labels: protection.Labels,
+		lookup: lookup,
+	}, nil
+}
+
+// target returns the resource a tool call acts on, if the tool is guarded
+func (g *Guard) target(name string, arguments map[string]interface{}) (Target, bool) {
+	if target, ok := guardedTools[name]; ok {
+		return target, true
+	}
+
+	if name == "local_deploy_directory" {
+		directory, _ := arguments["directory"].(string)
+		target, _ := DeployTarget(directory)
+		return target, true
+	}
+
+	if name == "local_run_deployed_resource" {
+		resourceType, _ := arguments["resourceType"].(string)
+		target, ok := deployedResourceTargets[resourceType]
+		return target, ok
+	}
+
+	if name == "benchmark_resource" {
+		resourceType, _ := arguments["resourceType"].(string)
+		target, ok := benchmarkTargets[resourceType]
+		return target, ok
+	}
+
+	if name == "set_env" || name == "unset_env" {
+		resourceType, _ := arguments["resourceType"].(string)
+		target, ok := envTargets[resourceType]
+		return target, ok
+	}
+
+	if name == "scale_resource" {
+		resourceType, _ := arguments["resourceType"].(string)
+		target, ok := scaleTargets[resourceType]
+		return target, ok
+	}
+
+	if name == "set_labels" || name == "remove_labels" {
+		resourceType, _ := arguments["resourceType"].(string)
+		target, ok := labelTargets[resourceType]
+		return target, ok
+	}
+
+	for _, prefix := range guardedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return Target{Kind: "resource", Arg: "name"}, true
+		}
+	}
+	return Target{}, false
+}
+
+// Check returns a policy error if a tool call targets a protected resource
+func (g *Guard) Check(ctx context.Context, tool string, arguments map[string]interface{}) error {
+	target, ok := g.target(tool, arguments)
+	if !ok {
+		return nil
+	}
+
+	name, _ := arguments[target.Arg].(string)
+	if tool == "local_deploy_directory" {
+		// The deployed name comes from blaxel.json, not from the arguments
+		_, name = DeployTarget(name)
+	}
+	if name == "" {
+		return nil
+	}
+
+	for _, pattern := range g.names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return fmt.Errorf("policy error: %s '%s' is protected (name matches '%s'); %s is not allowed on it", target.Kind, name, pattern, tool)
+		}
+	}
+
+	if len(g.labels) == 0 || target.Resource == "" || g.lookup == nil {
+		return nil
+	}
+
+	labels, found, err := g.lookup(ctx, target.Resource, name)
+	if err != nil {
+		return fmt.Errorf("policy error: could not check whether %s '%s' is protected: %w", target.Kind, name, err)
+	}
+	if !found {
+		// Nothing to protect; the handler reports the missing resource
+		return nil
+	}
+
+	keys := make([]string, 0, len(g.labels))
+	for key := range g.labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if value, ok := labels[key]; ok && value == g.labels[key] {
+			return fmt.Errorf("policy error: %s '%s' is protected (label %s=%s); %s is not allowed on it", target.Kind, name, key, value, tool)
+		}
+	}
+	return nil
+}
+
+// Middleware runs Check before every tool handler, so a toolset cannot bypass
+// the protection by forgetting to call it
+func (g *Guard) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := g.Check(ctx, request.Params.Name, request.GetArguments()); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return next(ctx, request)
+	}
+}
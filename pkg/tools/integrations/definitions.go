@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // IntegrationHandler defines the interface for integration operations
@@ -23,7 +24,7 @@ type IntegrationHandlerWithReadOnly interface {
 }
 
 // RegisterIntegrationTools registers integration tools with the given handler
-func RegisterIntegrationTools(s *server.MCPServer, handler IntegrationHandler) {
+func RegisterIntegrationTools(s tools.ToolRegistrar, handler IntegrationHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(IntegrationHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
@@ -34,9 +35,13 @@ func RegisterIntegrationTools(s *server.MCPServer, handler IntegrationHandler) {
 		mcp.WithString("filter",
 			mcp.Description("Optional filter string"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(listIntegrationsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		filter := request.GetString("filter", "")
 
 		result, err := handler.ListIntegrations(ctx, filter)
@@ -54,9 +59,13 @@ func RegisterIntegrationTools(s *server.MCPServer, handler IntegrationHandler) {
 			mcp.Required(),
 			mcp.Description("Name of the integration"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(getIntegrationTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		name := request.GetString("name", "")
 		if name == "" {
 			return mcp.NewToolResultError("integration name is required"), nil
@@ -10,6 +10,8 @@ import (
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/selector"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/toolkit/sdk"
 )
@@ -17,6 +19,7 @@ import (
 // SDKHandler implements IntegrationHandler using the SDK client
 type SDKHandler struct {
 	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
 	readOnly  bool
 }
 
@@ -29,6 +32,7 @@ func NewSDKHandler(cfg *config.Config) (IntegrationHandler, error) {
 
 	return &SDKHandler{
 		sdkClient: sdkClient,
+		cache:     client.SharedCache(cfg),
 		readOnly:  cfg.ReadOnly,
 	}, nil
 }
@@ -39,7 +43,9 @@ func (h *SDKHandler) ListIntegrations(ctx context.Context, filter string) ([]byt
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.ListIntegrationConnectionsWithResponse(ctx)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceIntegrations, "list", "", func() (*sdk.ListIntegrationConnectionsResponse, error) {
+		return h.sdkClient.ListIntegrationConnectionsWithResponse(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list integrations: %w", err)
 	}
@@ -65,14 +71,33 @@ func (h *SDKHandler) ListIntegrations(ctx context.Context, filter string) ([]byt
 		integrations = filtered
 	}
 
+	// Keep the integrations matching the label selector
+	integrations = selector.Apply(ctx, integrations, func(integration sdk.IntegrationConnection) (*sdk.Metadata, *string) {
+		return integration.Metadata, nil
+	})
+
+	// Sort by and project on SDK fields when the caller asked to
+	integrations, shaped, done, err := formatter.Shape(ctx, integrations)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return shaped, nil
+	}
+
 	// Convert SDK integrations to simple models
 	integrationModels := make([]formatter.IntegrationModel, len(integrations))
 	for i, integration := range integrations {
 		integrationModels[i] = convertToIntegrationModel(integration)
+		// Lists only ever show key names and fingerprints, whatever the format
+		integrationModels[i].Secrets = redact.MaskMap(integrationModels[i].Secrets)
 	}
 
-	// Format the integrations using the formatter
-	formatted := formatter.FormatIntegrations(integrationModels)
+	// Format the models in the requested output format
+	formatted, err := formatter.Render(formatter.FormatFromContext(ctx), integrationModels, formatter.IntegrationColumns, formatter.FormatIntegrations)
+	if err != nil {
+		return nil, err
+	}
 	return []byte(formatted), nil
 }
 
@@ -82,7 +107,9 @@ func (h *SDKHandler) GetIntegration(ctx context.Context, name string) ([]byte, e
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	integration, err := h.sdkClient.GetIntegrationConnectionWithResponse(ctx, name)
+	integration, err := client.Fetch(ctx, h.cache, client.ResourceIntegrations, "get", name, func() (*sdk.GetIntegrationConnectionResponse, error) {
+		return h.sdkClient.GetIntegrationConnectionWithResponse(ctx, name)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get integration: %w", err)
 	}
@@ -126,6 +153,21 @@ func (h *SDKHandler) CreateIntegration(ctx context.Context, name, integrationTyp
 		integrationData.Spec.Config = &config
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckAvailable("integration", name, func() (int, error) {
+			resp, err := h.sdkClient.GetIntegrationConnectionWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Integration '%s' would be created", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodPost, Path: "/integrations/connections", Body: integrationData})
+	}
+
 	integration, err := h.sdkClient.CreateIntegrationConnectionWithResponse(ctx, integrationData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create integration: %w", err)
@@ -138,6 +180,8 @@ func (h *SDKHandler) CreateIntegration(ctx context.Context, name, integrationTyp
 		return nil, fmt.Errorf("failed to create integration with status %d", integration.StatusCode())
 	}
 
+	h.cache.Invalidate(client.ResourceIntegrations, name)
+
 	result := map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Integration '%s' created successfully", name),
@@ -161,11 +205,28 @@ func (h *SDKHandler) DeleteIntegration(ctx context.Context, name string) ([]byte
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckExists("integration", name, func() (int, error) {
+			resp, err := h.sdkClient.GetIntegrationConnectionWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Integration '%s' would be deleted", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/integrations/connections/" + name})
+	}
+
 	_, err := h.sdkClient.DeleteIntegrationConnectionWithResponse(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete integration: %w", err)
 	}
 
+	h.cache.Invalidate(client.ResourceIntegrations, name)
+
 	result := map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Integration '%s' deleted successfully", name),
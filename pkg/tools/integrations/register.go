@@ -4,11 +4,11 @@ import (
 	"fmt"
 
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
-	"github.com/mark3labs/mcp-go/server"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 )
 
 // RegisterTools registers all integration-related tools using SDK client
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
+func RegisterTools(s tools.ToolRegistrar, cfg *config.Config) {
 	// Create SDK-based handler
 	handler, err := NewSDKHandler(cfg)
 	if err != nil {
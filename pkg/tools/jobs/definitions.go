@@ -3,13 +3,14 @@ package jobs
 import (
 	"context"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // JobHandler defines the interface for job operations
 type JobHandler interface {
-	ListJobs(ctx context.Context, status string) ([]byte, error)
+	ListJobs(ctx context.Context, filter string) ([]byte, error)
 	GetJob(ctx context.Context, id string) ([]byte, error)
 	DeleteJob(ctx context.Context, id string) ([]byte, error)
 }
@@ -21,7 +22,7 @@ type JobHandlerWithReadOnly interface {
 }
 
 // RegisterJobTools registers job tools with the given handler
-func RegisterJobTools(s *server.MCPServer, handler JobHandler) {
+func RegisterJobTools(s tools.ToolRegistrar, handler JobHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(JobHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
@@ -29,15 +30,19 @@ func RegisterJobTools(s *server.MCPServer, handler JobHandler) {
 	// List jobs tool
 	listJobsTool := mcp.NewTool("list_jobs",
 		mcp.WithDescription("List all jobs in the workspace"),
-		mcp.WithString("status",
-			mcp.Description("Optional filter by job status"),
+		mcp.WithString("filter",
+			mcp.Description("Optional filter string to match job names"),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
 		),
 	)
 
 	s.AddTool(listJobsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		status := request.GetString("status", "")
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
+		filter := request.GetString("filter", "")
 
-		result, err := handler.ListJobs(ctx, status)
+		result, err := handler.ListJobs(ctx, filter)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -52,9 +57,13 @@ func RegisterJobTools(s *server.MCPServer, handler JobHandler) {
 			mcp.Required(),
 			mcp.Description("ID of the job to retrieve"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(getJobTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		id := request.GetString("id", "")
 		if id == "" {
 			return mcp.NewToolResultError("job ID is required"), nil
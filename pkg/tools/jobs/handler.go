@@ -5,18 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/selector"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/toolkit/sdk"
 )
 
 // SDKHandler implements JobHandler using the SDK client
 type SDKHandler struct {
 	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
 	readOnly  bool
 }
 
@@ -29,17 +31,20 @@ func NewSDKHandler(cfg *config.Config) (JobHandler, error) {
 
 	return &SDKHandler{
 		sdkClient: sdkClient,
+		cache:     client.SharedCache(cfg),
 		readOnly:  cfg.ReadOnly,
 	}, nil
 }
 
 // ListJobs implements JobHandler.ListJobs
-func (h *SDKHandler) ListJobs(ctx context.Context, status string) ([]byte, error) {
+func (h *SDKHandler) ListJobs(ctx context.Context, filter string) ([]byte, error) {
 	if h.sdkClient == nil {
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.ListJobsWithResponse(ctx)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceJobs, "list", "", func() (*sdk.ListJobsResponse, error) {
+		return h.sdkClient.ListJobsWithResponse(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list jobs: %w", err)
 	}
@@ -53,25 +58,43 @@ func (h *SDKHandler) ListJobs(ctx context.Context, status string) ([]byte, error
 		jobs = *resp.JSON200
 	}
 
-	// Apply filter (on status in this case)
-	if status != "" {
+	// Apply optional filter
+	if filter != "" {
 		var filtered []sdk.Job
 		for _, job := range jobs {
-			if job.Status != nil && strings.EqualFold(*job.Status, status) {
+			if job.Metadata != nil && job.Metadata.Name != nil &&
+				tools.ContainsString(*job.Metadata.Name, filter) {
 				filtered = append(filtered, job)
 			}
 		}
 		jobs = filtered
 	}
 
+	// Keep the jobs matching the label selector and status filters
+	jobs = selector.Apply(ctx, jobs, func(job sdk.Job) (*sdk.Metadata, *string) {
+		return job.Metadata, job.Status
+	})
+
+	// Sort by and project on SDK fields when the caller asked to
+	jobs, shaped, done, err := formatter.Shape(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return shaped, nil
+	}
+
 	// Convert SDK jobs to simple models
 	jobModels := make([]formatter.JobModel, len(jobs))
 	for i, job := range jobs {
 		jobModels[i] = convertToJobModel(job)
 	}
 
-	// Format the jobs using the formatter
-	formatted := formatter.FormatJobs(jobModels)
+	// Format the models in the requested output format
+	formatted, err := formatter.Render(formatter.FormatFromContext(ctx), jobModels, formatter.JobColumns, formatter.FormatJobs)
+	if err != nil {
+		return nil, err
+	}
 	return []byte(formatted), nil
 }
 
@@ -81,7 +104,9 @@ func (h *SDKHandler) GetJob(ctx context.Context, id string) ([]byte, error) {
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.GetJobWithResponse(ctx, id)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceJobs, "get", id, func() (*sdk.GetJobResponse, error) {
+		return h.sdkClient.GetJobWithResponse(ctx, id)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
@@ -109,6 +134,21 @@ func (h *SDKHandler) DeleteJob(ctx context.Context, id string) ([]byte, error) {
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckExists("job", id, func() (int, error) {
+			resp, err := h.sdkClient.GetJobWithResponse(ctx, id)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Job '%s' would be deleted", id), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/jobs/" + id})
+	}
+
 	resp, err := h.sdkClient.DeleteJobWithResponse(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete job: %w", err)
@@ -118,6 +158,8 @@ func (h *SDKHandler) DeleteJob(ctx context.Context, id string) ([]byte, error) {
 		return nil, fmt.Errorf("delete job failed with status %d", resp.StatusCode())
 	}
 
+	h.cache.Invalidate(client.ResourceJobs, id)
+
 	result := map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Job '%s' deleted successfully", id),
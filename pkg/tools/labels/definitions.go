@@ -0,0 +1,140 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceTypes are the resource types whose labels can be changed
+var ResourceTypes = []string{"agent", "model", "function", "sandbox", "job", "integration"}
+
+// LabelHandler defines the interface for label operations
+type LabelHandler interface {
+	SetLabels(ctx context.Context, resourceType, name string, labels map[string]string) ([]byte, error)
+	RemoveLabels(ctx context.Context, resourceType, name string, keys []string) ([]byte, error)
+}
+
+// LabelHandlerWithReadOnly extends LabelHandler with readonly capability
+type LabelHandlerWithReadOnly interface {
+	LabelHandler
+	IsReadOnly() bool
+}
+
+// RegisterLabelTools registers label tools with the given handler
+func RegisterLabelTools(s tools.ToolRegistrar, handler LabelHandler) {
+	// Check if handler supports readonly mode
+	readOnlyHandler, hasReadOnly := handler.(LabelHandlerWithReadOnly)
+	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
+
+	// Both tools modify the workspace
+	if isReadOnly {
+		return
+	}
+
+	// Set labels tool
+	setLabelsTool := mcp.NewTool("set_labels",
+		mcp.WithDescription("Add or change labels on a resource. Other labels are kept. Concurrent changes are detected on a best-effort basis, since the API has no conditional update: a change made after the labels are read fails without changing anything, except in the short window right before the write, and a change landing after the write is reported as a warning with the current labels."),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithObject("labels",
+			mcp.Required(),
+			mcp.Description("Labels to set, as key/value pairs (e.g. {\"env\": \"prod\"})"),
+		),
+	)
+
+	s.AddTool(setLabelsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		type SetLabelsArgs struct {
+			ResourceType string                 `json:"resourceType"`
+			Name         string                 `json:"name"`
+			Labels       map[string]interface{} `json:"labels"`
+		}
+
+		var args SetLabelsArgs
+		if err := request.BindArguments(&args); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid arguments: %v", err)), nil
+		}
+
+		if !slices.Contains(ResourceTypes, args.ResourceType) {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown resource type '%s' (expected one of %s)", args.ResourceType, strings.Join(ResourceTypes, ", "))), nil
+		}
+		if args.Name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+		if len(args.Labels) == 0 {
+			return mcp.NewToolResultError("at least one label is required"), nil
+		}
+
+		// Label values are strings; numbers and booleans are written as text
+		labels := make(map[string]string, len(args.Labels))
+		for key, value := range args.Labels {
+			switch v := value.(type) {
+			case string:
+				labels[key] = v
+			case float64, bool:
+				labels[key] = fmt.Sprint(v)
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("label '%s' must be a string", key)), nil
+			}
+		}
+
+		result, err := handler.SetLabels(ctx, args.ResourceType, args.Name, labels)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Remove labels tool
+	removeLabelsTool := mcp.NewTool("remove_labels",
+		mcp.WithDescription("Remove labels from a resource. Keys that are not set are ignored. Concurrent changes are detected on a best-effort basis, since the API has no conditional update: a change made after the labels are read fails without changing anything, except in the short window right before the write, and a change landing after the write is reported as a warning with the current labels."),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithArray("keys",
+			mcp.Required(),
+			mcp.Description("Keys of the labels to remove"),
+			mcp.WithStringItems(),
+		),
+	)
+
+	s.AddTool(removeLabelsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType := request.GetString("resourceType", "")
+		if !slices.Contains(ResourceTypes, resourceType) {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown resource type '%s' (expected one of %s)", resourceType, strings.Join(ResourceTypes, ", "))), nil
+		}
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+		keys := request.GetStringSlice("keys", nil)
+		if len(keys) == 0 {
+			return mcp.NewToolResultError("at least one label key is required"), nil
+		}
+
+		result, err := handler.RemoveLabels(ctx, resourceType, name, keys)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
@@ -0,0 +1,195 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// SDKLabelHandler implements LabelHandler using the SDK client
+type SDKLabelHandler struct {
+	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
+	readOnly  bool
+}
+
+// NewSDKLabelHandler creates a new SDK-based label handler
+func NewSDKLabelHandler(sdkClient *sdk.ClientWithResponses, cache *client.ResponseCache, readOnly bool) LabelHandler {
+	return &SDKLabelHandler{
+		sdkClient: sdkClient,
+		cache:     cache,
+		readOnly:  readOnly,
+	}
+}
+
+// SetLabels implements LabelHandler.SetLabels
+func (h *SDKLabelHandler) SetLabels(ctx context.Context, resourceType, name string, labels map[string]string) ([]byte, error) {
+	for key := range labels {
+		if key == "" || strings.ContainsAny(key, " \t=!(),") {
+			return nil, fmt.Errorf("invalid label key %q", key)
+		}
+	}
+
+	return h.modify(ctx, resourceType, name, func(current map[string]string) {
+		maps.Copy(current, labels)
+	})
+}
+
+// RemoveLabels implements LabelHandler.RemoveLabels
+func (h *SDKLabelHandler) RemoveLabels(ctx context.Context, resourceType, name string, keys []string) ([]byte, error) {
+	return h.modify(ctx, resourceType, name, func(current map[string]string) {
+		for _, key := range keys {
+			delete(current, key)
+		}
+	})
+}
+
+// IsReadOnly implements LabelHandlerWithReadOnly.IsReadOnly
+func (h *SDKLabelHandler) IsReadOnly() bool {
+	return h.readOnly
+}
+
+// modify applies change to the labels of a resource with the update call of
+// its type
+func (h *SDKLabelHandler) modify(ctx context.Context, resourceType, name string, change func(map[string]string)) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	switch resourceType {
+	case "agent":
+		return modifyLabels(ctx, h.cache, client.AgentOps(ctx, h.sdkClient, name), name, change)
+	case "model":
+		return modifyLabels(ctx, h.cache, client.ModelOps(ctx, h.sdkClient, name), name, change)
+	case "function":
+		return modifyLabels(ctx, h.cache, client.FunctionOps(ctx, h.sdkClient, name), name, change)
+	case "sandbox":
+		return modifyLabels(ctx, h.cache, client.SandboxOps(ctx, h.sdkClient, name), name, change)
+	case "job":
+		return modifyLabels(ctx, h.cache, client.JobOps(ctx, h.sdkClient, name), name, change)
+	case "integration":
+		return modifyLabels(ctx, h.cache, client.IntegrationOps(ctx, h.sdkClient, name), name, change)
+	}
+
+	return nil, fmt.Errorf("unknown resource type '%s' (expected one of %s)", resourceType, strings.Join(ResourceTypes, ", "))
+}
+
+// readLabels fetches the resource, bypassing the cache, and returns it with its labels
+func readLabels[T any](ops client.ResourceOps[T], name string) (*T, map[string]string, error) {
+	item, err := ops.Read(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labels := map[string]string{}
+	if metadata := *ops.Metadata(item); metadata != nil && metadata.Labels != nil {
+		labels = maps.Clone(map[string]string(*metadata.Labels))
+	}
+	return item, labels, nil
+}
+
+// modifyLabels is a read-modify-write of the labels of a resource. The API has
+// no conditional update, so detection of concurrent changes is best-effort:
+// the resource is read again right before the update, which refuses labels
+// changed since the first read, and the new labels are written onto that
+// second read so that other changes to the resource are kept. A change landing
+// in the window between the second read and the update is overwritten; one
+// landing after the update is reported as a warning with the current labels,
+// since the update itself succeeded.
+func modifyLabels[T any](ctx context.Context, cache *client.ResponseCache, ops client.ResourceOps[T], name string, change func(map[string]string)) ([]byte, error) {
+	item, before, err := readLabels(ops, name)
+	if err != nil {
+		return nil, err
+	}
+
+	after := maps.Clone(before)
+	change(after)
+
+	result := map[string]interface{}{
+		"success":  true,
+		"resource": ops.Kind,
+		"name":     name,
+		"before":   before,
+		"labels":   after,
+		"changes":  diff(before, after),
+	}
+
+	if maps.Equal(before, after) {
+		result["message"] = fmt.Sprintf("Labels of %s '%s' are already up to date", ops.Kind, name)
+		return tools.MarshalResult(result)
+	}
+
+	if tools.IsDryRun(ctx) {
+		setLabels(ops, item, after)
+		return tools.DryRun(fmt.Sprintf("Labels of %s '%s' would be changed: %s", ops.Kind, name, strings.Join(diff(before, after), ", ")),
+			[]string{fmt.Sprintf("%s '%s' exists", ops.Kind, name)},
+			tools.PlannedRequest{Method: http.MethodPut, Path: ops.Path, Body: item})
+	}
+
+	// Refuse to overwrite labels changed since they were read
+	latest, current, err := readLabels(ops, name)
+	if err != nil {
+		return nil, err
+	}
+	if !maps.Equal(before, current) {
+		return nil, fmt.Errorf("conflict: the labels of %s '%s' were changed concurrently (%s); read them again and retry",
+			ops.Kind, name, strings.Join(diff(before, current), ", "))
+	}
+
+	setLabels(ops, latest, after)
+	if err := ops.Write(cache, name, *latest); err != nil {
+		return nil, err
+	}
+
+	// A concurrent update may still have landed after ours
+	_, current, err = readLabels(ops, name)
+	if err != nil {
+		return nil, err
+	}
+	if !maps.Equal(after, current) {
+		result["warning"] = fmt.Sprintf("the labels were changed concurrently after the update (%s); current holds the labels now set",
+			strings.Join(diff(after, current), ", "))
+		result["current"] = current
+	}
+
+	result["message"] = fmt.Sprintf("Labels of %s '%s' updated successfully", ops.Kind, name)
+	return tools.MarshalResult(result)
+}
+
+// setLabels replaces the labels of a resource
+func setLabels[T any](ops client.ResourceOps[T], item *T, labels map[string]string) {
+	metadata := ops.Metadata(item)
+	if *metadata == nil {
+		*metadata = &sdk.Metadata{}
+	}
+	l := sdk.MetadataLabels(labels)
+	(*metadata).Labels = &l
+}
+
+// diff describes the changes from before to after, sorted by key
+func diff(before, after map[string]string) []string {
+	changes := []string{}
+	for key, value := range after {
+		old, ok := before[key]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("+%s=%s", key, value))
+		case old != value:
+			changes = append(changes, fmt.Sprintf("~%s=%s (was %s)", key, value, old))
+		}
+	}
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			changes = append(changes, fmt.Sprintf("-%s=%s", key, value))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i][1:] < changes[j][1:] })
+	return changes
+}
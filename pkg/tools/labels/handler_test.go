@@ -0,0 +1,144 @@
+package labels
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client/clienttest"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func newSandbox(labels map[string]string) *clienttest.Resource[sdk.Sandbox] {
+	l := sdk.MetadataLabels(labels)
+	return clienttest.New(sdk.Sandbox{Metadata: &sdk.Metadata{Labels: &l}})
+}
+
+func sandboxOps(sandbox *clienttest.Resource[sdk.Sandbox]) client.ResourceOps[sdk.Sandbox] {
+	return sandbox.Ops(client.SandboxOps(context.Background(), nil, "box"))
+}
+
+func labelsOf(sandbox *clienttest.Resource[sdk.Sandbox]) map[string]string {
+	return map[string]string(*sandbox.Item.Metadata.Labels)
+}
+
+// relabel replaces the labels of a sandbox, as another client would
+func relabel(labels map[string]string) func(*sdk.Sandbox) {
+	return func(sandbox *sdk.Sandbox) {
+		l := sdk.MetadataLabels(labels)
+		sandbox.Metadata.Labels = &l
+	}
+}
+
+func set(labels map[string]string) func(map[string]string) {
+	return func(current map[string]string) { maps.Copy(current, labels) }
+}
+
+func decode(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("invalid result: %v", err)
+	}
+	return result
+}
+
+func TestModifyLabels(t *testing.T) {
+	sandbox := newSandbox(map[string]string{"env": "dev", "team": "a"})
+
+	data, err := modifyLabels(context.Background(), nil, sandboxOps(sandbox), "box", func(current map[string]string) {
+		current["env"] = "prod"
+		current["tier"] = "1"
+		delete(current, "team")
+	})
+	if err != nil {
+		t.Fatalf("modifyLabels() error = %v", err)
+	}
+
+	result := decode(t, data)
+	changes, _ := json.Marshal(result["changes"])
+	if string(changes) != `["~env=prod (was dev)","-team=a","+tier=1"]` {
+		t.Errorf("changes = %s", changes)
+	}
+	if !maps.Equal(labelsOf(sandbox), map[string]string{"env": "prod", "tier": "1"}) || len(sandbox.Updates) != 1 {
+		t.Errorf("labels = %v after %d updates", labelsOf(sandbox), len(sandbox.Updates))
+	}
+	if _, ok := result["warning"]; ok {
+		t.Errorf("unexpected warning: %v", result["warning"])
+	}
+}
+
+func TestModifyLabelsUpToDate(t *testing.T) {
+	sandbox := newSandbox(map[string]string{"env": "prod"})
+
+	data, err := modifyLabels(context.Background(), nil, sandboxOps(sandbox), "box", set(map[string]string{"env": "prod"}))
+	if err != nil {
+		t.Fatalf("modifyLabels() error = %v", err)
+	}
+	if len(sandbox.Updates) != 0 || !strings.Contains(decode(t, data)["message"].(string), "already up to date") {
+		t.Errorf("an up-to-date resource should not be updated: %s", data)
+	}
+}
+
+func TestModifyLabelsConflictBeforeWrite(t *testing.T) {
+	sandbox := newSandbox(map[string]string{"env": "dev"})
+	sandbox.OnRead = map[int]func(*sdk.Sandbox){2: relabel(map[string]string{"env": "staging"})}
+
+	_, err := modifyLabels(context.Background(), nil, sandboxOps(sandbox), "box", set(map[string]string{"env": "prod"}))
+	if err == nil || !strings.Contains(err.Error(), "conflict") || !strings.Contains(err.Error(), "~env=staging (was dev)") {
+		t.Errorf("modifyLabels() error = %v", err)
+	}
+	if len(sandbox.Updates) != 0 {
+		t.Error("a conflict before the write should not update the resource")
+	}
+}
+
+func TestModifyLabelsKeepsOtherChanges(t *testing.T) {
+	sandbox := newSandbox(map[string]string{"env": "dev"})
+	sandbox.OnRead = map[int]func(*sdk.Sandbox){2: func(sandbox *sdk.Sandbox) {
+		memory := 4096
+		sandbox.Spec = &sdk.SandboxSpec{Runtime: &sdk.Runtime{Memory: &memory}}
+	}}
+
+	if _, err := modifyLabels(context.Background(), nil, sandboxOps(sandbox), "box", set(map[string]string{"env": "prod"})); err != nil {
+		t.Fatalf("modifyLabels() error = %v", err)
+	}
+	if labelsOf(sandbox)["env"] != "prod" {
+		t.Errorf("labels = %v", labelsOf(sandbox))
+	}
+	if spec := sandbox.Item.Spec; spec == nil || spec.Runtime == nil || spec.Runtime.Memory == nil || *spec.Runtime.Memory != 4096 {
+		t.Error("a change made to the spec before the update should be kept")
+	}
+}
+
+func TestModifyLabelsChangedAfterWrite(t *testing.T) {
+	sandbox := newSandbox(map[string]string{"env": "dev"})
+	sandbox.OnRead = map[int]func(*sdk.Sandbox){3: relabel(map[string]string{"env": "prod", "owner": "other"})}
+
+	data, err := modifyLabels(context.Background(), nil, sandboxOps(sandbox), "box", set(map[string]string{"env": "prod"}))
+	if err != nil {
+		t.Fatalf("a change after the write should not fail the call: %v", err)
+	}
+
+	result := decode(t, data)
+	if result["success"] != true || len(sandbox.Updates) != 1 {
+		t.Errorf("result = %v, updates = %d", result, len(sandbox.Updates))
+	}
+	if warning, _ := result["warning"].(string); !strings.Contains(warning, "+owner=other") {
+		t.Errorf("warning = %q", warning)
+	}
+	if current, _ := result["current"].(map[string]interface{}); current["owner"] != "other" {
+		t.Errorf("current = %v", result["current"])
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := diff(map[string]string{"a": "1", "b": "2"}, map[string]string{"b": "3", "c": "4"})
+	want := []string{"-a=1", "~b=3 (was 2)", "+c=4"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("diff() = %v, want %v", got, want)
+	}
+}
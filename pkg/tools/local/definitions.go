@@ -3,8 +3,8 @@ package local
 import (
 	"context"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // LocalHandler defines the interface for local operations
@@ -26,7 +26,7 @@ type LocalHandlerWithReadOnly interface {
 }
 
 // RegisterLocalTools registers local tools with the given handler
-func RegisterLocalTools(s *server.MCPServer, handler LocalHandler) {
+func RegisterLocalTools(s tools.ToolRegistrar, handler LocalHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(LocalHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/toolkit/sdk"
 )
 
@@ -16,6 +17,7 @@ import (
 type SDKHandler struct {
 	sdkClient *sdk.ClientWithResponses
 	cfg       *config.Config
+	cache     *client.ResponseCache
 	readOnly  bool
 }
 
@@ -29,6 +31,7 @@ func NewSDKHandler(cfg *config.Config) (LocalHandler, error) {
 	return &SDKHandler{
 		sdkClient: sdkClient,
 		cfg:       cfg,
+		cache:     client.SharedCache(cfg),
 		readOnly:  cfg.ReadOnly,
 	}, nil
 }
@@ -322,6 +325,9 @@ func (h *SDKHandler) DeployDirectory(directory string) (string, error) {
 		}
 	}
 
+	// Read the target before changing directory, which relative paths depend on
+	target, name := tools.DeployTarget(directory)
+
 	// Change to the directory
 	if err := os.Chdir(directory); err != nil {
 		return "", fmt.Errorf("failed to change to directory %s: %w", directory, err)
@@ -341,6 +347,9 @@ func (h *SDKHandler) DeployDirectory(directory string) (string, error) {
 		return "", fmt.Errorf("failed to deploy directory: %w", err)
 	}
 
+	// The deployment created or updated a resource behind the cache's back
+	h.cache.Invalidate(target.Resource, name)
+
 	return fmt.Sprintf("Directory deployed successfully: %s", directory), nil
 }
 
@@ -0,0 +1,115 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceTypes are the resource types whose logs can be read
+var ResourceTypes = []string{"agent", "function", "job", "sandbox"}
+
+// Severities are the log severities, from the least to the most severe
+var Severities = []string{"DEBUG", "INFO", "WARNING", "ERROR", "FATAL"}
+
+// Limits on the lines returned, so that logs fit in the client's context
+const (
+	defaultLimit         = 100
+	maxLimit             = 500
+	defaultFollowSeconds = 30
+	maxFollowSeconds     = 120
+)
+
+// LogOptions filter the logs of a resource
+type LogOptions struct {
+	Since         string // duration before now (e.g. 15m) or RFC3339 time
+	Until         string // RFC3339 time, empty for now
+	Severity      string // minimum severity
+	Search        string // case-insensitive text the message must contain
+	Limit         int    // maximum number of lines, the most recent are kept
+	Follow        bool
+	FollowSeconds int
+}
+
+// LogHandler defines the interface for log operations
+type LogHandler interface {
+	GetResourceLogs(ctx context.Context, resourceType, name string, options LogOptions) ([]byte, error)
+}
+
+// RegisterLogTools registers log tools with the given handler
+func RegisterLogTools(s tools.ToolRegistrar, handler LogHandler) {
+	// Get resource logs tool
+	getResourceLogsTool := mcp.NewTool("get_resource_logs",
+		mcp.WithDescription(fmt.Sprintf("Get the logs of an agent, MCP server (function), job or sandbox, oldest first. At most %d lines are returned, the most recent ones. With follow, new lines are streamed as progress notifications until followSeconds have passed.", maxLimit)),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Start of the time window, as a duration before now (e.g. 15m, 2h) or an RFC3339 time (default: 1h)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("End of the time window, as an RFC3339 time (default: now)"),
+		),
+		mcp.WithString("severity",
+			mcp.Description("Only lines at this severity or above"),
+			mcp.Enum(Severities...),
+		),
+		mcp.WithString("search",
+			mcp.Description("Only lines containing this text (case-insensitive)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of lines, the most recent are kept (default: %d, max: %d)", defaultLimit, maxLimit)),
+		),
+		mcp.WithBoolean("follow",
+			mcp.Description("Keep reading new lines and stream them as progress notifications"),
+		),
+		mcp.WithNumber("followSeconds",
+			mcp.Description(fmt.Sprintf("How long to follow the logs (default: %d, max: %d)", defaultFollowSeconds, maxFollowSeconds)),
+		),
+	)
+
+	s.AddTool(getResourceLogsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType := request.GetString("resourceType", "")
+		if resourceType == "" {
+			return mcp.NewToolResultError("resourceType is required"), nil
+		}
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+
+		options := LogOptions{
+			Since:         request.GetString("since", "1h"),
+			Until:         request.GetString("until", ""),
+			Severity:      request.GetString("severity", ""),
+			Search:        request.GetString("search", ""),
+			Limit:         request.GetInt("limit", defaultLimit),
+			Follow:        request.GetBool("follow", false),
+			FollowSeconds: request.GetInt("followSeconds", defaultFollowSeconds),
+		}
+		if options.Limit <= 0 || options.Limit > maxLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxLimit)), nil
+		}
+		if options.Follow && (options.FollowSeconds <= 0 || options.FollowSeconds > maxFollowSeconds) {
+			return mcp.NewToolResultError(fmt.Sprintf("followSeconds must be between 1 and %d", maxFollowSeconds)), nil
+		}
+		if options.Follow && options.Until != "" {
+			return mcp.NewToolResultError("until cannot be used with follow"), nil
+		}
+
+		result, err := handler.GetResourceLogs(tools.WithProgress(ctx, request), resourceType, name, options)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
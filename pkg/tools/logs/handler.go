@@ -0,0 +1,250 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/truncate"
+)
+
+// maxMessageChars caps the length of a single log line
+const maxMessageChars = 2000
+
+// pollInterval is how often new lines are read in follow mode
+var pollInterval = 2 * time.Second
+
+// severityAliases maps the other spellings of severities to Severities
+var severityAliases = map[string]string{
+	"TRACE":    "DEBUG",
+	"WARN":     "WARNING",
+	"ERR":      "ERROR",
+	"CRITICAL": "FATAL",
+	"PANIC":    "FATAL",
+}
+
+// logLine is a log line as returned by get_resource_logs
+type logLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Severity  string    `json:"severity,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// APILogHandler implements LogHandler using the observability API
+type APILogHandler struct {
+	logs *client.LogsClient
+}
+
+// NewAPILogHandler creates a new log handler
+func NewAPILogHandler(logs *client.LogsClient) LogHandler {
+	return &APILogHandler{logs: logs}
+}
+
+// GetResourceLogs implements LogHandler.GetResourceLogs
+func (h *APILogHandler) GetResourceLogs(ctx context.Context, resourceType, name string, options LogOptions) ([]byte, error) {
+	if !slices.Contains(ResourceTypes, resourceType) {
+		return nil, fmt.Errorf("unknown resource type '%s' (expected one of %s)", resourceType, strings.Join(ResourceTypes, ", "))
+	}
+
+	minSeverity := 0
+	if options.Severity != "" {
+		rank := severityRank(options.Severity)
+		if rank == 0 {
+			return nil, fmt.Errorf("unknown severity '%s' (expected one of %s)", options.Severity, strings.Join(Severities, ", "))
+		}
+		minSeverity = rank
+	}
+
+	now := time.Now()
+	start, err := parseSince(options.Since, now)
+	if err != nil {
+		return nil, err
+	}
+	var end time.Time
+	if options.Until != "" {
+		end, err = time.Parse(time.RFC3339, options.Until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid until time '%s': expected RFC3339, e.g. 2024-01-02T15:04:05Z", options.Until)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("until must be after since")
+		}
+	}
+
+	query := client.LogQuery{ResourceType: resourceType, Name: name, Start: start, End: end}
+	entries, err := h.logs.Fetch(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	search := strings.ToLower(options.Search)
+	keep := func(entry client.LogEntry) bool {
+		if minSeverity > 0 && severityRank(entry.Severity) < minSeverity {
+			return false
+		}
+		return search == "" || strings.Contains(strings.ToLower(entry.Message), search)
+	}
+
+	lines := &tail{limit: options.Limit}
+	for _, entry := range entries {
+		if keep(entry) {
+			lines.add(entry)
+		}
+	}
+
+	if options.Follow {
+		h.follow(ctx, query, entries, time.Duration(options.FollowSeconds)*time.Second, keep, lines)
+	}
+
+	result := map[string]interface{}{
+		"resourceType": resourceType,
+		"name":         name,
+		"since":        start.UTC().Format(time.RFC3339),
+		"lines":        lines.lines,
+		"count":        len(lines.lines),
+	}
+	if !end.IsZero() {
+		result["until"] = end.UTC().Format(time.RFC3339)
+	}
+	if options.Follow {
+		result["followedSeconds"] = options.FollowSeconds
+	}
+	if lines.omitted > 0 {
+		result["omitted"] = lines.omitted
+		result["message"] = fmt.Sprintf("%d older lines omitted; narrow the time window or filters to see them", lines.omitted)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// follow reads new lines until duration has passed or the call is cancelled,
+// streaming the ones kept as progress notifications
+func (h *APILogHandler) follow(ctx context.Context, query client.LogQuery, seen []client.LogEntry, duration time.Duration, keep func(client.LogEntry) bool, lines *tail) {
+	read := newCursor(query.Start)
+	for _, entry := range seen {
+		read.mark(entry)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		query.Start = read.last
+		entries, err := h.logs.Fetch(ctx, query)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warnf("Failed to follow logs of %s '%s': %v", query.ResourceType, query.Name, err)
+			}
+			continue
+		}
+
+		var fresh []string
+		for _, entry := range entries {
+			if !read.mark(entry) {
+				continue
+			}
+			if keep(entry) {
+				line := lines.add(entry)
+				fresh = append(fresh, fmt.Sprintf("%s %s %s", line.Timestamp.UTC().Format(time.RFC3339), line.Severity, line.Message))
+			}
+		}
+
+		if len(fresh) > 0 {
+			tools.ReportProgress(ctx, strings.Join(fresh, "\n"))
+		}
+	}
+}
+
+// cursor tracks the lines read in follow mode. Each read starts at the last
+// timestamp seen, so the lines at that timestamp are remembered to skip them.
+type cursor struct {
+	last   time.Time
+	atLast map[string]bool
+}
+
+func newCursor(start time.Time) *cursor {
+	return &cursor{last: start, atLast: map[string]bool{}}
+}
+
+// mark records entry as read, moving last forward if it is newer. It reports
+// false if the entry was read before.
+func (c *cursor) mark(entry client.LogEntry) bool {
+	if entry.Timestamp.Before(c.last) || (entry.Timestamp.Equal(c.last) && c.atLast[entry.Message]) {
+		return false
+	}
+	if entry.Timestamp.After(c.last) {
+		c.last = entry.Timestamp
+		clear(c.atLast)
+	}
+	c.atLast[entry.Message] = true
+	return true
+}
+
+// tail keeps the most recent lines up to a limit
+type tail struct {
+	limit   int
+	lines   []logLine
+	omitted int
+}
+
+func (t *tail) add(entry client.LogEntry) logLine {
+	line := logLine{
+		Timestamp: entry.Timestamp,
+		Severity:  entry.Severity,
+		Message:   truncate.Elide(entry.Message, maxMessageChars),
+	}
+	t.lines = append(t.lines, line)
+	if len(t.lines) > t.limit {
+		t.lines = t.lines[1:]
+		t.omitted++
+	}
+	return line
+}
+
+// severityRank returns the position of a severity in Severities, counting
+// from 1, or 0 if unknown. Lines without a severity rank as INFO.
+func severityRank(severity string) int {
+	severity = strings.ToUpper(severity)
+	if severity == "" {
+		severity = "INFO"
+	}
+	if alias, ok := severityAliases[severity]; ok {
+		severity = alias
+	}
+	return slices.Index(Severities, severity) + 1
+}
+
+// parseSince parses a duration before now or an RFC3339 time
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(since); err == nil {
+		if duration <= 0 {
+			return time.Time{}, fmt.Errorf("since must be a positive duration")
+		}
+		return now.Add(-duration), nil
+	}
+	start, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since '%s': expected a duration (e.g. 15m) or an RFC3339 time", since)
+	}
+	return start, nil
+}
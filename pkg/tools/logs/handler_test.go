@@ -0,0 +1,93 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func TestCursorMark(t *testing.T) {
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	read := newCursor(start)
+	steps := []struct {
+		entry client.LogEntry
+		fresh bool
+	}{
+		{client.LogEntry{Timestamp: at(1), Message: "a"}, true},
+		{client.LogEntry{Timestamp: at(1), Message: "b"}, true},
+		{client.LogEntry{Timestamp: at(1), Message: "a"}, false}, // read again at the last timestamp
+		{client.LogEntry{Timestamp: at(0), Message: "old"}, false},
+		{client.LogEntry{Timestamp: at(2), Message: "a"}, true},
+		{client.LogEntry{Timestamp: at(1), Message: "b"}, false}, // before the last timestamp
+		{client.LogEntry{Timestamp: at(2), Message: "c"}, true},
+	}
+
+	for i, step := range steps {
+		if got := read.mark(step.entry); got != step.fresh {
+			t.Errorf("step %d: mark(%+v) = %v, want %v", i, step.entry, got, step.fresh)
+		}
+	}
+	if !read.last.Equal(at(2)) {
+		t.Errorf("last = %v, want %v", read.last, at(2))
+	}
+}
+
+func TestFollowSkipsLinesAlreadyRead(t *testing.T) {
+	start := time.Now().UTC().Truncate(time.Second)
+	at := func(seconds int) string {
+		return start.Add(time.Duration(seconds) * time.Second).Format(time.RFC3339Nano)
+	}
+
+	// Every read answers the lines from startTime on, so lines at the last
+	// timestamp come back in the next read
+	all := []map[string]string{
+		{"timestamp": at(1), "severity": "INFO", "message": "one"},
+		{"timestamp": at(2), "severity": "INFO", "message": "two"},
+		{"timestamp": at(2), "severity": "DEBUG", "message": "three"},
+	}
+	var reads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since, _ := time.Parse(time.RFC3339Nano, r.URL.Query().Get("startTime"))
+		available := all[:min(int(reads.Add(1)), len(all))]
+		entries := []map[string]string{}
+		for _, entry := range available {
+			timestamp, _ := time.Parse(time.RFC3339Nano, entry["timestamp"])
+			if !timestamp.Before(since) {
+				entries = append(entries, entry)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(entries)
+	}))
+	defer server.Close()
+
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = 10 * time.Millisecond
+
+	h := &APILogHandler{logs: client.NewLogsClient(&config.Config{APIEndpoint: server.URL, Credentials: sdk.Credentials{APIKey: "test-key"}})}
+	query := client.LogQuery{ResourceType: "agent", Name: "a", Start: start}
+	lines := &tail{limit: 10}
+	keep := func(entry client.LogEntry) bool { return entry.Severity != "DEBUG" }
+
+	h.follow(context.Background(), query, nil, 200*time.Millisecond, keep, lines)
+
+	if reads.Load() < 4 {
+		t.Fatalf("expected several reads, got %d", reads.Load())
+	}
+	var messages []string
+	for _, line := range lines.lines {
+		messages = append(messages, line.Message)
+	}
+	if len(messages) != 2 || messages[0] != "one" || messages[1] != "two" {
+		t.Errorf("lines = %v, want [one two] once each", messages)
+	}
+}
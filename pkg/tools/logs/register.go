@@ -0,0 +1,16 @@
+package logs
+
+import (
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+)
+
+// RegisterTools registers all log-related tools
+func RegisterTools(s tools.ToolRegistrar, cfg *config.Config) {
+	// Logs are served by the observability API, outside the SDK
+	handler := NewAPILogHandler(client.NewLogsClient(cfg))
+
+	// Register tools using shared definitions
+	RegisterLogTools(s, handler)
+}
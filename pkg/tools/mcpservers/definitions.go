@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // MCPServerHandler defines the interface for MCP server operations
@@ -23,7 +24,7 @@ type MCPServerHandlerWithReadOnly interface {
 }
 
 // RegisterMCPServerTools registers MCP server tools with the given handler
-func RegisterMCPServerTools(s *server.MCPServer, handler MCPServerHandler) {
+func RegisterMCPServerTools(s tools.ToolRegistrar, handler MCPServerHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(MCPServerHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
@@ -34,9 +35,13 @@ func RegisterMCPServerTools(s *server.MCPServer, handler MCPServerHandler) {
 		mcp.WithString("filter",
 			mcp.Description("Optional filter string"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(listMCPServersTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		filter := request.GetString("filter", "")
 
 		result, err := handler.ListMCPServers(ctx, filter)
@@ -54,9 +59,13 @@ func RegisterMCPServerTools(s *server.MCPServer, handler MCPServerHandler) {
 			mcp.Required(),
 			mcp.Description("Name of the MCP server"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(getMCPServerTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		name := request.GetString("name", "")
 		if name == "" {
 			return mcp.NewToolResultError("MCP server name is required"), nil
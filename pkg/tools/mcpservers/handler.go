@@ -11,6 +11,7 @@ import (
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/selector"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/utils"
 	"github.com/blaxel-ai/toolkit/sdk"
@@ -19,6 +20,7 @@ import (
 // SDKHandler implements MCPServerHandler using the SDK client
 type SDKHandler struct {
 	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
 	readOnly  bool
 }
 
@@ -31,6 +33,7 @@ func NewSDKHandler(cfg *config.Config) (MCPServerHandler, error) {
 
 	return &SDKHandler{
 		sdkClient: sdkClient,
+		cache:     client.SharedCache(cfg),
 		readOnly:  cfg.ReadOnly,
 	}, nil
 }
@@ -41,7 +44,9 @@ func (h *SDKHandler) ListMCPServers(ctx context.Context, filter string) ([]byte,
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.ListFunctionsWithResponse(ctx)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceFunctions, "list", "", func() (*sdk.ListFunctionsResponse, error) {
+		return h.sdkClient.ListFunctionsWithResponse(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list MCP servers: %w", err)
 	}
@@ -67,14 +72,31 @@ func (h *SDKHandler) ListMCPServers(ctx context.Context, filter string) ([]byte,
 		functions = filtered
 	}
 
+	// Keep the MCP servers matching the label selector and status filters
+	functions = selector.Apply(ctx, functions, func(fn sdk.Function) (*sdk.Metadata, *string) {
+		return fn.Metadata, fn.Status
+	})
+
+	// Sort by and project on SDK fields when the caller asked to
+	functions, shaped, done, err := formatter.Shape(ctx, functions)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return shaped, nil
+	}
+
 	// Convert SDK functions to simple models
 	functionModels := make([]formatter.FunctionModel, len(functions))
 	for i, function := range functions {
 		functionModels[i] = convertToFunctionModel(function)
 	}
 
-	// Format the functions using the formatter
-	formatted := formatter.FormatFunctions(functionModels)
+	// Format the models in the requested output format
+	formatted, err := formatter.Render(formatter.FormatFromContext(ctx), functionModels, formatter.FunctionColumns, formatter.FormatFunctions)
+	if err != nil {
+		return nil, err
+	}
 	return []byte(formatted), nil
 }
 
@@ -84,7 +106,9 @@ func (h *SDKHandler) GetMCPServer(ctx context.Context, name string) ([]byte, err
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	server, err := h.sdkClient.GetFunctionWithResponse(ctx, name)
+	server, err := client.Fetch(ctx, h.cache, client.ResourceFunctions, "get", name, func() (*sdk.GetFunctionResponse, error) {
+		return h.sdkClient.GetFunctionWithResponse(ctx, name)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get MCP server: %w", err)
 	}
@@ -133,6 +157,10 @@ func (h *SDKHandler) CreateMCPServer(ctx context.Context, name, integrationConne
 		},
 	}
 
+	// Checks and requests collected for a dry run
+	var checks []string
+	var planned []tools.PlannedRequest
+
 	// Handle integration configuration
 	var integrationName string
 	if hasExisting {
@@ -141,6 +169,20 @@ func (h *SDKHandler) CreateMCPServer(ctx context.Context, name, integrationConne
 			return nil, fmt.Errorf("integrationConnectionName cannot be empty")
 		}
 		integrationName = integrationConnectionName
+
+		if tools.IsDryRun(ctx) {
+			check, err := tools.CheckExists("integration", integrationName, func() (int, error) {
+				resp, err := h.sdkClient.GetIntegrationConnectionWithResponse(ctx, integrationName)
+				if err != nil {
+					return 0, err
+				}
+				return resp.StatusCode(), nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			checks = append(checks, check)
+		}
 	} else if hasNewType {
 		// Create inline integration for the MCP server
 		if integrationType == "" {
@@ -170,18 +212,34 @@ func (h *SDKHandler) CreateMCPServer(ctx context.Context, name, integrationConne
 			integrationData.Spec.Config = &config
 		}
 
-		// Create the integration
-		integrationResp, err := h.sdkClient.CreateIntegrationConnectionWithResponse(ctx, integrationData)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create inline integration: %w", err)
-		}
-
-		if integrationResp.StatusCode() >= 400 {
-			if integrationResp.StatusCode() == 409 {
-				// Integration might already exist, try to use it
-				logger.Printf("Integration '%s' already exists, will attempt to use it", integrationName)
+		if tools.IsDryRun(ctx) {
+			// An existing integration is reused, as after a 409 below
+			integrationResp, err := h.sdkClient.GetIntegrationConnectionWithResponse(ctx, integrationName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get integration connection: %w", err)
+			}
+			if integrationResp.StatusCode() == http.StatusOK {
+				checks = append(checks, fmt.Sprintf("integration '%s' already exists and would be reused", integrationName))
 			} else {
-				return nil, fmt.Errorf("failed to create integration with status %d", integrationResp.StatusCode())
+				checks = append(checks, fmt.Sprintf("integration '%s' would be created", integrationName))
+				planned = append(planned, tools.PlannedRequest{Method: http.MethodPost, Path: "/integrations/connections", Body: integrationData})
+			}
+		} else {
+			// Create the integration
+			integrationResp, err := h.sdkClient.CreateIntegrationConnectionWithResponse(ctx, integrationData)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create inline integration: %w", err)
+			}
+
+			h.cache.Invalidate(client.ResourceIntegrations, integrationName)
+
+			if integrationResp.StatusCode() >= 400 {
+				if integrationResp.StatusCode() == 409 {
+					// Integration might already exist, try to use it
+					logger.Printf("Integration '%s' already exists, will attempt to use it", integrationName)
+				} else {
+					return nil, fmt.Errorf("failed to create integration with status %d", integrationResp.StatusCode())
+				}
 			}
 		}
 	}
@@ -192,6 +250,22 @@ func (h *SDKHandler) CreateMCPServer(ctx context.Context, name, integrationConne
 		functionData.Spec.IntegrationConnections = &connections
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckAvailable("MCP server", name, func() (int, error) {
+			resp, err := h.sdkClient.GetFunctionWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+		planned = append(planned, tools.PlannedRequest{Method: http.MethodPost, Path: "/functions", Body: functionData})
+		return tools.DryRun(fmt.Sprintf("MCP server '%s' would be created", name), checks, planned...)
+	}
+
 	// Create the MCP server
 	function, err := h.sdkClient.CreateFunctionWithResponse(ctx, functionData)
 	if err != nil {
@@ -205,6 +279,8 @@ func (h *SDKHandler) CreateMCPServer(ctx context.Context, name, integrationConne
 		return nil, fmt.Errorf("failed to create MCP server with status %d", function.StatusCode())
 	}
 
+	h.cache.Invalidate(client.ResourceFunctions, name)
+
 	// Check if we should wait for completion
 	waitForCompletionBool := true // default to true
 	if waitForCompletion != "" {
@@ -285,12 +361,29 @@ func (h *SDKHandler) DeleteMCPServer(ctx context.Context, name, waitForCompletio
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckExists("MCP server", name, func() (int, error) {
+			resp, err := h.sdkClient.GetFunctionWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("MCP server '%s' would be deleted", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/functions/" + name})
+	}
+
 	// Delete the MCP server
 	_, err := h.sdkClient.DeleteFunctionWithResponse(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete MCP server: %w", err)
 	}
 
+	h.cache.Invalidate(client.ResourceFunctions, name)
+
 	// Check if we should wait for completion
 	waitForCompletionBool := true // default to true
 	if waitForCompletion != "" {
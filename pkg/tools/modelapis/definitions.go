@@ -3,8 +3,9 @@ package modelapis
 import (
 	"context"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // ModelAPIHandler defines the interface for model API operations
@@ -22,7 +23,7 @@ type ModelAPIHandlerWithReadOnly interface {
 }
 
 // RegisterModelAPITools registers model API tools with the given handler
-func RegisterModelAPITools(s *server.MCPServer, handler ModelAPIHandler) {
+func RegisterModelAPITools(s tools.ToolRegistrar, handler ModelAPIHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(ModelAPIHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
@@ -33,9 +34,13 @@ func RegisterModelAPITools(s *server.MCPServer, handler ModelAPIHandler) {
 		mcp.WithString("filter",
 			mcp.Description("Optional filter string"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(listModelAPIsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		filter := request.GetString("filter", "")
 
 		result, err := handler.ListModelAPIs(ctx, filter)
@@ -53,9 +58,13 @@ func RegisterModelAPITools(s *server.MCPServer, handler ModelAPIHandler) {
 			mcp.Required(),
 			mcp.Description("Name of the model API"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(getModelAPITool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		name := request.GetString("name", "")
 		if name == "" {
 			return mcp.NewToolResultError("model API name is required"), nil
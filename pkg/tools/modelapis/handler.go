@@ -11,6 +11,7 @@ import (
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/selector"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/utils"
 	"github.com/blaxel-ai/toolkit/sdk"
@@ -19,6 +20,7 @@ import (
 // SDKHandler implements ModelAPIHandler using the SDK client
 type SDKHandler struct {
 	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
 	readOnly  bool
 }
 
@@ -31,6 +33,7 @@ func NewSDKHandler(cfg *config.Config) (ModelAPIHandler, error) {
 
 	return &SDKHandler{
 		sdkClient: sdkClient,
+		cache:     client.SharedCache(cfg),
 		readOnly:  cfg.ReadOnly,
 	}, nil
 }
@@ -41,7 +44,9 @@ func (h *SDKHandler) ListModelAPIs(ctx context.Context, filter string) ([]byte,
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.ListModelsWithResponse(ctx)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceModels, "list", "", func() (*sdk.ListModelsResponse, error) {
+		return h.sdkClient.ListModelsWithResponse(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list model APIs: %w", err)
 	}
@@ -67,14 +72,31 @@ func (h *SDKHandler) ListModelAPIs(ctx context.Context, filter string) ([]byte,
 		models = filtered
 	}
 
+	// Keep the model APIs matching the label selector and status filters
+	models = selector.Apply(ctx, models, func(model sdk.Model) (*sdk.Metadata, *string) {
+		return model.Metadata, model.Status
+	})
+
+	// Sort by and project on SDK fields when the caller asked to
+	models, shaped, done, err := formatter.Shape(ctx, models)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return shaped, nil
+	}
+
 	// Convert SDK models to simple models
 	modelModels := make([]formatter.ModelAPI, len(models))
 	for i, model := range models {
 		modelModels[i] = convertToModelAPIModel(model)
 	}
 
-	// Format the models using the formatter
-	formatted := formatter.FormatModels(modelModels)
+	// Format the models in the requested output format
+	formatted, err := formatter.Render(formatter.FormatFromContext(ctx), modelModels, formatter.ModelColumns, formatter.FormatModels)
+	if err != nil {
+		return nil, err
+	}
 	return []byte(formatted), nil
 }
 
@@ -84,7 +106,9 @@ func (h *SDKHandler) GetModelAPI(ctx context.Context, name string) ([]byte, erro
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	model, err := h.sdkClient.GetModelWithResponse(ctx, name)
+	model, err := client.Fetch(ctx, h.cache, client.ResourceModels, "get", name, func() (*sdk.GetModelResponse, error) {
+		return h.sdkClient.GetModelWithResponse(ctx, name)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get model API: %w", err)
 	}
@@ -125,6 +149,10 @@ func (h *SDKHandler) CreateModelAPI(ctx context.Context, name, model, endpoint,
 		},
 	}
 
+	// Checks and requests collected for a dry run
+	var checks []string
+	var planned []tools.PlannedRequest
+
 	// Handle integration configuration
 	var integrationName string
 	if hasExisting {
@@ -157,18 +185,34 @@ func (h *SDKHandler) CreateModelAPI(ctx context.Context, name, model, endpoint,
 		}
 		integrationData.Spec.Secret = &secrets
 
-		// Create the integration
-		integrationResp, err := h.sdkClient.CreateIntegrationConnectionWithResponse(ctx, integrationData)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create inline integration: %w", err)
-		}
-
-		if integrationResp.StatusCode() >= 400 {
-			if integrationResp.StatusCode() == 409 {
-				// Integration might already exist, try to use it
-				logger.Printf("Integration '%s' already exists, will attempt to use it", integrationName)
+		if tools.IsDryRun(ctx) {
+			// An existing integration is reused, as after a 409 below
+			integrationResp, err := h.sdkClient.GetIntegrationConnectionWithResponse(ctx, integrationName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get integration connection: %w", err)
+			}
+			if integrationResp.StatusCode() == http.StatusOK {
+				checks = append(checks, fmt.Sprintf("integration '%s' already exists and would be reused", integrationName))
 			} else {
-				return nil, fmt.Errorf("failed to create integration with status %d", integrationResp.StatusCode())
+				checks = append(checks, fmt.Sprintf("integration '%s' would be created", integrationName))
+				planned = append(planned, tools.PlannedRequest{Method: http.MethodPost, Path: "/integrations/connections", Body: integrationData})
+			}
+		} else {
+			// Create the integration
+			integrationResp, err := h.sdkClient.CreateIntegrationConnectionWithResponse(ctx, integrationData)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create inline integration: %w", err)
+			}
+
+			h.cache.Invalidate(client.ResourceIntegrations, integrationName)
+
+			if integrationResp.StatusCode() >= 400 {
+				if integrationResp.StatusCode() == 409 {
+					// Integration might already exist, try to use it
+					logger.Printf("Integration '%s' already exists, will attempt to use it", integrationName)
+				} else {
+					return nil, fmt.Errorf("failed to create integration with status %d", integrationResp.StatusCode())
+				}
 			}
 		}
 	} else {
@@ -191,7 +235,24 @@ func (h *SDKHandler) CreateModelAPI(ctx context.Context, name, model, endpoint,
 				return nil, fmt.Errorf("no integration connection found")
 			}
 			modelData.Spec.Runtime.Type = response.JSON200.Spec.Integration
+			checks = append(checks, fmt.Sprintf("integration '%s' exists", integrationName))
+		}
+	}
+
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckAvailable("model API", name, func() (int, error) {
+			resp, err := h.sdkClient.GetModelWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
 		}
+		checks = append(checks, check)
+		planned = append(planned, tools.PlannedRequest{Method: http.MethodPost, Path: "/models", Body: modelData})
+		return tools.DryRun(fmt.Sprintf("Model API '%s' would be created", name), checks, planned...)
 	}
 
 	// Create the model API
@@ -207,6 +268,8 @@ func (h *SDKHandler) CreateModelAPI(ctx context.Context, name, model, endpoint,
 		return nil, fmt.Errorf("failed to create model API with status %d", modelResp.StatusCode())
 	}
 
+	h.cache.Invalidate(client.ResourceModels, name)
+
 	// Check if we should wait for completion
 	waitForCompletionBool := true // default to true
 	if waitForCompletion != "" {
@@ -303,12 +366,29 @@ func (h *SDKHandler) DeleteModelAPI(ctx context.Context, name, waitForCompletion
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckExists("model API", name, func() (int, error) {
+			resp, err := h.sdkClient.GetModelWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Model API '%s' would be deleted", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/models/" + name})
+	}
+
 	// Delete the model API
 	_, err := h.sdkClient.DeleteModelWithResponse(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete model API: %w", err)
 	}
 
+	h.cache.Invalidate(client.ResourceModels, name)
+
 	// Check if we should wait for completion
 	waitForCompletionBool := true // default to true
 	if waitForCompletion != "" {
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Access classifies a tool as reading or modifying state
+type Access string
+
+const (
+	// AccessRead tools only inspect the workspace or the server
+	AccessRead Access = "read"
+	// AccessWrite tools create, modify, delete or run something
+	AccessWrite Access = "write"
+)
+
+// readPrefixes are the tool name prefixes classified as read
+var readPrefixes = []string{"list_", "get_"}
+
+// readTools are read tools whose name does not follow the list_/get_ convention
+var readTools = map[string]bool{
+	"login_status":            true,
+	"local_list_templates":    true,
+	"local_quick_start_guide": true,
+	"enable_toolset":          true,
+	"read_output":             true,
+	"compare_evaluations":     true,
+}
+
+// Policy classifies every tool as read or write and enforces read-only mode.
+// Tools are write unless they are known to be read, so a new tool that forgets
+// to declare itself can never slip through read-only mode.
+type Policy struct {
+	mu            sync.RWMutex
+	readOnly      bool
+	revealSecrets bool
+	access        map[string]Access
+}
+
+// NewPolicy creates a policy; with readOnly set, write tools are rejected.
+// allowRevealSecrets lets callers turn redaction off outside read-only mode.
+func NewPolicy(readOnly, allowRevealSecrets bool) *Policy {
+	return &Policy{
+		readOnly:      readOnly,
+		revealSecrets: allowRevealSecrets && !readOnly,
+		access:        make(map[string]Access),
+	}
+}
+
+// ReadOnly reports whether write tools are rejected
+func (p *Policy) ReadOnly() bool {
+	return p.readOnly
+}
+
+// RevealSecrets reports whether callers may turn redaction off
+func (p *Policy) RevealSecrets() bool {
+	return p.revealSecrets
+}
+
+// Classify returns the access of a tool and remembers it for Middleware.
+// A tool is read if it sets the read-only hint annotation or follows the
+// list_/get_ naming convention.
+func (p *Policy) Classify(tool mcp.Tool) Access {
+	access := AccessWrite
+	if tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint {
+		access = AccessRead
+	} else if readTools[tool.Name] {
+		access = AccessRead
+	} else {
+		for _, prefix := range readPrefixes {
+			if strings.HasPrefix(tool.Name, prefix) {
+				access = AccessRead
+				break
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.access[tool.Name] = access
+	p.mu.Unlock()
+
+	return access
+}
+
+// Access returns the recorded access of a tool. Tools that were never
+// classified are treated as write.
+func (p *Policy) Access(name string) Access {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if access, ok := p.access[name]; ok {
+		return access
+	}
+	return AccessWrite
+}
+
+// Allows reports whether a tool with the given access may be exposed and called
+func (p *Policy) Allows(access Access) bool {
+	return !p.readOnly || access == AccessRead
+}
+
+// Annotate classifies a tool and sets its read-only hint accordingly, so
+// clients see the same classification the server enforces
+func (p *Policy) Annotate(tool mcp.Tool) (mcp.Tool, Access) {
+	access := p.Classify(tool)
+	tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(access == AccessRead)
+	if access == AccessRead {
+		tool.Annotations.DestructiveHint = mcp.ToBoolPtr(false)
+	}
+	return tool, access
+}
+
+// Middleware rejects write tools at call time when the server is read-only.
+// It is installed on the MCP server so it covers every tool, however registered.
+func (p *Policy) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !p.Allows(p.Access(request.Params.Name)) {
+			return mcp.NewToolResultError(fmt.Sprintf("tool %s modifies the workspace and is not available in read-only mode", request.Params.Name)), nil
+		}
+
+		return next(ctx, request)
+	}
+}
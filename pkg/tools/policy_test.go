@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPolicyClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		tool   mcp.Tool
+		access Access
+	}{
+		{"list prefix", mcp.NewTool("list_agents"), AccessRead},
+		{"get prefix", mcp.NewTool("get_agent"), AccessRead},
+		{"known read tool", mcp.NewTool("login_status"), AccessRead},
+		{"read-only hint", mcp.NewTool("describe_workspace", mcp.WithReadOnlyHintAnnotation(true)), AccessRead},
+		{"delete", mcp.NewTool("delete_agent"), AccessWrite},
+		{"run", mcp.NewTool("run_model"), AccessWrite},
+		{"local deploy", mcp.NewTool("local_deploy_directory"), AccessWrite},
+		{"write hint", mcp.NewTool("scale_resource", mcp.WithReadOnlyHintAnnotation(false)), AccessWrite},
+		{"undeclared tool", mcp.NewTool("frobnicate"), AccessWrite},
+		{"prefix inside the name", mcp.NewTool("agent_list_secrets"), AccessWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewPolicy(true, false)
+			if got := policy.Classify(tt.tool); got != tt.access {
+				t.Errorf("Classify() = %s, want %s", got, tt.access)
+			}
+			if got := policy.Access(tt.tool.Name); got != tt.access {
+				t.Errorf("Access() = %s, want the classified %s", got, tt.access)
+			}
+
+			annotated, _ := policy.Annotate(tt.tool)
+			if hint := annotated.Annotations.ReadOnlyHint; hint == nil || *hint != (tt.access == AccessRead) {
+				t.Errorf("readOnlyHint = %v, want %v", hint, tt.access == AccessRead)
+			}
+		})
+	}
+
+	if got := NewPolicy(false, false).Access("never_classified"); got != AccessWrite {
+		t.Errorf("Access() of an unclassified tool = %s, want write", got)
+	}
+}
+
+func TestPolicyMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly bool
+		tool     string
+		allowed  bool
+	}{
+		{"read tool in read-only mode", true, "list_agents", true},
+		{"write tool in read-only mode", true, "delete_agent", false},
+		{"unclassified tool in read-only mode", true, "frobnicate", false},
+		{"read tool", false, "list_agents", true},
+		{"write tool", false, "delete_agent", true},
+		{"unclassified tool", false, "frobnicate", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewPolicy(tt.readOnly, false)
+			policy.Classify(mcp.NewTool("list_agents"))
+			policy.Classify(mcp.NewTool("delete_agent"))
+
+			called := false
+			handler := policy.Middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tt.tool
+			result, err := handler(context.Background(), request)
+			if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if called != tt.allowed || result.IsError == tt.allowed {
+				t.Errorf("called = %v, isError = %v, want allowed = %v", called, result.IsError, tt.allowed)
+			}
+			if !tt.allowed {
+				text, _ := result.Content[0].(mcp.TextContent)
+				if !strings.Contains(text.Text, "not available in read-only mode") {
+					t.Errorf("message = %q", text.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyRevealSecrets(t *testing.T) {
+	tests := []struct {
+		readOnly, allow, reveal bool
+	}{
+		{false, false, false},
+		{false, true, true},
+		{true, true, false},
+	}
+
+	for _, tt := range tests {
+		if got := NewPolicy(tt.readOnly, tt.allow).RevealSecrets(); got != tt.reveal {
+			t.Errorf("NewPolicy(%v, %v).RevealSecrets() = %v, want %v", tt.readOnly, tt.allow, got, tt.reveal)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"sync"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progress sends the progress notifications of one tool call
+type progress struct {
+	mu     sync.Mutex
+	server *server.MCPServer
+	token  mcp.ProgressToken
+	count  int
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context through which handlers report progress on a
+// call, when the client asked for it with a progress token
+func WithProgress(ctx context.Context, request mcp.CallToolRequest) context.Context {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return ctx
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progress{server: srv, token: request.Params.Meta.ProgressToken})
+}
+
+// ReportProgress sends message to the client as a progress notification, with
+// secrets masked like in tool results. It reports whether the client is
+// listening, so callers can skip work otherwise.
+func ReportProgress(ctx context.Context, message string) bool {
+	p, ok := ctx.Value(progressKey{}).(*progress)
+	if !ok || p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.count++
+	err := p.server.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": p.token,
+		"progress":      p.count,
+		"message":       redact.Text(message),
+	})
+	if err != nil {
+		logger.Debugf("Failed to send progress notification: %v", err)
+	}
+	return true
+}
+
+// WithoutProgress returns a context in which ReportProgress sends nothing, for
+// work whose own progress would be noise in the progress of the call
+func WithoutProgress(ctx context.Context) context.Context {
+	return context.WithValue(ctx, progressKey{}, (*progress)(nil))
+}
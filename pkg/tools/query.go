@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Per-call arguments narrowing the output of list and get tools
+const (
+	FieldsArg = "fields"
+	SortByArg = "sortBy"
+	OrderArg  = "order"
+)
+
+// withQuery adds the fields argument to list and get tools, and the sortBy
+// and order arguments to list tools
+func withQuery(tool mcp.Tool) mcp.Tool {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[FieldsArg] = map[string]any{
+		"type":        "string",
+		"description": "Comma-separated field paths to return, e.g. 'metadata.name,status,spec.runtime.image'. The result is returned as JSON with only these fields.",
+	}
+	if strings.HasPrefix(tool.Name, "list_") {
+		tool.InputSchema.Properties[SortByArg] = map[string]any{
+			"type":        "string",
+			"description": "Field path to sort by, e.g. 'metadata.name' or 'metadata.createdAt'",
+		}
+		tool.InputSchema.Properties[OrderArg] = map[string]any{
+			"type":        "string",
+			"description": "Sort order: asc (default) or desc",
+			"enum":        []string{"asc", "desc"},
+		}
+	}
+	return tool
+}
+
+// QueryMiddleware reads the fields, sortBy and order arguments into the
+// context for handlers, then applies them to JSON results the handlers left
+// as is, such as get results
+func QueryMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !SupportsFormat(request.Params.Name) {
+			return next(ctx, request)
+		}
+
+		fields, err := formatter.ParseFields(request.GetString(FieldsArg, ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		query := formatter.Query{
+			Fields: fields,
+			SortBy: strings.TrimSpace(request.GetString(SortByArg, "")),
+		}
+		switch order := strings.ToLower(request.GetString(OrderArg, "asc")); order {
+		case "asc", "":
+		case "desc":
+			query.Descending = true
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("unknown sort order %q (expected asc or desc)", order)), nil
+		}
+
+		if query.Empty() {
+			return next(ctx, request)
+		}
+
+		result, err := next(formatter.WithQuery(ctx, query), request)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		for i, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				shaped, ok, err := formatter.ApplyQuery(query, text.Text)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if ok {
+					text.Text = shaped
+					result.Content[i] = text
+				}
+			}
+		}
+
+		return result, nil
+	}
+}
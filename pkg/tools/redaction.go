@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RevealSecretsArg is the per-call argument that turns redaction off
+const RevealSecretsArg = "revealSecrets"
+
+// withRevealSecrets adds the revealSecrets argument to a tool's input schema
+func withRevealSecrets(tool mcp.Tool) mcp.Tool {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[RevealSecretsArg] = map[string]any{
+		"type":        "boolean",
+		"description": "Show secret values in clear instead of key names and fingerprints",
+	}
+	return tool
+}
+
+// RedactionMiddleware masks secrets in every tool result. Callers may opt out
+// with revealSecrets only when the operator allowed it, outside read-only mode.
+func (p *Policy) RedactionMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		reveal := request.GetBool(RevealSecretsArg, false)
+		if reveal && !p.revealSecrets {
+			if p.readOnly {
+				return mcp.NewToolResultError("revealSecrets is not available in read-only mode"), nil
+			}
+			return mcp.NewToolResultError("revealSecrets is not enabled on this server (start it with --allow-reveal-secrets)"), nil
+		}
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		if reveal {
+			logger.Warnf("Secrets revealed in the result of %s", request.Params.Name)
+			return result, nil
+		}
+
+		for i, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				text.Text = redact.Output(text.Text)
+				result.Content[i] = text
+			}
+		}
+
+		return result, nil
+	}
+}
@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolRegistrar is what the Register*Tools functions add their tools to.
+// Both *server.MCPServer and *Registry satisfy it.
+type ToolRegistrar interface {
+	AddTool(tool mcp.Tool, handler server.ToolHandlerFunc)
+}
+
+// Registry sits between the toolsets and the MCP server and decides, in one
+// place, which tools are exposed according to the enable/disable glob patterns
+// and the read/write policy.
+// In dynamic mode, toolsets are kept aside until EnableToolset is called.
+type Registry struct {
+	mu       sync.Mutex
+	server   *server.MCPServer
+	policy   *Policy
+	enable   []string
+	disable  []string
+	dynamic  bool
+	toolsets []*Toolset
+	exposed  []string
+	hidden   []string
+	matched  map[string]bool
+}
+
+// Toolset groups the tools registered by one tools package
+type Toolset struct {
+	Name        string
+	Description string
+	Enabled     bool
+	tools       []server.ServerTool
+}
+
+// ToolsetInfo describes a toolset for list_available_toolsets
+type ToolsetInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	ToolCount   int    `json:"toolCount"`
+}
+
+// toolsetRegistrar collects the tools of one toolset
+type toolsetRegistrar struct {
+	registry *Registry
+	toolset  *Toolset
+}
+
+// AddTool implements ToolRegistrar
+func (t *toolsetRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	tool, ok := t.registry.admit(tool)
+	if !ok {
+		return
+	}
+	t.toolset.tools = append(t.toolset.tools, server.ServerTool{Tool: tool, Handler: handler})
+}
+
+// NewRegistry creates a registry that adds allowed tools to s.
+// An empty enable list allows every tool; disable patterns always win.
+// Tools the policy does not allow (write tools in read-only mode) are never exposed.
+// With dynamic set, toolsets are only exposed once enabled at runtime.
+func NewRegistry(s *server.MCPServer, policy *Policy, enable, disable []string, dynamic bool) (*Registry, error) {
+	for _, pattern := range append(append([]string{}, enable...), disable...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+
+	return &Registry{
+		server:  s,
+		policy:  policy,
+		enable:  enable,
+		disable: disable,
+		dynamic: dynamic,
+		matched: make(map[string]bool),
+	}, nil
+}
+
+// Dynamic reports whether toolsets are enabled at runtime
+func (r *Registry) Dynamic() bool {
+	return r.dynamic
+}
+
+// AddTool implements ToolRegistrar, dropping tools filtered out by the patterns.
+// Tools added directly are exposed immediately, outside of any toolset.
+func (r *Registry) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tool, ok := r.admit(tool)
+	if !ok {
+		return
+	}
+
+	r.exposed = append(r.exposed, tool.Name)
+	r.server.AddTool(tool, handler)
+}
+
+// AddToolset collects the tools of a toolset through register. The toolset is
+// exposed right away unless the registry is dynamic and enabled is false.
+func (r *Registry) AddToolset(name, description string, enabled bool, register func(ToolRegistrar)) {
+	toolset := &Toolset{
+		Name:        name,
+		Description: description,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	register(&toolsetRegistrar{registry: r, toolset: toolset})
+	r.toolsets = append(r.toolsets, toolset)
+
+	if !r.dynamic || enabled {
+		r.expose(toolset)
+	}
+}
+
+// EnableToolset exposes the tools of a toolset and returns their names.
+// The MCP server notifies clients with notifications/tools/list_changed.
+func (r *Registry) EnableToolset(name string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	toolset := r.toolset(name)
+	if toolset == nil {
+		return nil, fmt.Errorf("unknown toolset %q", name)
+	}
+
+	return r.expose(toolset), nil
+}
+
+// expose adds the tools of a toolset to the server once and returns their
+// names. The caller must hold r.mu.
+func (r *Registry) expose(toolset *Toolset) []string {
+	names := make([]string, 0, len(toolset.tools))
+	for _, tool := range toolset.tools {
+		names = append(names, tool.Tool.Name)
+	}
+
+	if toolset.Enabled {
+		return names
+	}
+
+	toolset.Enabled = true
+	r.exposed = append(r.exposed, names...)
+	if len(toolset.tools) > 0 {
+		r.server.AddTools(toolset.tools...)
+	}
+
+	return names
+}
+
+// Toolsets describes every registered toolset, in registration order
+func (r *Registry) Toolsets() []ToolsetInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	infos := make([]ToolsetInfo, 0, len(r.toolsets))
+	for _, toolset := range r.toolsets {
+		infos = append(infos, ToolsetInfo{
+			Name:        toolset.Name,
+			Description: toolset.Description,
+			Enabled:     toolset.Enabled,
+			ToolCount:   len(toolset.tools),
+		})
+	}
+	return infos
+}
+
+// ToolsetTools returns the tool definitions of a toolset, enabled or not
+func (r *Registry) ToolsetTools(name string) ([]mcp.Tool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	toolset := r.toolset(name)
+	if toolset == nil {
+		return nil, fmt.Errorf("unknown toolset %q", name)
+	}
+
+	tools := make([]mcp.Tool, 0, len(toolset.tools))
+	for _, tool := range toolset.tools {
+		tools = append(tools, tool.Tool)
+	}
+	return tools, nil
+}
+
+// toolset finds a toolset by name. The caller must hold r.mu.
+func (r *Registry) toolset(name string) *Toolset {
+	for _, toolset := range r.toolsets {
+		if toolset.Name == name {
+			return toolset
+		}
+	}
+	return nil
+}
+
+// admit classifies a tool and reports whether it may be exposed, recording it
+// as hidden otherwise. The caller must hold r.mu.
+func (r *Registry) admit(tool mcp.Tool) (mcp.Tool, bool) {
+	tool, access := r.policy.Annotate(tool)
+	if !r.allowed(tool.Name) || !r.policy.Allows(access) {
+		r.hidden = append(r.hidden, tool.Name)
+		return tool, false
+	}
+
+	if r.policy.RevealSecrets() {
+		tool = withRevealSecrets(tool)
+	}
+	if access == AccessWrite && SupportsDryRun(tool.Name) {
+		tool = withDryRun(tool)
+	}
+	if SupportsFormat(tool.Name) {
+		tool = withQuery(withFormat(tool))
+	}
+	if SupportsSelector(tool.Name) {
+		tool = withSelector(tool)
+	}
+	if tool.Name != ReadOutputTool {
+		tool = withMaxOutputChars(tool)
+	}
+	return tool, true
+}
+
+// allowed reports whether a tool name passes the enable and disable patterns.
+// The caller must hold r.mu.
+func (r *Registry) allowed(name string) bool {
+	enabled := len(r.enable) == 0 || r.match(r.enable, name)
+	disabled := r.match(r.disable, name)
+	return enabled && !disabled
+}
+
+// Exposed returns the sorted names of the tools added to the server
+func (r *Registry) Exposed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := append([]string{}, r.exposed...)
+	sort.Strings(names)
+	return names
+}
+
+// Hidden returns the sorted names of the tools filtered out by the patterns
+func (r *Registry) Hidden() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := append([]string{}, r.hidden...)
+	sort.Strings(names)
+	return names
+}
+
+// UnmatchedPatterns returns the enable/disable patterns that matched no tool,
+// which usually points at a typo in the configuration
+func (r *Registry) UnmatchedPatterns() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unmatched []string
+	for _, pattern := range append(append([]string{}, r.enable...), r.disable...) {
+		if !r.matched[pattern] {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return unmatched
+}
+
+func (r *Registry) match(patterns []string, name string) bool {
+	found := false
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			r.matched[pattern] = true
+			found = true
+		}
+	}
+	return found
+}
@@ -2,17 +2,56 @@ package runtime
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/benchmark"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/conversation"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/evaluation"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // RuntimeHandler defines the interface for runtime operations
 type RuntimeHandler interface {
-	RunAgent(ctx context.Context, name, message, context string) (string, error)
+	RunAgent(ctx context.Context, name, message, context, threadID string) (string, error)
+	GetAgentConversation(ctx context.Context, name, threadID string, limit int) ([]byte, error)
+	EvaluateAgent(ctx context.Context, name string, cases []evaluation.Case, concurrency int, timeout time.Duration) ([]byte, error)
+	CompareEvaluations(ctx context.Context, baseline, candidate string) ([]byte, error)
 	RunJob(ctx context.Context, name, parameters string) (string, error)
 	RunModel(ctx context.Context, name, body, path, method string) (string, error)
 	RunSandbox(ctx context.Context, name, body, method, path string) (string, error)
+	BenchmarkResource(ctx context.Context, target BenchmarkTarget, opts benchmark.Options) ([]byte, error)
+}
+
+// BenchmarkTarget is the request sent repeatedly by benchmark_resource
+type BenchmarkTarget struct {
+	ResourceType string
+	Name         string
+	Method       string
+	Path         string
+	Body         string
+	Headers      map[string]string
+}
+
+// benchmarkDefaults are the requests sent to each kind of resource when no
+// path or body is given. MCP servers are sent an initialize request, which
+// every server accepts without a session.
+var benchmarkDefaults = map[string]BenchmarkTarget{
+	"agent": {
+		Method: "POST",
+		Body:   `{"inputs":"Hello"}`,
+	},
+	"model": {
+		Method: "POST",
+		Path:   "/v1/chat/completions",
+	},
+	"function": {
+		Method:  "POST",
+		Path:    "/mcp",
+		Body:    `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"blaxel-mcp-server-benchmark","version":"1.0.0"}}}`,
+		Headers: map[string]string{"Accept": "application/json, text/event-stream"},
+	},
 }
 
 // RuntimeHandlerWithReadOnly extends RuntimeHandler with readonly capability
@@ -22,11 +61,11 @@ type RuntimeHandlerWithReadOnly interface {
 }
 
 // RegisterRuntimeTools registers runtime tools with the given handler
-func RegisterRuntimeTools(s *server.MCPServer, handler RuntimeHandler) {
+func RegisterRuntimeTools(s tools.ToolRegistrar, handler RuntimeHandler) {
 
 	// Run/Chat with Agent
 	runAgentTool := mcp.NewTool("run_agent",
-		mcp.WithDescription("Chat with or invoke an agent"),
+		mcp.WithDescription("Chat with or invoke an agent. Streamed responses are forwarded as progress notifications and assembled into the final text."),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("Name of the agent to run"),
@@ -38,6 +77,9 @@ func RegisterRuntimeTools(s *server.MCPServer, handler RuntimeHandler) {
 		mcp.WithString("context",
 			mcp.Description("Optional context data for the agent (JSON string)"),
 		),
+		mcp.WithString("threadId",
+			mcp.Description("Conversation thread to continue, as returned by a previous run_agent call. A new thread is started when omitted."),
+		),
 	)
 
 	s.AddTool(runAgentTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -52,8 +94,14 @@ func RegisterRuntimeTools(s *server.MCPServer, handler RuntimeHandler) {
 		}
 
 		context := request.GetString("context", "")
+		threadID := request.GetString("threadId", "")
+		if threadID != "" {
+			if err := conversation.ValidateThreadID(threadID); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
 
-		result, err := handler.RunAgent(ctx, name, message, context)
+		result, err := handler.RunAgent(tools.WithProgress(ctx, request), name, message, context, threadID)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -61,6 +109,210 @@ func RegisterRuntimeTools(s *server.MCPServer, handler RuntimeHandler) {
 		return mcp.NewToolResultText(result), nil
 	})
 
+	// Replay a conversation with an agent
+	getAgentConversationTool := mcp.NewTool("get_agent_conversation",
+		mcp.WithDescription("Replay the messages exchanged with an agent in a run_agent thread, from the local transcript. Without threadId, list the recorded threads with the agent."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the agent"),
+		),
+		mcp.WithString("threadId",
+			mcp.Description("Thread to replay"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Only the most recent turns (default: all)"),
+		),
+	)
+
+	s.AddTool(getAgentConversationTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("agent name is required"), nil
+		}
+
+		threadID := request.GetString("threadId", "")
+		limit := request.GetInt("limit", 0)
+		if limit < 0 {
+			return mcp.NewToolResultError("limit must not be negative"), nil
+		}
+
+		result, err := handler.GetAgentConversation(ctx, name, threadID, limit)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Evaluate an agent on a dataset
+	evaluateAgentTool := mcp.NewTool("evaluate_agent",
+		mcp.WithDescription(fmt.Sprintf("Run a dataset of inputs through an agent and check each output with exact, contains, regex or jsonSchema checks. Reports pass/fail and latency per case and an aggregate score, and saves the run for compare_evaluations. At most %d cases.", evaluation.MaxCases)),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the agent to evaluate"),
+		),
+		mcp.WithString("cases",
+			mcp.Description(`Inline dataset, as a JSON array or JSON lines of cases: {"id", "input", "context", "expected", "checks": [{"type", "value", "pattern", "schema", "ignoreCase"}]}`),
+		),
+		mcp.WithString("datasetPath",
+			mcp.Description("Path of a JSONL dataset file, instead of cases"),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description("Cases run at the same time (default: 4, max: 16)"),
+		),
+		mcp.WithNumber("timeoutSeconds",
+			mcp.Description("Timeout of each case in seconds (default: 120)"),
+		),
+	)
+
+	s.AddTool(evaluateAgentTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.GetString("name", "")
+		if name == "" {
+			return mcp.NewToolResultError("agent name is required"), nil
+		}
+
+		inline := request.GetString("cases", "")
+		path := request.GetString("datasetPath", "")
+		var (
+			cases []evaluation.Case
+			err   error
+		)
+		switch {
+		case inline != "" && path != "":
+			return mcp.NewToolResultError("give either cases or datasetPath, not both"), nil
+		case inline != "":
+			cases, err = evaluation.ParseCases([]byte(inline))
+		case path != "":
+			cases, err = evaluation.LoadCases(path)
+		default:
+			return mcp.NewToolResultError("a dataset is required: give cases or datasetPath"), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		concurrency := request.GetInt("concurrency", 4)
+		if concurrency < 1 || concurrency > 16 {
+			return mcp.NewToolResultError("concurrency must be between 1 and 16"), nil
+		}
+		timeoutSeconds := request.GetInt("timeoutSeconds", 120)
+		if timeoutSeconds < 1 {
+			return mcp.NewToolResultError("timeoutSeconds must be positive"), nil
+		}
+
+		result, err := handler.EvaluateAgent(tools.WithProgress(ctx, request), name, cases, concurrency, time.Duration(timeoutSeconds)*time.Second)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Compare two evaluation runs
+	compareEvaluationsTool := mcp.NewTool("compare_evaluations",
+		mcp.WithDescription("Compare two evaluate_agent runs: score and latency changes, regressions, fixes and cases still failing"),
+		mcp.WithString("baseline",
+			mcp.Required(),
+			mcp.Description("Run ID of the baseline evaluation"),
+		),
+		mcp.WithString("candidate",
+			mcp.Required(),
+			mcp.Description("Run ID of the evaluation compared to the baseline"),
+		),
+	)
+
+	s.AddTool(compareEvaluationsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		baseline := request.GetString("baseline", "")
+		candidate := request.GetString("candidate", "")
+		if baseline == "" || candidate == "" {
+			return mcp.NewToolResultError("baseline and candidate run IDs are required"), nil
+		}
+
+		result, err := handler.CompareEvaluations(ctx, baseline, candidate)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
+	// Load-test a deployed resource
+	benchmarkResourceTool := mcp.NewTool("benchmark_resource",
+		mcp.WithDescription(fmt.Sprintf("Load-test an agent, model API or MCP server: send a number of requests at a given concurrency, optionally for at most a duration, and report latency and time-to-first-byte percentiles (p50/p90/p99), throughput and errors by status code. At most %d requests, %d concurrent, for %s.", benchmark.MaxRequests, benchmark.MaxConcurrency, benchmark.MaxDuration)),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum("agent", "model", "function"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithNumber("requests",
+			mcp.Description(fmt.Sprintf("Total requests to send (default: 50, max: %d)", benchmark.MaxRequests)),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description(fmt.Sprintf("Requests in flight at the same time (default: 5, max: %d)", benchmark.MaxConcurrency)),
+		),
+		mcp.WithNumber("durationSeconds",
+			mcp.Description(fmt.Sprintf("Stop sending requests after this many seconds, even if fewer were sent (max: %d)", int(benchmark.MaxDuration.Seconds()))),
+		),
+		mcp.WithNumber("timeoutSeconds",
+			mcp.Description("Timeout of each request in seconds (default: 60)"),
+		),
+		mcp.WithString("body",
+			mcp.Description(`Body of each request (JSON string). Required for models; defaults to {"inputs":"Hello"} for agents and an MCP initialize request for MCP servers.`),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of each request (default: none for agents, /v1/chat/completions for models, /mcp for MCP servers)"),
+		),
+		mcp.WithString("method",
+			mcp.Description("HTTP method of each request (default: POST)"),
+		),
+	)
+
+	s.AddTool(benchmarkResourceTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resourceType := request.GetString("resourceType", "")
+		target, ok := benchmarkDefaults[resourceType]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown resource type '%s' (expected agent, model or function)", resourceType)), nil
+		}
+
+		target.ResourceType = resourceType
+		target.Name = request.GetString("name", "")
+		if target.Name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+		target.Method = request.GetString("method", target.Method)
+		target.Path = request.GetString("path", target.Path)
+		target.Body = request.GetString("body", target.Body)
+		if target.Body == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("body is required to benchmark a %s", resourceType)), nil
+		}
+		headers := map[string]string{"Content-Type": "application/json"}
+		for key, value := range target.Headers {
+			headers[key] = value
+		}
+		target.Headers = headers
+
+		opts := benchmark.Options{
+			Requests:    request.GetInt("requests", 50),
+			Concurrency: request.GetInt("concurrency", 5),
+			Duration:    time.Duration(request.GetInt("durationSeconds", 0)) * time.Second,
+			Timeout:     time.Duration(request.GetInt("timeoutSeconds", 60)) * time.Second,
+		}
+		if err := opts.Validate(); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := handler.BenchmarkResource(tools.WithProgress(ctx, request), target, opts)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+
 	// Trigger/Run Job
 	runJobTool := mcp.NewTool("run_job",
 		mcp.WithDescription("Trigger or run a job"),
@@ -91,7 +343,7 @@ func RegisterRuntimeTools(s *server.MCPServer, handler RuntimeHandler) {
 
 	// Invoke/Run Model
 	runModelTool := mcp.NewTool("run_model",
-		mcp.WithDescription("Invoke a model API"),
+		mcp.WithDescription("Invoke a model API. Streamed responses (e.g. \"stream\": true in the body) are forwarded as progress notifications and assembled into the final text."),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("Name of the model API to invoke"),
@@ -126,7 +378,7 @@ func RegisterRuntimeTools(s *server.MCPServer, handler RuntimeHandler) {
 			method = "POST"
 		}
 
-		result, err := handler.RunModel(ctx, name, body, path, method)
+		result, err := handler.RunModel(tools.WithProgress(ctx, request), name, body, path, method)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
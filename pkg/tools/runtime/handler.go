@@ -3,20 +3,33 @@ package runtime
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/benchmark"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/conversation"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/evaluation"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/toolkit/sdk"
 )
 
+// threadIDHeader carries the conversation thread to and from Blaxel agents,
+// which also accept it as the thread_id field of the body
+const threadIDHeader = "X-Blaxel-Thread-Id"
+
 // SDKHandler implements RuntimeHandler using the SDK client
 type SDKHandler struct {
-	sdkClient *sdk.ClientWithResponses
-	cfg       *config.Config
-	readOnly  bool
+	sdkClient     *sdk.ClientWithResponses
+	cfg           *config.Config
+	readOnly      bool
+	conversations *conversation.Store
+	evaluations   *evaluation.Store
 }
 
 // NewSDKHandler creates a new SDK-based runtime handler
@@ -26,22 +39,102 @@ func NewSDKHandler(cfg *config.Config) (RuntimeHandler, error) {
 		return nil, fmt.Errorf("failed to initialize SDK client: %w", err)
 	}
 
+	conversations, err := conversation.Open(cfg.Workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open conversation store: %w", err)
+	}
+
+	evaluations, err := evaluation.Open(cfg.Workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open evaluation store: %w", err)
+	}
+
 	return &SDKHandler{
-		sdkClient: sdkClient,
-		cfg:       cfg,
-		readOnly:  cfg.ReadOnly,
+		sdkClient:     sdkClient,
+		cfg:           cfg,
+		readOnly:      cfg.ReadOnly,
+		conversations: conversations,
+		evaluations:   evaluations,
 	}, nil
 }
 
 // RunAgent implements RuntimeHandler.RunAgent
-func (h *SDKHandler) RunAgent(ctx context.Context, name, message, context string) (string, error) {
+func (h *SDKHandler) RunAgent(ctx context.Context, name, message, context, threadID string) (string, error) {
 	if h.sdkClient == nil {
 		return "", fmt.Errorf("SDK client not initialized")
 	}
 
+	// Continue the given thread, or start a new one
+	if threadID == "" {
+		threadID = conversation.NewThreadID()
+	} else {
+		thread, err := h.conversations.Read(threadID)
+		if err != nil && !errors.Is(err, conversation.ErrNotFound) {
+			return "", err
+		}
+		if thread != nil && thread.Agent != "" && thread.Agent != name {
+			return "", fmt.Errorf("thread '%s' belongs to agent '%s', not '%s'", threadID, thread.Agent, name)
+		}
+	}
+
+	reply, err := h.invokeAgent(ctx, name, message, context, threadID)
+	if err != nil {
+		return "", err
+	}
+	body, stream := reply.body, reply.stream
+
+	// The agent may have assigned its own thread ID
+	sent := threadID
+	threadID = reply.threadID
+
+	// Keep the exchange so that get_agent_conversation can replay it
+	now := time.Now().UTC()
+	err = h.conversations.Append(threadID,
+		conversation.Turn{Time: now, Agent: name, Role: conversation.RoleUser, Content: message},
+		conversation.Turn{Time: now, Agent: name, Role: conversation.RoleAgent, Content: string(body)},
+	)
+	if err != nil {
+		logger.Warnf("Failed to record conversation %s with agent %s: %v", threadID, name, err)
+	}
+
+	output := map[string]interface{}{
+		"threadId": threadID,
+		"response": string(body),
+	}
+	if threadID != sent {
+		output["requestedThreadId"] = sent
+	}
+	if stream != nil {
+		output["streamed"] = true
+		output["events"] = stream.Events
+	}
+
+	// Keep JSON responses structured for better readability
+	var result interface{}
+	if err := json.Unmarshal(body, &result); err == nil {
+		output["response"] = result
+	}
+
+	formatted, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to format response: %w", err)
+	}
+	return string(formatted), nil
+}
+
+// agentReply is the response of an agent to one message
+type agentReply struct {
+	body     []byte        // the response, or the text assembled from a stream
+	stream   *streamResult // nil unless the response was an event stream
+	threadID string        // the thread returned by the agent, or the one sent
+}
+
+// invokeAgent sends a message to an agent on a thread
+func (h *SDKHandler) invokeAgent(ctx context.Context, name, message, context, threadID string) (*agentReply, error) {
 	// Prepare the request body for the agent
 	requestBody := map[string]interface{}{
-		"inputs": message,
+		"inputs":    message,
+		"thread_id": threadID,
 	}
 	if context != "" {
 		// Parse context JSON string into interface{}
@@ -53,7 +146,7 @@ func (h *SDKHandler) RunAgent(ctx context.Context, name, message, context string
 
 	bodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Use the SDK Run method to invoke the agent
@@ -64,35 +157,146 @@ func (h *SDKHandler) RunAgent(ctx context.Context, name, message, context string
 		name,
 		"POST",
 		"", // Path will be constructed by the Run method
-		map[string]string{"Content-Type": "application/json"},
+		map[string]string{"Content-Type": "application/json", threadIDHeader: threadID},
 		nil, // No query params
 		string(bodyBytes),
 		false, // debug
 		false, // local
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to run agent: %w", err)
+		return nil, fmt.Errorf("failed to run agent: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("agent invocation failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Read response, forwarding streamed content as it arrives
+	body, stream, err := readBody(ctx, resp)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if stream != nil {
+		body = []byte(stream.Text)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("agent invocation failed with status %d: %s", resp.StatusCode, string(body))
+	reply := &agentReply{body: body, stream: stream, threadID: threadID}
+	if returned := resp.Header.Get(threadIDHeader); returned != "" && conversation.ValidateThreadID(returned) == nil {
+		reply.threadID = returned
 	}
+	return reply, nil
+}
 
-	// Try to format as JSON for better readability
+// GetAgentConversation implements RuntimeHandler.GetAgentConversation
+func (h *SDKHandler) GetAgentConversation(ctx context.Context, name, threadID string, limit int) ([]byte, error) {
 	var result interface{}
-	if err := json.Unmarshal(body, &result); err == nil {
-		formatted, _ := json.MarshalIndent(result, "", "  ")
-		return string(formatted), nil
+	if threadID == "" {
+		threads, err := h.conversations.List(name)
+		if err != nil {
+			return nil, err
+		}
+		result = map[string]interface{}{
+			"agent":   name,
+			"threads": threads,
+		}
+	} else {
+		thread, err := h.conversations.Read(threadID)
+		if errors.Is(err, conversation.ErrNotFound) {
+			return nil, fmt.Errorf("no conversation '%s' recorded with agent '%s'", threadID, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if thread.Agent != name {
+			return nil, fmt.Errorf("thread '%s' belongs to agent '%s', not '%s'", threadID, thread.Agent, name)
+		}
+
+		// Keep the most recent turns
+		total := len(thread.Turns)
+		if limit > 0 && total > limit {
+			thread.Turns = thread.Turns[total-limit:]
+		}
+		result = map[string]interface{}{
+			"threadId":   thread.ID,
+			"agent":      thread.Agent,
+			"totalTurns": total,
+			"turns":      thread.Turns,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return jsonData, nil
+}
+
+// EvaluateAgent implements RuntimeHandler.EvaluateAgent
+func (h *SDKHandler) EvaluateAgent(ctx context.Context, name string, cases []evaluation.Case, concurrency int, timeout time.Duration) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	// Each case runs on a thread of its own, without a transcript, and its
+	// streamed content is not forwarded: progress is reported per case
+	invoke := func(ctx context.Context, c evaluation.Case) (string, error) {
+		reply, err := h.invokeAgent(tools.WithoutProgress(ctx), name, c.Input, string(c.Context), conversation.NewThreadID())
+		if err != nil {
+			return "", err
+		}
+
+		// Check the text of JSON string responses rather than its encoding
+		var text string
+		if err := json.Unmarshal(reply.body, &text); err == nil {
+			return text, nil
+		}
+		return string(reply.body), nil
+	}
+
+	done := func(result evaluation.CaseResult, completed int) {
+		status := "failed"
+		switch {
+		case result.Passed:
+			status = "passed"
+		case result.Error != "":
+			status = "errored"
+		}
+		tools.ReportProgress(ctx, fmt.Sprintf("case %s %s (%d/%d)", result.ID, status, completed, len(cases)))
+	}
+
+	run := evaluation.Execute(ctx, name, cases, concurrency, timeout, invoke, done)
+	if err := h.evaluations.Save(run); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
 	}
 
-	return string(body), nil
+	return jsonData, nil
+}
+
+// CompareEvaluations implements RuntimeHandler.CompareEvaluations
+func (h *SDKHandler) CompareEvaluations(ctx context.Context, baseline, candidate string) ([]byte, error) {
+	before, err := h.evaluations.Load(baseline)
+	if err != nil {
+		return nil, err
+	}
+	after, err := h.evaluations.Load(candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.MarshalIndent(evaluation.Compare(before, after), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return jsonData, nil
 }
 
 // RunJob implements RuntimeHandler.RunJob
@@ -178,14 +382,24 @@ func (h *SDKHandler) RunModel(ctx context.Context, name, body, path, method stri
 	}
 	defer resp.Body.Close()
 
-	// Read response
-	bodyBytes, err = io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ = io.ReadAll(resp.Body)
+		return "", fmt.Errorf("model invocation failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// Read response, forwarding streamed content as it arrives
+	bodyBytes, stream, err := readBody(ctx, resp)
 	if err != nil {
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("model invocation failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	// Streamed chunks are assembled into the final text
+	if stream != nil {
+		formatted, err := json.MarshalIndent(stream, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(formatted), nil
 	}
 
 	// Try to format as JSON for better readability
@@ -256,6 +470,78 @@ func (h *SDKHandler) RunSandbox(ctx context.Context, name, body, method, path st
 	return string(bodyBytes), nil
 }
 
+// BenchmarkResource implements RuntimeHandler.BenchmarkResource
+func (h *SDKHandler) BenchmarkResource(ctx context.Context, target BenchmarkTarget, opts benchmark.Options) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	do := func(ctx context.Context) benchmark.Sample {
+		start := time.Now()
+		resp, err := h.sdkClient.Run(
+			ctx,
+			h.cfg.Workspace,
+			target.ResourceType,
+			target.Name,
+			target.Method,
+			target.Path,
+			target.Headers,
+			nil, // No query params
+			target.Body,
+			false, // debug
+			false, // local
+		)
+		if err != nil {
+			return benchmark.Sample{Latency: time.Since(start), Err: err}
+		}
+		defer resp.Body.Close()
+
+		// Run returns once the headers are read
+		sample := benchmark.Sample{Status: resp.StatusCode, TTFB: time.Since(start)}
+		_, sample.Err = io.Copy(io.Discard, resp.Body)
+		sample.Latency = time.Since(start)
+		return sample
+	}
+
+	step := max(1, opts.Requests/20)
+	progress := func(completed, failed int) {
+		if completed%step == 0 {
+			tools.ReportProgress(ctx, fmt.Sprintf("%d/%d requests completed, %d failed", completed, opts.Requests, failed))
+		}
+	}
+
+	report := benchmark.Run(ctx, opts, do, progress)
+
+	message := fmt.Sprintf("Sent %d requests to %s '%s': %d succeeded, %d failed, %.2f requests/s",
+		report.Requests, target.ResourceType, target.Name, report.Succeeded, report.Failed, report.ThroughputRPS)
+	if report.Latency != nil {
+		message += fmt.Sprintf(", p50 %.0fms, p99 %.0fms", report.Latency.P50, report.Latency.P99)
+	}
+
+	result := map[string]interface{}{
+		"success":      report.Succeeded > 0,
+		"message":      message,
+		"resourceType": target.ResourceType,
+		"name":         target.Name,
+		"method":       target.Method,
+		"path":         target.Path,
+		"load": map[string]interface{}{
+			"requests":        opts.Requests,
+			"concurrency":     opts.Concurrency,
+			"durationSeconds": opts.Duration.Seconds(),
+			"timeoutSeconds":  opts.Timeout.Seconds(),
+		},
+		"report": report,
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return jsonData, nil
+}
+
 // IsReadOnly implements RuntimeHandlerWithReadOnly.IsReadOnly
 func (h *SDKHandler) IsReadOnly() bool {
 	return h.readOnly
@@ -0,0 +1,235 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+)
+
+// progressInterval is the shortest time between two progress notifications;
+// content arriving in between is sent together
+const progressInterval = 250 * time.Millisecond
+
+// maxOtherEvents bounds the events without text kept in a stream result
+const maxOtherEvents = 20
+
+// streamResult is an event stream assembled into one result
+type streamResult struct {
+	Streamed     bool          `json:"streamed"`
+	Text         string        `json:"text"`
+	Events       int           `json:"events"`
+	FinishReason string        `json:"finishReason,omitempty"`
+	Usage        interface{}   `json:"usage,omitempty"`
+	OtherEvents  []interface{} `json:"otherEvents,omitempty"`
+}
+
+// readBody reads a successful response body. Event streams are parsed and
+// their text assembled into stream; chunked bodies are read as they arrive.
+// Either way, content is forwarded as progress notifications on the way.
+func readBody(ctx context.Context, resp *http.Response) (body []byte, stream *streamResult, err error) {
+	progress := &forwarder{ctx: ctx}
+	defer progress.flush()
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/event-stream" {
+		stream, err := readEventStream(resp.Body, progress)
+		return nil, stream, err
+	}
+
+	if !slices.Contains(resp.TransferEncoding, "chunked") {
+		body, err := io.ReadAll(resp.Body)
+		return body, nil, err
+	}
+
+	var buffer bytes.Buffer
+	forwarded := 0
+	chunk := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(chunk)
+		buffer.Write(chunk[:n])
+
+		// Forward complete characters only; a split one goes with the next chunk
+		data := buffer.Bytes()
+		end := len(data)
+		if err == nil {
+			end = completeRunes(data, forwarded)
+		}
+		if end > forwarded {
+			progress.write(string(data[forwarded:end]))
+			forwarded = end
+		}
+
+		if errors.Is(err, io.EOF) {
+			return buffer.Bytes(), nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// completeRunes returns the end of the complete characters of data after
+// from, leaving out a character split at the end
+func completeRunes(data []byte, from int) int {
+	end := len(data)
+	for i := 1; i <= utf8.UTFMax && end-i >= from; i++ {
+		if utf8.RuneStart(data[end-i]) {
+			if !utf8.FullRune(data[end-i : end]) {
+				end -= i
+			}
+			break
+		}
+	}
+	return end
+}
+
+// readEventStream parses server-sent events, assembling the text of
+// OpenAI-style chunks (choices[].delta.content) and of plain-text events
+func readEventStream(r io.Reader, progress *forwarder) (*streamResult, error) {
+	result := &streamResult{Streamed: true}
+	reader := bufio.NewReader(r)
+	var data []string
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read event stream: %w", err)
+		}
+		eof := errors.Is(err, io.EOF)
+
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			// A blank line ends an event, as does the end of the stream
+			if len(data) > 0 {
+				result.add(strings.Join(data, "\n"), progress)
+				data = data[:0]
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment, used as keep-alive
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			if field == "data" {
+				data = append(data, strings.TrimPrefix(value, " "))
+			}
+		}
+
+		if eof {
+			if len(data) > 0 {
+				result.add(strings.Join(data, "\n"), progress)
+			}
+			return result, nil
+		}
+	}
+}
+
+// add folds the data of one event into the result
+func (r *streamResult) add(data string, progress *forwarder) {
+	if data == "[DONE]" {
+		return
+	}
+	r.Events++
+
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		// Plain-text events, quoted or not, are pieces of the text
+		var text string
+		if json.Unmarshal([]byte(data), &text) != nil {
+			text = data
+		}
+		r.Text += text
+		progress.write(text)
+		return
+	}
+
+	text, found := eventText(event)
+	if usage, ok := event["usage"]; ok && usage != nil {
+		r.Usage = usage
+	}
+	if choices, ok := event["choices"].([]interface{}); ok {
+		for _, c := range choices {
+			if choice, ok := c.(map[string]interface{}); ok {
+				if reason, ok := choice["finish_reason"].(string); ok {
+					r.FinishReason = reason
+				}
+			}
+		}
+	}
+
+	if found {
+		if text != "" {
+			r.Text += text
+			progress.write(text)
+		}
+	} else if len(r.OtherEvents) < maxOtherEvents && event["usage"] == nil {
+		r.OtherEvents = append(r.OtherEvents, event)
+	}
+}
+
+// eventText returns the text carried by a JSON event: the delta or message of
+// OpenAI chat chunks, the text of completion chunks, or a delta's text. Chunks
+// carrying only a role or a finish reason are found with no text.
+func eventText(event map[string]interface{}) (string, bool) {
+	if choices, ok := event["choices"].([]interface{}); ok && len(choices) > 0 {
+		var text strings.Builder
+		for _, c := range choices {
+			choice, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, key := range []string{"delta", "message"} {
+				if part, ok := choice[key].(map[string]interface{}); ok {
+					if content, ok := part["content"].(string); ok {
+						text.WriteString(content)
+					}
+				}
+			}
+			if content, ok := choice["text"].(string); ok {
+				text.WriteString(content)
+			}
+		}
+		return text.String(), true
+	}
+
+	if delta, ok := event["delta"].(map[string]interface{}); ok {
+		if content, ok := delta["text"].(string); ok {
+			return content, true
+		}
+	}
+	return "", false
+}
+
+// forwarder sends content as progress notifications, batching what arrives
+// within progressInterval
+type forwarder struct {
+	ctx     context.Context
+	pending strings.Builder
+	last    time.Time
+}
+
+func (f *forwarder) write(text string) {
+	f.pending.WriteString(text)
+	if time.Since(f.last) >= progressInterval {
+		f.flush()
+	}
+}
+
+func (f *forwarder) flush() {
+	if f.pending.Len() == 0 {
+		return
+	}
+	tools.ReportProgress(f.ctx, f.pending.String())
+	f.pending.Reset()
+	f.last = time.Now()
+}
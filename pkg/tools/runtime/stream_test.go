@@ -0,0 +1,226 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestReadEventStream(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		text   string
+		events int
+		finish string
+	}{
+		{
+			name: "openai delta chunks",
+			stream: "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n" +
+				"data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+				"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+				"data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+				"data: [DONE]\n\n",
+			text:   "Hello",
+			events: 4,
+			finish: "stop",
+		},
+		{
+			name:   "done is not an event",
+			stream: "data: [DONE]\n\n",
+			text:   "",
+			events: 0,
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: first\ndata: second\n\n",
+			text:   "first\nsecond",
+			events: 1,
+		},
+		{
+			name:   "missing trailing blank line",
+			stream: "data: {\"choices\":[{\"delta\":{\"content\":\"end\"}}]}",
+			text:   "end",
+			events: 1,
+		},
+		{
+			name:   "comments and other fields",
+			stream: ": keep-alive\nevent: message\nid: 1\ndata: \"quoted\"\n\n",
+			text:   "quoted",
+			events: 1,
+		},
+		{
+			name:   "crlf line endings",
+			stream: "data: a\r\n\r\ndata: b\r\n\r\n",
+			text:   "ab",
+			events: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := readEventStream(strings.NewReader(tt.stream), &forwarder{ctx: context.Background()})
+			if err != nil {
+				t.Fatalf("readEventStream() error = %v", err)
+			}
+			if result.Text != tt.text {
+				t.Errorf("Text = %q, want %q", result.Text, tt.text)
+			}
+			if result.Events != tt.events {
+				t.Errorf("Events = %d, want %d", result.Events, tt.events)
+			}
+			if result.FinishReason != tt.finish {
+				t.Errorf("FinishReason = %q, want %q", result.FinishReason, tt.finish)
+			}
+		})
+	}
+}
+
+func TestReadEventStreamUsageAndOtherEvents(t *testing.T) {
+	stream := "data: {\"type\":\"ping\"}\n\n" +
+		"data: {\"choices\":[],\"usage\":{\"total_tokens\":3}}\n\n"
+
+	result, err := readEventStream(strings.NewReader(stream), &forwarder{ctx: context.Background()})
+	if err != nil {
+		t.Fatalf("readEventStream() error = %v", err)
+	}
+	if result.Usage == nil {
+		t.Error("Usage should be kept")
+	}
+	if len(result.OtherEvents) != 1 {
+		t.Errorf("OtherEvents = %v, want the ping event only", result.OtherEvents)
+	}
+}
+
+func TestEventText(t *testing.T) {
+	tests := []struct {
+		name  string
+		event map[string]interface{}
+		text  string
+		found bool
+	}{
+		{
+			name:  "chat delta",
+			event: map[string]interface{}{"choices": []interface{}{map[string]interface{}{"delta": map[string]interface{}{"content": "hi"}}}},
+			text:  "hi",
+			found: true,
+		},
+		{
+			name:  "chat message",
+			event: map[string]interface{}{"choices": []interface{}{map[string]interface{}{"message": map[string]interface{}{"content": "full"}}}},
+			text:  "full",
+			found: true,
+		},
+		{
+			name:  "completion text",
+			event: map[string]interface{}{"choices": []interface{}{map[string]interface{}{"text": "done"}}},
+			text:  "done",
+			found: true,
+		},
+		{
+			name:  "role only",
+			event: map[string]interface{}{"choices": []interface{}{map[string]interface{}{"delta": map[string]interface{}{"role": "assistant"}}}},
+			text:  "",
+			found: true,
+		},
+		{
+			name:  "delta text",
+			event: map[string]interface{}{"delta": map[string]interface{}{"text": "piece"}},
+			text:  "piece",
+			found: true,
+		},
+		{
+			name:  "no text",
+			event: map[string]interface{}{"type": "ping"},
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, found := eventText(tt.event)
+			if text != tt.text || found != tt.found {
+				t.Errorf("eventText() = %q, %v, want %q, %v", text, found, tt.text, tt.found)
+			}
+		})
+	}
+}
+
+func TestCompleteRunes(t *testing.T) {
+	euro := []byte("€") // 3 bytes
+
+	tests := []struct {
+		name string
+		data []byte
+		from int
+		want int
+	}{
+		{"ascii", []byte("abc"), 0, 3},
+		{"complete rune", append([]byte("a"), euro...), 0, 4},
+		{"split after one byte", append([]byte("a"), euro[:1]...), 0, 1},
+		{"split after two bytes", append([]byte("a"), euro[:2]...), 0, 1},
+		{"split rune only", euro[:2], 0, 0},
+		{"already forwarded", append([]byte("ab"), euro[:1]...), 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completeRunes(tt.data, tt.from); got != tt.want {
+				t.Errorf("completeRunes(%q, %d) = %d, want %d", tt.data, tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+// byteReader returns its content a few bytes at a time, splitting characters
+type byteReader struct {
+	data []byte
+	size int
+}
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := min(r.size, len(r.data), len(p))
+	copy(p, r.data[:n])
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestReadBodyChunked(t *testing.T) {
+	content := "héllo wörld €€€"
+	resp := &http.Response{
+		Header:           http.Header{"Content-Type": []string{"text/plain"}},
+		TransferEncoding: []string{"chunked"},
+		Body:             io.NopCloser(&byteReader{data: []byte(content), size: 2}),
+	}
+
+	body, stream, err := readBody(context.Background(), resp)
+	if err != nil {
+		t.Fatalf("readBody() error = %v", err)
+	}
+	if stream != nil {
+		t.Error("a chunked body is not an event stream")
+	}
+	if string(body) != content {
+		t.Errorf("body = %q, want %q", body, content)
+	}
+}
+
+func TestReadBodyEventStream(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": []string{"text/event-stream; charset=utf-8"}},
+		Body:   io.NopCloser(strings.NewReader("data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\ndata: [DONE]\n\n")),
+	}
+
+	body, stream, err := readBody(context.Background(), resp)
+	if err != nil {
+		t.Fatalf("readBody() error = %v", err)
+	}
+	if body != nil || stream == nil || stream.Text != "ok" {
+		t.Errorf("readBody() = %q, %+v, want the assembled stream", body, stream)
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"strconv"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // SandboxHandler defines the interface for sandbox operations
@@ -23,7 +24,7 @@ type SandboxHandlerWithReadOnly interface {
 }
 
 // RegisterSandboxTools registers sandbox tools with the given handler
-func RegisterSandboxTools(s *server.MCPServer, handler SandboxHandler) {
+func RegisterSandboxTools(s tools.ToolRegistrar, handler SandboxHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(SandboxHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
@@ -34,9 +35,13 @@ func RegisterSandboxTools(s *server.MCPServer, handler SandboxHandler) {
 		mcp.WithString("filter",
 			mcp.Description("Optional filter string"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(listSandboxesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		filter := request.GetString("filter", "")
 
 		result, err := handler.ListSandboxes(ctx, filter)
@@ -54,9 +59,13 @@ func RegisterSandboxTools(s *server.MCPServer, handler SandboxHandler) {
 			mcp.Required(),
 			mcp.Description("Name of the sandbox to retrieve"),
 		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bypass the response cache and fetch fresh data"),
+		),
 	)
 
 	s.AddTool(getSandboxTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = client.WithRefresh(ctx, request.GetBool("refresh", false))
 		name := request.GetString("name", "")
 		if name == "" {
 			return mcp.NewToolResultError("sandbox name is required"), nil
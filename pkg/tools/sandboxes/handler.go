@@ -12,6 +12,7 @@ import (
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/selector"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/toolkit/sdk"
 )
@@ -19,6 +20,7 @@ import (
 // SDKHandler implements SandboxHandler using the SDK client
 type SDKHandler struct {
 	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
 	readOnly  bool
 }
 
@@ -31,6 +33,7 @@ func NewSDKHandler(cfg *config.Config) (SandboxHandler, error) {
 
 	return &SDKHandler{
 		sdkClient: sdkClient,
+		cache:     client.SharedCache(cfg),
 		readOnly:  cfg.ReadOnly,
 	}, nil
 }
@@ -41,7 +44,9 @@ func (h *SDKHandler) ListSandboxes(ctx context.Context, filter string) ([]byte,
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	resp, err := h.sdkClient.ListSandboxesWithResponse(ctx)
+	resp, err := client.Fetch(ctx, h.cache, client.ResourceSandboxes, "list", "", func() (*sdk.ListSandboxesResponse, error) {
+		return h.sdkClient.ListSandboxesWithResponse(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sandboxes: %w", err)
 	}
@@ -67,14 +72,31 @@ func (h *SDKHandler) ListSandboxes(ctx context.Context, filter string) ([]byte,
 		sandboxes = filtered
 	}
 
+	// Keep the sandboxes matching the label selector and status filters
+	sandboxes = selector.Apply(ctx, sandboxes, func(sandbox sdk.Sandbox) (*sdk.Metadata, *string) {
+		return sandbox.Metadata, sandbox.Status
+	})
+
+	// Sort by and project on SDK fields when the caller asked to
+	sandboxes, shaped, done, err := formatter.Shape(ctx, sandboxes)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return shaped, nil
+	}
+
 	// Convert SDK sandboxes to simple models
 	sandboxModels := make([]formatter.SandboxModel, len(sandboxes))
 	for i, sandbox := range sandboxes {
 		sandboxModels[i] = convertToSandboxModel(sandbox)
 	}
 
-	// Format the sandboxes using the formatter
-	formatted := formatter.FormatSandboxes(sandboxModels)
+	// Format the models in the requested output format
+	formatted, err := formatter.Render(formatter.FormatFromContext(ctx), sandboxModels, formatter.SandboxColumns, formatter.FormatSandboxes)
+	if err != nil {
+		return nil, err
+	}
 	return []byte(formatted), nil
 }
 
@@ -84,7 +106,9 @@ func (h *SDKHandler) GetSandbox(ctx context.Context, name string) ([]byte, error
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
-	sandbox, err := h.sdkClient.GetSandboxWithResponse(ctx, name)
+	sandbox, err := client.Fetch(ctx, h.cache, client.ResourceSandboxes, "get", name, func() (*sdk.GetSandboxResponse, error) {
+		return h.sdkClient.GetSandboxWithResponse(ctx, name)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sandbox: %w", err)
 	}
@@ -159,6 +183,21 @@ func (h *SDKHandler) CreateSandbox(ctx context.Context, name, image string, memo
 		sandboxData.Spec.Runtime.Envs = tools.SetRuntimeEnv(env)
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckAvailable("sandbox", name, func() (int, error) {
+			resp, err := h.sdkClient.GetSandboxWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Sandbox '%s' would be created", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodPost, Path: "/sandboxes", Body: sandboxData})
+	}
+
 	// Create sandbox
 	sandbox, err := h.sdkClient.CreateSandboxWithResponse(ctx, sandboxData)
 	if err != nil {
@@ -172,6 +211,8 @@ func (h *SDKHandler) CreateSandbox(ctx context.Context, name, image string, memo
 		return nil, fmt.Errorf("failed to create sandbox with status %d", sandbox.StatusCode())
 	}
 
+	h.cache.Invalidate(client.ResourceSandboxes, name)
+
 	result := map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Sandbox '%s' created successfully", name),
@@ -198,11 +239,28 @@ func (h *SDKHandler) DeleteSandbox(ctx context.Context, name string) ([]byte, er
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := tools.CheckExists("sandbox", name, func() (int, error) {
+			resp, err := h.sdkClient.GetSandboxWithResponse(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+			return resp.StatusCode(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Sandbox '%s' would be deleted", name), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/sandboxes/" + name})
+	}
+
 	_, err := h.sdkClient.DeleteSandboxWithResponse(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete sandbox: %w", err)
 	}
 
+	h.cache.Invalidate(client.ResourceSandboxes, name)
+
 	result := map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Sandbox '%s' deleted successfully", name),
@@ -0,0 +1,173 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceTypes are the resource types that can be scaled
+var ResourceTypes = []string{"agent", "function", "job"}
+
+// Allowed values of the runtime settings
+var (
+	MemoryTiers = []int{512, 1024, 2048, 4096, 8192, 16384, 32768} // MB
+	Generations = []string{"mk2", "mk3"}
+)
+
+// Bounds of the runtime settings
+const (
+	MaxReplicas        = 100
+	MaxConcurrentTasks = 1000
+	MaxTimeoutSeconds  = 86400
+)
+
+// Settings are the runtime settings changed by scale_resource, the fields of
+// Spec.Runtime. Unset fields are kept as they are.
+type Settings struct {
+	Memory             *int    `json:"memory,omitempty"`
+	Generation         *string `json:"generation,omitempty"`
+	MaxConcurrentTasks *int    `json:"maxConcurrentTasks,omitempty"`
+	MinReplicas        *int    `json:"minReplicas,omitempty"`
+	MaxReplicas        *int    `json:"maxReplicas,omitempty"`
+	Timeout            *int    `json:"timeout,omitempty"` // seconds
+}
+
+// IsEmpty reports whether no setting is set
+func (s Settings) IsEmpty() bool {
+	return s == Settings{}
+}
+
+// Validate checks the settings against the allowed tiers and bounds
+func (s Settings) Validate(resourceType string) error {
+	if s.Memory != nil && !slices.Contains(MemoryTiers, *s.Memory) {
+		return fmt.Errorf("memory must be one of %s MB", joinInts(MemoryTiers))
+	}
+	if s.Generation != nil && !slices.Contains(Generations, *s.Generation) {
+		return fmt.Errorf("generation must be one of %s", strings.Join(Generations, ", "))
+	}
+	if s.MaxConcurrentTasks != nil && (*s.MaxConcurrentTasks < 1 || *s.MaxConcurrentTasks > MaxConcurrentTasks) {
+		return fmt.Errorf("maxConcurrentTasks must be between 1 and %d", MaxConcurrentTasks)
+	}
+	if resourceType == "job" && (s.MinReplicas != nil || s.MaxReplicas != nil) {
+		return fmt.Errorf("jobs have no replicas; use maxConcurrentTasks to run more tasks at once")
+	}
+	if s.MinReplicas != nil && (*s.MinReplicas < 0 || *s.MinReplicas > MaxReplicas) {
+		return fmt.Errorf("minReplicas must be between 0 and %d", MaxReplicas)
+	}
+	if s.MaxReplicas != nil && (*s.MaxReplicas < 1 || *s.MaxReplicas > MaxReplicas) {
+		return fmt.Errorf("maxReplicas must be between 1 and %d", MaxReplicas)
+	}
+	if s.MinReplicas != nil && s.MaxReplicas != nil && *s.MinReplicas > *s.MaxReplicas {
+		return fmt.Errorf("minReplicas (%d) must not exceed maxReplicas (%d)", *s.MinReplicas, *s.MaxReplicas)
+	}
+	if s.Timeout != nil && (*s.Timeout < 1 || *s.Timeout > MaxTimeoutSeconds) {
+		return fmt.Errorf("timeout must be between 1 and %d seconds", MaxTimeoutSeconds)
+	}
+	return nil
+}
+
+// ScaleHandler defines the interface for scaling operations
+type ScaleHandler interface {
+	ScaleResource(ctx context.Context, resourceType, name string, settings Settings, waitForCompletion string) ([]byte, error)
+}
+
+// ScaleHandlerWithReadOnly extends ScaleHandler with readonly capability
+type ScaleHandlerWithReadOnly interface {
+	ScaleHandler
+	IsReadOnly() bool
+}
+
+// RegisterScaleTools registers scaling tools with the given handler
+func RegisterScaleTools(s tools.ToolRegistrar, handler ScaleHandler) {
+	// Check if handler supports readonly mode
+	readOnlyHandler, hasReadOnly := handler.(ScaleHandlerWithReadOnly)
+	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
+
+	// Scaling modifies the workspace
+	if isReadOnly {
+		return
+	}
+
+	// Scale resource tool
+	scaleResourceTool := mcp.NewTool("scale_resource",
+		mcp.WithDescription("Change the memory, generation, max concurrent tasks, min/max replicas and timeout of an agent, MCP server (function) or job in one call, and redeploy it. Settings not passed are kept. Returns the runtime settings before and after."),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of the resource"),
+			mcp.Enum(ResourceTypes...),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the resource"),
+		),
+		mcp.WithNumber("memory",
+			mcp.Description(fmt.Sprintf("Memory in MB, one of %s", joinInts(MemoryTiers))),
+		),
+		mcp.WithString("generation",
+			mcp.Description("Runtime generation"),
+			mcp.Enum(Generations...),
+		),
+		mcp.WithNumber("maxConcurrentTasks",
+			mcp.Description(fmt.Sprintf("Maximum tasks handled at the same time by one replica (1 to %d)", MaxConcurrentTasks)),
+		),
+		mcp.WithNumber("minReplicas",
+			mcp.Description(fmt.Sprintf("Minimum number of replicas, 0 to scale to zero (agents and MCP servers, up to %d)", MaxReplicas)),
+		),
+		mcp.WithNumber("maxReplicas",
+			mcp.Description(fmt.Sprintf("Maximum number of replicas (agents and MCP servers, up to %d)", MaxReplicas)),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description(fmt.Sprintf("Request or task timeout in seconds (1 to %d)", MaxTimeoutSeconds)),
+		),
+		mcp.WithString("waitForCompletion",
+			mcp.Description("Wait for the redeployment to complete (default: true)"),
+		),
+	)
+
+	s.AddTool(scaleResourceTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		type ScaleResourceArgs struct {
+			ResourceType      string `json:"resourceType"`
+			Name              string `json:"name"`
+			WaitForCompletion string `json:"waitForCompletion"`
+			Settings
+		}
+
+		var args ScaleResourceArgs
+		if err := request.BindArguments(&args); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid arguments: %v", err)), nil
+		}
+
+		if !slices.Contains(ResourceTypes, args.ResourceType) {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown resource type '%s' (expected one of %s)", args.ResourceType, strings.Join(ResourceTypes, ", "))), nil
+		}
+		if args.Name == "" {
+			return mcp.NewToolResultError("resource name is required"), nil
+		}
+		if args.Settings.IsEmpty() {
+			return mcp.NewToolResultError("at least one setting is required: memory, generation, maxConcurrentTasks, minReplicas, maxReplicas or timeout"), nil
+		}
+		if err := args.Settings.Validate(args.ResourceType); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := handler.ScaleResource(ctx, args.ResourceType, args.Name, args.Settings, args.WaitForCompletion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, ", ")
+}
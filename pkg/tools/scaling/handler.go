@@ -0,0 +1,189 @@
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/logger"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+// SDKScaleHandler implements ScaleHandler using the SDK client
+type SDKScaleHandler struct {
+	sdkClient *sdk.ClientWithResponses
+	cache     *client.ResponseCache
+	readOnly  bool
+}
+
+// NewSDKScaleHandler creates a new SDK-based scaling handler
+func NewSDKScaleHandler(sdkClient *sdk.ClientWithResponses, cache *client.ResponseCache, readOnly bool) ScaleHandler {
+	return &SDKScaleHandler{
+		sdkClient: sdkClient,
+		cache:     cache,
+		readOnly:  readOnly,
+	}
+}
+
+// ScaleResource implements ScaleHandler.ScaleResource
+func (h *SDKScaleHandler) ScaleResource(ctx context.Context, resourceType, name string, settings Settings, waitForCompletion string) ([]byte, error) {
+	if h.sdkClient == nil {
+		return nil, fmt.Errorf("SDK client not initialized")
+	}
+
+	switch resourceType {
+	case "agent":
+		return scale(ctx, h, client.AgentOps(ctx, h.sdkClient, name), name, settings, waitForCompletion)
+	case "function":
+		return scale(ctx, h, client.FunctionOps(ctx, h.sdkClient, name), name, settings, waitForCompletion)
+	case "job":
+		return scale(ctx, h, client.JobOps(ctx, h.sdkClient, name), name, settings, waitForCompletion)
+	}
+
+	return nil, fmt.Errorf("unknown resource type '%s' (expected one of %s)", resourceType, strings.Join(ResourceTypes, ", "))
+}
+
+// IsReadOnly implements ScaleHandlerWithReadOnly.IsReadOnly
+func (h *SDKScaleHandler) IsReadOnly() bool {
+	return h.readOnly
+}
+
+// scale applies settings to the runtime of a resource, updates it and waits
+// for it to redeploy
+func scale[T any](ctx context.Context, h *SDKScaleHandler, ops client.ResourceOps[T], name string, settings Settings, waitForCompletion string) ([]byte, error) {
+	// Read the resource, bypassing the cache, so that the other settings are kept
+	item, err := ops.Read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	runtime := ops.RuntimeOf(item)
+	before := settingsOf(runtime)
+	after := merge(before, settings)
+
+	// A single bound may conflict with the current value of the other
+	if after.MinReplicas != nil && after.MaxReplicas != nil && *after.MinReplicas > *after.MaxReplicas {
+		return nil, fmt.Errorf("minReplicas (%d) would exceed maxReplicas (%d)", *after.MinReplicas, *after.MaxReplicas)
+	}
+
+	changes := diff(before, after)
+	result := map[string]interface{}{
+		"success":  true,
+		"resource": ops.Kind,
+		"name":     name,
+		"before":   before,
+		"after":    after,
+		"changes":  changes,
+	}
+
+	if len(changes) == 0 {
+		result["message"] = fmt.Sprintf("Runtime settings of %s '%s' are already up to date", ops.Kind, name)
+		return tools.MarshalResult(result)
+	}
+
+	apply(runtime, after)
+
+	if tools.IsDryRun(ctx) {
+		return tools.DryRun(fmt.Sprintf("%s '%s' would be scaled and redeployed: %s", capitalize(ops.Kind), name, strings.Join(changes, ", ")),
+			[]string{fmt.Sprintf("%s '%s' exists", ops.Kind, name), "settings are within the allowed tiers"},
+			tools.PlannedRequest{Method: http.MethodPut, Path: ops.Path, Body: item})
+	}
+
+	if err := ops.Write(h.cache, name, *item); err != nil {
+		return nil, err
+	}
+
+	waited, err := ops.Wait(ctx, name, waitForCompletion)
+	switch {
+	case err != nil:
+		logger.Printf("Warning: %s scaled but status check failed: %v", ops.Kind, err)
+		result["message"] = fmt.Sprintf("%s '%s' scaled (status check failed: %v)", capitalize(ops.Kind), name, err)
+	case waited:
+		result["message"] = fmt.Sprintf("%s '%s' scaled and redeployed successfully", capitalize(ops.Kind), name)
+	default:
+		result["message"] = fmt.Sprintf("%s '%s' scaled successfully; it is redeploying", capitalize(ops.Kind), name)
+	}
+
+	return tools.MarshalResult(result)
+}
+
+// settingsOf reads the scaling settings of a runtime
+func settingsOf(runtime *sdk.Runtime) Settings {
+	return Settings{
+		Memory:             runtime.Memory,
+		Generation:         runtime.Generation,
+		MaxConcurrentTasks: runtime.MaxConcurrentTasks,
+		MinReplicas:        runtime.MinScale,
+		MaxReplicas:        runtime.MaxScale,
+		Timeout:            runtime.Timeout,
+	}
+}
+
+// apply writes the scaling settings into a runtime
+func apply(runtime *sdk.Runtime, settings Settings) {
+	runtime.Memory = settings.Memory
+	runtime.Generation = settings.Generation
+	runtime.MaxConcurrentTasks = settings.MaxConcurrentTasks
+	runtime.MinScale = settings.MinReplicas
+	runtime.MaxScale = settings.MaxReplicas
+	runtime.Timeout = settings.Timeout
+}
+
+// merge returns the current settings with the ones that were passed
+func merge(current, changes Settings) Settings {
+	merged := current
+	if changes.Memory != nil {
+		merged.Memory = changes.Memory
+	}
+	if changes.Generation != nil {
+		merged.Generation = changes.Generation
+	}
+	if changes.MaxConcurrentTasks != nil {
+		merged.MaxConcurrentTasks = changes.MaxConcurrentTasks
+	}
+	if changes.MinReplicas != nil {
+		merged.MinReplicas = changes.MinReplicas
+	}
+	if changes.MaxReplicas != nil {
+		merged.MaxReplicas = changes.MaxReplicas
+	}
+	if changes.Timeout != nil {
+		merged.Timeout = changes.Timeout
+	}
+	return merged
+}
+
+// diff describes the changed settings, in the order of Spec.Runtime
+func diff(before, after Settings) []string {
+	changes := []string{}
+	add := func(field, old, new string) {
+		if old != new {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", field, old, new))
+		}
+	}
+	add("memory", show(before.Memory), show(after.Memory))
+	add("generation", show(before.Generation), show(after.Generation))
+	add("maxConcurrentTasks", show(before.MaxConcurrentTasks), show(after.MaxConcurrentTasks))
+	add("minReplicas", show(before.MinReplicas), show(after.MinReplicas))
+	add("maxReplicas", show(before.MaxReplicas), show(after.MaxReplicas))
+	add("timeout", show(before.Timeout), show(after.Timeout))
+	return changes
+}
+
+// show formats an optional setting
+func show[V int | string](value *V) string {
+	if value == nil {
+		return "(default)"
+	}
+	return fmt.Sprint(*value)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,98 @@
+package scaling
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client/clienttest"
+	"github.com/blaxel-ai/toolkit/sdk"
+)
+
+func intPtr(v int) *int { return &v }
+
+func fakeJob(runtime *sdk.Runtime) (client.ResourceOps[sdk.Job], *clienttest.Resource[sdk.Job]) {
+	job := clienttest.New(sdk.Job{Spec: &sdk.JobSpec{Runtime: runtime}})
+	return job.Ops(client.JobOps(context.Background(), nil, "nightly")), job
+}
+
+func TestScale(t *testing.T) {
+	ops, job := fakeJob(&sdk.Runtime{Memory: intPtr(2048), Timeout: intPtr(60)})
+
+	data, err := scale(context.Background(), &SDKScaleHandler{}, ops, "nightly", Settings{Memory: intPtr(4096), MaxConcurrentTasks: intPtr(5)}, "false")
+	if err != nil {
+		t.Fatalf("scale() error = %v", err)
+	}
+
+	var result struct {
+		Before  Settings `json:"before"`
+		After   Settings `json:"after"`
+		Changes []string `json:"changes"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("invalid result: %v", err)
+	}
+
+	want := []string{"memory: 2048 -> 4096", "maxConcurrentTasks: (default) -> 5"}
+	if len(result.Changes) != len(want) || result.Changes[0] != want[0] || result.Changes[1] != want[1] {
+		t.Errorf("changes = %v, want %v", result.Changes, want)
+	}
+	if *result.Before.Memory != 2048 || *result.After.Memory != 4096 || *result.After.Timeout != 60 {
+		t.Errorf("before = %+v, after = %+v", result.Before, result.After)
+	}
+
+	if len(job.Updates) != 1 {
+		t.Fatalf("expected one update, got %d", len(job.Updates))
+	}
+	runtime := job.Updates[0].Spec.Runtime
+	if *runtime.Memory != 4096 || *runtime.MaxConcurrentTasks != 5 || *runtime.Timeout != 60 {
+		t.Errorf("updated runtime = %+v", runtime)
+	}
+}
+
+func TestScaleUpToDate(t *testing.T) {
+	ops, job := fakeJob(&sdk.Runtime{Memory: intPtr(2048)})
+
+	if _, err := scale(context.Background(), &SDKScaleHandler{}, ops, "nightly", Settings{Memory: intPtr(2048)}, "false"); err != nil {
+		t.Fatalf("scale() error = %v", err)
+	}
+	if len(job.Updates) != 0 {
+		t.Errorf("an up-to-date resource should not be updated")
+	}
+}
+
+func TestScaleReplicaBoundsAgainstCurrent(t *testing.T) {
+	ops, job := fakeJob(&sdk.Runtime{MaxScale: intPtr(2)})
+
+	if _, err := scale(context.Background(), &SDKScaleHandler{}, ops, "nightly", Settings{MinReplicas: intPtr(5)}, "false"); err == nil {
+		t.Error("minReplicas above the current maxReplicas should be refused")
+	}
+	if len(job.Updates) != 0 {
+		t.Errorf("a refused change should not be written")
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		settings     Settings
+		valid        bool
+	}{
+		{"memory tier", "agent", Settings{Memory: intPtr(4096)}, true},
+		{"memory off tier", "agent", Settings{Memory: intPtr(3000)}, false},
+		{"scale to zero", "function", Settings{MinReplicas: intPtr(0)}, true},
+		{"replicas on a job", "job", Settings{MaxReplicas: intPtr(2)}, false},
+		{"min above max", "agent", Settings{MinReplicas: intPtr(3), MaxReplicas: intPtr(2)}, false},
+		{"timeout bound", "job", Settings{Timeout: intPtr(MaxTimeoutSeconds + 1)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.Validate(tt.resourceType); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid=%v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package scaling
+
+import (
+	"fmt"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
+)
+
+// RegisterTools registers all scaling tools using SDK client
+func RegisterTools(s tools.ToolRegistrar, cfg *config.Config) {
+	// Initialize SDK client
+	sdkClient, err := client.NewSDKClient(cfg)
+	if err != nil {
+		// Log error but continue - tools will return errors when called
+		fmt.Printf("Warning: Failed to initialize SDK client: %v\n", err)
+	}
+
+	// Create SDK-based handler
+	handler := NewSDKScaleHandler(sdkClient, client.SharedCache(cfg), cfg.ReadOnly)
+
+	// Register tools using shared definitions
+	RegisterScaleTools(s, handler)
+}
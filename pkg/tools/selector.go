@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/selector"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Per-call arguments filtering the items of list tools
+const (
+	LabelSelectorArg = "labelSelector"
+	StatusArg        = "status"
+)
+
+// selectableTools are the list tools of labeled resources, and whether their
+// items have a status to filter on
+var selectableTools = map[string]bool{
+	"list_agents":       true,
+	"list_model_apis":   true,
+	"list_mcp_servers":  true,
+	"list_sandboxes":    true,
+	"list_jobs":         true,
+	"list_integrations": false,
+}
+
+// SupportsSelector reports whether a tool filters its items by label selector
+func SupportsSelector(name string) bool {
+	_, ok := selectableTools[name]
+	return ok
+}
+
+// withSelector adds the labelSelector argument, and the status argument for
+// resources that have one, to a tool's input schema
+func withSelector(tool mcp.Tool) mcp.Tool {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[LabelSelectorArg] = map[string]any{
+		"type":        "string",
+		"description": "Label selector, e.g. 'env=prod,team in (ml,search),!deprecated'. Supports =, !=, in, notin, key (set) and !key (not set).",
+	}
+	if selectableTools[tool.Name] {
+		tool.InputSchema.Properties[StatusArg] = map[string]any{
+			"type":        "string",
+			"description": "Only items with one of these comma-separated statuses, e.g. 'DEPLOYED' or 'FAILED,DEPLOYING'",
+		}
+	}
+	return tool
+}
+
+// SelectorMiddleware reads the labelSelector and status arguments of list
+// tools into the context; handlers apply them with selector.Apply
+func SelectorMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		hasStatus, ok := selectableTools[request.Params.Name]
+		if !ok {
+			return next(ctx, request)
+		}
+
+		labels, err := selector.Parse(request.GetString(LabelSelectorArg, ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		filter := selector.Filter{Labels: labels}
+		if hasStatus {
+			filter.Statuses = config.ParseList(request.GetString(StatusArg, ""))
+		}
+
+		if filter.Empty() {
+			return next(ctx, request)
+		}
+		return next(selector.WithFilter(ctx, filter), request)
+	}
+}
@@ -3,8 +3,8 @@ package serviceaccounts
 import (
 	"context"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // ServiceAccountHandler defines the interface for service account operations
@@ -23,7 +23,7 @@ type ServiceAccountHandlerWithReadOnly interface {
 }
 
 // RegisterServiceAccountTools registers service account tools with the given handler
-func RegisterServiceAccountTools(s *server.MCPServer, handler ServiceAccountHandler) {
+func RegisterServiceAccountTools(s tools.ToolRegistrar, handler ServiceAccountHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(ServiceAccountHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
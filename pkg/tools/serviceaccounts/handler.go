@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/toolkit/sdk"
 )
@@ -47,6 +49,56 @@ func (h *SDKHandler) ListServiceAccounts(ctx context.Context, filter string) ([]
 		return nil, fmt.Errorf("no service accounts found")
 	}
 
+	// Sort by and project on SDK fields when the caller asked to
+	sorted, err := formatter.SortItems(ctx, *serviceAccounts.JSON200)
+	if err != nil {
+		return nil, err
+	}
+	matched := sorted[:0:0]
+	for _, account := range sorted {
+		if filter == "" || (account.Name != nil && tools.ContainsString(*account.Name, filter)) {
+			matched = append(matched, account)
+		}
+	}
+	if projected, done, err := formatter.ProjectItems(ctx, matched); err != nil {
+		return nil, err
+	} else if done {
+		return projected, nil
+	}
+
+	// Other formats are rendered from simple models
+	if format := formatter.FormatFromContext(ctx); format != formatter.FormatText {
+		var models []formatter.ServiceAccountModel
+		for _, account := range *serviceAccounts.JSON200 {
+			if filter != "" && (account.Name == nil || !tools.ContainsString(*account.Name, filter)) {
+				continue
+			}
+
+			model := formatter.ServiceAccountModel{}
+			if account.Name != nil {
+				model.Name = *account.Name
+			}
+			if account.ClientId != nil {
+				model.ClientID = *account.ClientId
+			}
+			if account.Description != nil {
+				model.Description = *account.Description
+			}
+			if account.CreatedAt != nil {
+				if createdAt, err := time.Parse(time.RFC3339, *account.CreatedAt); err == nil {
+					model.CreatedAt = &createdAt
+				}
+			}
+			models = append(models, model)
+		}
+
+		formatted, err := formatter.Render(format, models, formatter.ServiceAccountColumns, formatter.FormatServiceAccounts)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(formatted), nil
+	}
+
 	// Convert service accounts for formatting
 	var formattedResult strings.Builder
 	formattedResult.WriteString(fmt.Sprintf("Found %d service account(s):\n\n", len(*serviceAccounts.JSON200)))
@@ -129,6 +181,11 @@ func (h *SDKHandler) CreateServiceAccount(ctx context.Context, name string) ([]b
 		Name: name,
 	}
 
+	if tools.IsDryRun(ctx) {
+		return tools.DryRun(fmt.Sprintf("Service account '%s' would be created", name), nil,
+			tools.PlannedRequest{Method: http.MethodPost, Path: "/service_accounts", Body: serviceAccountData})
+	}
+
 	account, err := h.sdkClient.CreateWorkspaceServiceAccountWithResponse(ctx, serviceAccountData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create service account: %w", err)
@@ -151,7 +208,7 @@ func (h *SDKHandler) CreateServiceAccount(ctx context.Context, name string) ([]b
 		result["service_account"].(map[string]interface{})["client_id"] = *account.JSON200.ClientId
 		if account.JSON200.ClientSecret != nil {
 			result["service_account"].(map[string]interface{})["client_secret"] = *account.JSON200.ClientSecret
-			result["message"] = fmt.Sprintf("Service account '%s' created successfully. Save the client_secret as it won't be shown again.", name)
+			result["message"] = fmt.Sprintf("Service account '%s' created successfully. Save the client_secret as it won't be shown again (it is redacted unless the server allows revealSecrets).", name)
 		}
 	}
 
@@ -169,6 +226,15 @@ func (h *SDKHandler) DeleteServiceAccount(ctx context.Context, clientID string)
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := h.checkServiceAccount(ctx, clientID)
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Service account with client ID '%s' would be deleted", clientID), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/service_accounts/" + clientID})
+	}
+
 	_, err := h.sdkClient.DeleteWorkspaceServiceAccountWithResponse(ctx, clientID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete service account: %w", err)
@@ -198,6 +264,15 @@ func (h *SDKHandler) UpdateServiceAccount(ctx context.Context, clientID, descrip
 		Description: &description,
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := h.checkServiceAccount(ctx, clientID)
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Service account '%s' would be updated", clientID), []string{check},
+			tools.PlannedRequest{Method: http.MethodPut, Path: "/service_accounts/" + clientID, Body: updateData})
+	}
+
 	// Update the service account
 	resp, err := h.sdkClient.UpdateWorkspaceServiceAccountWithResponse(ctx, clientID, updateData)
 	if err != nil {
@@ -225,6 +300,25 @@ func (h *SDKHandler) UpdateServiceAccount(ctx context.Context, clientID, descrip
 	return jsonData, nil
 }
 
+// checkServiceAccount verifies that a service account exists for a dry run.
+// The API has no get endpoint, so the accounts are listed.
+func (h *SDKHandler) checkServiceAccount(ctx context.Context, clientID string) (string, error) {
+	serviceAccounts, err := h.sdkClient.GetWorkspaceServiceAccountsWithResponse(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list service accounts: %w", err)
+	}
+
+	if serviceAccounts.JSON200 != nil {
+		for _, account := range *serviceAccounts.JSON200 {
+			if account.ClientId != nil && *account.ClientId == clientID {
+				return fmt.Sprintf("service account '%s' exists", clientID), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("service account with client ID '%s' not found", clientID)
+}
+
 // IsReadOnly implements ServiceAccountHandlerWithReadOnly.IsReadOnly
 func (h *SDKHandler) IsReadOnly() bool {
 	return h.readOnly
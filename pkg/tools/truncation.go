@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/truncate"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MaxOutputCharsArg is the per-call argument capping the length of a result
+const MaxOutputCharsArg = "maxOutputChars"
+
+// ReadOutputTool is the tool reading back the full text of shortened results.
+// Its results are bounded by its own length argument and never shortened.
+const ReadOutputTool = "read_output"
+
+// withMaxOutputChars adds the maxOutputChars argument to a tool's input schema
+func withMaxOutputChars(tool mcp.Tool) mcp.Tool {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[MaxOutputCharsArg] = map[string]any{
+		"type":        "number",
+		"description": "Shorten the result to at most this many characters, not counting the note explaining how to read the rest; the full result can then be read with read_output (0 for no limit)",
+	}
+	return tool
+}
+
+// TruncationMiddleware shortens results longer than maxOutputChars, from the
+// per-call argument or the server default. The full text is kept in store and
+// a note tells the caller how to read the rest.
+func TruncationMiddleware(defaultMax int, store *truncate.Store) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Name == ReadOutputTool {
+				return next(ctx, request)
+			}
+
+			max := request.GetInt(MaxOutputCharsArg, defaultMax)
+			if max < 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("%s cannot be negative", MaxOutputCharsArg)), nil
+			}
+
+			result, err := next(ctx, request)
+			if err != nil || result == nil || max == 0 {
+				return result, err
+			}
+
+			var notes []mcp.Content
+			for i, content := range result.Content {
+				text, ok := content.(mcp.TextContent)
+				if !ok {
+					continue
+				}
+				shortened, ok := truncate.Shorten(text.Text, max)
+				if !ok {
+					continue
+				}
+
+				id := store.Put(text.Text)
+				notes = append(notes, mcp.NewTextContent(fmt.Sprintf(
+					"[Output shortened from %d to %d characters. The full output is kept as '%s': call %s with id '%s' and an offset to read it in ranges, or narrow the call (fields, filters, a smaller limit).]",
+					utf8.RuneCountInString(text.Text), utf8.RuneCountInString(shortened), id, ReadOutputTool, id,
+				)))
+				text.Text = shortened
+				result.Content[i] = text
+			}
+			result.Content = append(result.Content, notes...)
+
+			return result, nil
+		}
+	}
+}
@@ -3,8 +3,8 @@ package users
 import (
 	"context"
 
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // UserHandler defines the interface for user operations
@@ -23,7 +23,7 @@ type UserHandlerWithReadOnly interface {
 }
 
 // RegisterUserTools registers user tools with the given handler
-func RegisterUserTools(s *server.MCPServer, handler UserHandler) {
+func RegisterUserTools(s tools.ToolRegistrar, handler UserHandler) {
 	// Check if handler supports readonly mode
 	readOnlyHandler, hasReadOnly := handler.(UserHandlerWithReadOnly)
 	isReadOnly := hasReadOnly && readOnlyHandler.IsReadOnly()
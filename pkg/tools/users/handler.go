@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/client"
 	"github.com/blaxel-ai/blaxel-mcp-server/pkg/config"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/formatter"
+	"github.com/blaxel-ai/blaxel-mcp-server/pkg/tools"
 	"github.com/blaxel-ai/toolkit/sdk"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
@@ -54,8 +57,16 @@ func (h *SDKHandler) ListUsers(ctx context.Context, filter string) ([]byte, erro
 		return jsonData, nil
 	}
 
+	// Sort by SDK fields when the caller asked to
+	sorted, err := formatter.SortItems(ctx, *users.JSON200)
+	if err != nil {
+		return nil, err
+	}
+
 	var userList []map[string]interface{}
-	for _, user := range *users.JSON200 {
+	var userModels []formatter.UserModel
+	matched := sorted[:0:0]
+	for _, user := range sorted {
 		userInfo := make(map[string]interface{})
 
 		if user.Email != nil {
@@ -114,6 +125,41 @@ func (h *SDKHandler) ListUsers(ctx context.Context, filter string) ([]byte, erro
 		}
 
 		userList = append(userList, userInfo)
+		matched = append(matched, user)
+
+		model := formatter.UserModel{}
+		if user.Email != nil {
+			model.Email = *user.Email
+		}
+		if name, ok := userInfo["name"].(string); ok {
+			model.Name = name
+		}
+		if user.Role != nil {
+			model.Role = *user.Role
+		}
+		if user.Accepted != nil {
+			model.Accepted = *user.Accepted
+		}
+		if user.EmailVerified != nil {
+			model.EmailVerified = *user.EmailVerified
+		}
+		userModels = append(userModels, model)
+	}
+
+	// Project the matching users on SDK fields when the caller asked to
+	if projected, done, err := formatter.ProjectItems(ctx, matched); err != nil {
+		return nil, err
+	} else if done {
+		return projected, nil
+	}
+
+	// Other formats are rendered from the user models
+	if format := formatter.FormatFromContext(ctx); format != formatter.FormatText {
+		formatted, err := formatter.Render(format, userModels, formatter.UserColumns, formatter.FormatUsers)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(formatted), nil
 	}
 
 	result := map[string]interface{}{
@@ -205,6 +251,19 @@ func (h *SDKHandler) InviteUser(ctx context.Context, email, role string) ([]byte
 		Email: &emailType,
 	}
 
+	if tools.IsDryRun(ctx) {
+		found, err := h.findUser(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return nil, fmt.Errorf("user '%s' is already in the workspace or has a pending invitation", email)
+		}
+		return tools.DryRun(fmt.Sprintf("User '%s' would be invited to the workspace", email),
+			[]string{fmt.Sprintf("user '%s' is not in the workspace yet", email)},
+			tools.PlannedRequest{Method: http.MethodPost, Path: "/users", Body: inviteData})
+	}
+
 	resp, err := h.sdkClient.InviteWorkspaceUserWithResponse(ctx, inviteData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to invite user: %w", err)
@@ -249,6 +308,15 @@ func (h *SDKHandler) UpdateUserRole(ctx context.Context, email, role string) ([]
 		Role: role,
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := h.checkUser(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("Role of user '%s' would be set to '%s'", email, role), []string{check},
+			tools.PlannedRequest{Method: http.MethodPut, Path: "/users/" + email, Body: updateData})
+	}
+
 	// The API expects either sub or email as the identifier
 	resp, err := h.sdkClient.UpdateWorkspaceUserRoleWithResponse(ctx, email, updateData)
 	if err != nil {
@@ -283,6 +351,15 @@ func (h *SDKHandler) RemoveUser(ctx context.Context, email string) ([]byte, erro
 		return nil, fmt.Errorf("SDK client not initialized")
 	}
 
+	if tools.IsDryRun(ctx) {
+		check, err := h.checkUser(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		return tools.DryRun(fmt.Sprintf("User '%s' would be removed from the workspace", email), []string{check},
+			tools.PlannedRequest{Method: http.MethodDelete, Path: "/users/" + email})
+	}
+
 	// The API expects either sub or email as the identifier
 	resp, err := h.sdkClient.RemoveWorkspaceUserWithResponse(ctx, email)
 	if err != nil {
@@ -311,6 +388,40 @@ func (h *SDKHandler) RemoveUser(ctx context.Context, email string) ([]byte, erro
 	return nil, fmt.Errorf("failed to remove user with status %d", resp.StatusCode())
 }
 
+// findUser reports whether a user with the given email or sub is in the workspace
+func (h *SDKHandler) findUser(ctx context.Context, identifier string) (bool, error) {
+	users, err := h.sdkClient.ListWorkspaceUsersWithResponse(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to list workspace users: %w", err)
+	}
+
+	if users.JSON200 == nil {
+		return false, nil
+	}
+
+	for _, user := range *users.JSON200 {
+		if user.Email != nil && strings.EqualFold(*user.Email, identifier) {
+			return true, nil
+		}
+		if user.Sub != nil && *user.Sub == identifier {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkUser verifies that a user is in the workspace for a dry run
+func (h *SDKHandler) checkUser(ctx context.Context, identifier string) (string, error) {
+	found, err := h.findUser(ctx, identifier)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("user '%s' not found in workspace", identifier)
+	}
+	return fmt.Sprintf("user '%s' is in the workspace", identifier), nil
+}
+
 // IsReadOnly implements UserHandlerWithReadOnly.IsReadOnly
 func (h *SDKHandler) IsReadOnly() bool {
 	return h.readOnly
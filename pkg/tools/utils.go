@@ -2,6 +2,8 @@ package tools
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -58,3 +60,31 @@ func SetRuntimeEnv(env string) *[]interface{} {
 	}
 	return &envMap
 }
+
+// RuntimeEnvs converts environment variables to the runtime envs of the SDK,
+// sorted by name so that updates are stable
+func RuntimeEnvs(envs map[string]string) *[]interface{} {
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	envList := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		envList = append(envList, map[string]interface{}{
+			"name":  name,
+			"value": envs[name],
+		})
+	}
+	return &envList
+}
+
+// MarshalResult formats a tool result as indented JSON
+func MarshalResult(result map[string]interface{}) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	return jsonData, nil
+}
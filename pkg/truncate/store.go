@@ -0,0 +1,91 @@
+package truncate
+
+import (
+	"fmt"
+	"sync"
+)
+
+// storeCapacity is how many full outputs are kept; the oldest is dropped first
+const storeCapacity = 32
+
+// Store keeps the full text of shortened outputs so that they can be read
+// back in ranges
+type Store struct {
+	mu      sync.Mutex
+	next    int
+	order   []string
+	outputs map[string][]rune
+}
+
+// Chunk is a range of a stored output
+type Chunk struct {
+	ID         string `json:"id"`
+	Offset     int    `json:"offset"`
+	Length     int    `json:"length"`
+	Total      int    `json:"total"`
+	NextOffset *int   `json:"nextOffset,omitempty"`
+	Text       string `json:"text"`
+}
+
+var defaultStore = NewStore()
+
+// Default returns the store shared by the truncation middleware and read_output
+func Default() *Store {
+	return defaultStore
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{outputs: make(map[string][]rune)}
+}
+
+// Put keeps an output and returns its id
+func (s *Store) Put(text string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	id := fmt.Sprintf("output-%d", s.next)
+	s.outputs[id] = []rune(text)
+	s.order = append(s.order, id)
+
+	if len(s.order) > storeCapacity {
+		delete(s.outputs, s.order[0])
+		s.order = s.order[1:]
+	}
+	return id
+}
+
+// Read returns up to length characters of an output, starting at offset
+func (s *Store) Read(id string, offset, length int) (*Chunk, error) {
+	s.mu.Lock()
+	runes, ok := s.outputs[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("output '%s' not found; only the last %d shortened outputs are kept", id, storeCapacity)
+	}
+
+	if offset < 0 || offset > len(runes) {
+		return nil, fmt.Errorf("offset %d is out of range (output '%s' has %d characters)", offset, id, len(runes))
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("length must be positive")
+	}
+
+	end := offset + length
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	chunk := &Chunk{
+		ID:     id,
+		Offset: offset,
+		Length: end - offset,
+		Total:  len(runes),
+		Text:   string(runes[offset:end]),
+	}
+	if end < len(runes) {
+		chunk.NextOffset = &end
+	}
+	return chunk, nil
+}
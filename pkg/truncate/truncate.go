@@ -0,0 +1,167 @@
+package truncate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// minItems and minChars are the smallest array and string sizes JSON is
+	// shrunk to before falling back to cutting the text
+	minItems = 1
+	minChars = 40
+)
+
+// Shorten cuts text down to at most max characters and reports whether it
+// did. JSON stays valid JSON: arrays keep their first items followed by a
+// marker counting the others, and long strings lose their middle. Other text
+// loses its middle, so that both its beginning and its end remain. The note
+// the caller adds about the shortening is not counted.
+func Shorten(text string, max int) (string, bool) {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text, false
+	}
+
+	if shortened, ok := shortenJSON(text, max); ok {
+		return shortened, true
+	}
+	return Elide(text, max), true
+}
+
+// Elide replaces the middle of s with a marker so that it fits in max
+// characters. When max leaves no room for the marker and a character on each
+// side, s is cut to its first max characters instead.
+func Elide(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	if max <= 0 {
+		return ""
+	}
+
+	// The marker length depends on the count it reports; size it for the worst case
+	marker := fmt.Sprintf(" ... [%d characters elided] ... ", len(runes))
+	keep := max - utf8.RuneCountInString(marker)
+	if keep < 2 {
+		return string(runes[:max])
+	}
+	head := (keep + 1) / 2
+	tail := keep - head
+
+	marker = fmt.Sprintf(" ... [%d characters elided] ... ", len(runes)-head-tail)
+	return string(runes[:head]) + marker + string(runes[len(runes)-tail:])
+}
+
+// shortenJSON shrinks the arrays and strings of a JSON document until it fits,
+// and returns false when the text is not JSON or cannot be made to fit
+func shortenJSON(text string, max int) (string, bool) {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return "", false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	items, chars := sizes(value)
+	for {
+		items = shrinkLimit(items, minItems)
+		chars = shrinkLimit(chars, minChars)
+
+		data, err := encode(shrink(value, items, chars))
+		if err != nil {
+			return "", false
+		}
+		if utf8.RuneCount(data) <= max {
+			return string(data), true
+		}
+		if items == minItems && chars == minChars {
+			return "", false
+		}
+	}
+}
+
+// shrinkLimit lowers a limit by a quarter, never below min
+func shrinkLimit(limit, min int) int {
+	limit = limit * 3 / 4
+	if limit < min {
+		return min
+	}
+	return limit
+}
+
+// sizes returns the length of the longest array and of the longest string in a value
+func sizes(value interface{}) (items, chars int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			i, c := sizes(item)
+			items, chars = maxInt(items, i), maxInt(chars, c)
+		}
+	case []interface{}:
+		items = len(v)
+		for _, item := range v {
+			i, c := sizes(item)
+			items, chars = maxInt(items, i), maxInt(chars, c)
+		}
+	case string:
+		chars = utf8.RuneCountInString(v)
+	}
+	return items, chars
+}
+
+// shrink returns a copy of value with arrays cut to items elements plus a
+// marker, and strings elided to chars characters
+func shrink(value interface{}, items, chars int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		shrunk := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			shrunk[key] = shrink(item, items, chars)
+		}
+		return shrunk
+	case []interface{}:
+		kept := v
+		if len(v) > items {
+			kept = v[:items]
+		}
+		shrunk := make([]interface{}, 0, len(kept)+1)
+		for _, item := range kept {
+			shrunk = append(shrunk, shrink(item, items, chars))
+		}
+		if len(v) > items {
+			shrunk = append(shrunk, fmt.Sprintf("... %d more items (%d in total)", len(v)-items, len(v)))
+		}
+		return shrunk
+	case string:
+		return Elide(v, chars)
+	}
+	return value
+}
+
+// encode formats a value as indented JSON without escaping HTML characters
+func encode(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
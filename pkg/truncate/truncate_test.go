@@ -0,0 +1,146 @@
+package truncate
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestElideBound(t *testing.T) {
+	text := strings.Repeat("é", 100)
+
+	for max := 0; max <= 101; max++ {
+		got := Elide(text, max)
+		if n := utf8.RuneCountInString(got); n > max && max < 100 {
+			t.Errorf("Elide(%d) = %d characters", max, n)
+		}
+		if max >= 100 && got != text {
+			t.Errorf("Elide(%d) should keep text that fits", max)
+		}
+	}
+
+	if got := Elide(text, 5); got != "ééééé" {
+		t.Errorf("Elide() below the marker size = %q, want a hard cut", got)
+	}
+	if got := Elide(text, 60); !strings.Contains(got, "characters elided") || !strings.HasPrefix(got, "é") || !strings.HasSuffix(got, "é") {
+		t.Errorf("Elide() = %q, want the marker between both ends", got)
+	}
+}
+
+func TestShortenBound(t *testing.T) {
+	inputs := []string{
+		strings.Repeat("log line\n", 200),
+		`{"items": [` + strings.Repeat(`{"name": "agent", "status": "DEPLOYED"},`, 50) + `{"name": "last"}]}`,
+		`["` + strings.Repeat("x", 5000) + `"]`,
+	}
+
+	for _, input := range inputs {
+		for _, max := range []int{1, 10, 39, 40, 80, 200, 1000} {
+			got, ok := Shorten(input, max)
+			if !ok {
+				t.Errorf("Shorten(%d) should shorten %d characters", max, len(input))
+			}
+			if n := utf8.RuneCountInString(got); n > max {
+				t.Errorf("Shorten(%.20q, %d) = %d characters", input, max, n)
+			}
+		}
+	}
+}
+
+func TestShorten(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		max       int
+		shortened bool
+		contains  []string
+	}{
+		{"fits", "short", 10, false, []string{"short"}},
+		{"no limit", strings.Repeat("x", 100), 0, false, nil},
+		{"text keeps both ends", "BEGIN" + strings.Repeat("x", 200) + "END", 100, true, []string{"BEGIN", "END", "characters elided"}},
+		{"json array keeps first items", `[` + strings.Repeat(`"item",`, 99) + `"item"]`, 100, true, []string{`"item"`, "more items (100 in total)"}},
+		{"json string loses its middle", `{"log": "` + strings.Repeat("y", 500) + `"}`, 150, true, []string{`"log"`, "characters elided"}},
+		{"invalid json is text", `{"unterminated": ` + strings.Repeat("z", 200), 100, true, []string{`{"unterminated"`, "characters elided"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Shorten(tt.text, tt.max)
+			if ok != tt.shortened {
+				t.Fatalf("Shorten() shortened = %v, want %v", ok, tt.shortened)
+			}
+			if !ok && got != tt.text {
+				t.Errorf("Shorten() changed text it did not shorten")
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Shorten() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestShortenJSONStaysValid(t *testing.T) {
+	text := `{"agents": [` + strings.Repeat(`{"name": "a", "description": "`+strings.Repeat("d", 300)+`"},`, 20) + `{"name": "last"}]}`
+	got, ok := Shorten(text, 800)
+	if !ok {
+		t.Fatal("Shorten() should shorten")
+	}
+	if _, valid := shortenJSON(got, len(got)); !valid {
+		t.Errorf("Shorten() = %q, want valid JSON", got)
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	id := store.Put("héllo world")
+
+	tests := []struct {
+		name   string
+		offset int
+		length int
+		text   string
+		next   int // 0 when there is no next offset
+		err    bool
+	}{
+		{"first range", 0, 5, "héllo", 5, false},
+		{"last range", 6, 100, "world", 0, false},
+		{"at the end", 11, 5, "", 0, false},
+		{"negative offset", -1, 5, "", 0, true},
+		{"past the end", 12, 5, "", 0, true},
+		{"zero length", 0, 0, "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunk, err := store.Read(id, tt.offset, tt.length)
+			if tt.err {
+				if err == nil {
+					t.Error("Read() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if chunk.Text != tt.text || chunk.Total != 11 {
+				t.Errorf("Read() = %+v", chunk)
+			}
+			if (chunk.NextOffset == nil) != (tt.next == 0) || (chunk.NextOffset != nil && *chunk.NextOffset != tt.next) {
+				t.Errorf("NextOffset = %v, want %d", chunk.NextOffset, tt.next)
+			}
+		})
+	}
+}
+
+func TestStoreCapacity(t *testing.T) {
+	store := NewStore()
+	first := store.Put("first")
+	for i := 0; i < storeCapacity; i++ {
+		store.Put("other")
+	}
+	if _, err := store.Read(first, 0, 1); err == nil {
+		t.Error("the oldest output should be dropped")
+	}
+}